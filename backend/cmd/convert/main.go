@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/taldoflemis/brain.test/config"
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/media"
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/misc"
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/postgres"
+	"github.com/taldoflemis/brain.test/internal/adapters/drivers/interchange"
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+)
+
+// convert turns question banks from one format into another. Given -owner,
+// it reads or writes the games of that user instead: -game with -to exports
+// a stored game, -from imports into the game given by -game, or into a new
+// one titled -title when there is none.
+func main() {
+	from := flag.String("from", "", "format of the input, gift, aiken or moodle_xml")
+	to := flag.String("to", "", "format of the output, gift, aiken or moodle_xml")
+	in := flag.String("in", "", "file to read, standard input when empty")
+	out := flag.String("out", "", "file to write, standard output when empty")
+	owner := flag.String("owner", "", "id of the user owning the games read or written")
+	gameId := flag.String("game", "", "id of the game to export or import into")
+	title := flag.String("title", "", "title of the game created by an import")
+	draft := flag.Bool("draft", false, "create the imported game as a draft")
+	flag.Parse()
+
+	switch {
+	case *owner == "" && *from != "" && *to != "":
+		questions := importQuestions(*from, *in)
+		writeQuestions(*to, *out, questions)
+	case *owner != "" && *gameId != "" && *to != "" && *from == "":
+		found, err := newGameService().GetGameById(context.Background(), *owner, parseGameId(*gameId))
+		if err != nil {
+			log.Fatal(err)
+		}
+		writeQuestions(*to, *out, found.Questions)
+	case *owner != "" && *from != "" && *to == "" && (*gameId != "" || *title != ""):
+		questions := importQuestions(*from, *in)
+		id := importGame(newGameService(), *owner, *gameId, *title, *draft, questions)
+		log.Printf("imported %d questions into game %s", len(questions), id)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func parseGameId(id string) uuid.UUID {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return parsed
+}
+
+func importQuestions(format string, in string) []game.Question {
+	input := io.Reader(os.Stdin)
+	if in != "" {
+		file, err := os.Open(in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	data, err := io.ReadAll(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	questions, warnings, err := interchange.Import(interchange.Format(format), data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, w := range warnings {
+		log.Printf("reading: %s", w)
+	}
+
+	return questions
+}
+
+func writeQuestions(format string, out string, questions []game.Question) {
+	data, warnings, err := interchange.Export(interchange.Format(format), questions)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, w := range warnings {
+		log.Printf("writing: %s", w)
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(data)
+	} else {
+		err = os.WriteFile(out, data, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// importGame appends the questions to the game of owner with the given id, or
+// creates a new game of theirs with them when there is no id. It returns the
+// id of the game.
+func importGame(
+	gameService *services.GameService,
+	owner string,
+	gameId string,
+	title string,
+	draft bool,
+	questions []game.Question,
+) uuid.UUID {
+	ctx := context.Background()
+
+	if gameId == "" {
+		newGame := &game.Game{Title: title, Questions: questions}
+
+		var err error
+		if draft {
+			err = gameService.CreateDraftGame(ctx, owner, newGame)
+		} else {
+			err = gameService.CreateNewGame(ctx, owner, newGame)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return newGame.Id
+	}
+
+	id := parseGameId(gameId)
+	found, err := gameService.GetGameById(ctx, owner, id)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, err = gameService.UpdateGameQuestions(ctx, owner, id, &services.UpdateGameQuestionsRequest{
+		Questions: append(found.Questions, questions...),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return id
+}
+
+func newGameService() *services.GameService {
+	koanf := config.NewKoanfson()
+	err := koanf.LoadFromJSON("config.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = koanf.LoadFromEnv("BRAIN_")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pgCfg, err := config.NewPostgresConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+	mediaCfg, err := config.NewMediaConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal(err)
+	}
+	zapLoggerAdapter := misc.NewZapLogger(logger.Sugar())
+
+	pool, err := postgres.NewPool(postgres.GenerateConnectionString(pgCfg))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mediaStorer, err := media.NewMediaStorer(*mediaCfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return services.NewGameService(
+		zapLoggerAdapter,
+		services.NewValidationService(),
+		postgres.NewPostgresGameStorer(pool),
+		postgres.NewPostgresRevisionStorer(pool),
+		mediaStorer,
+	)
+}
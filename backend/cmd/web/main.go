@@ -6,11 +6,14 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/taldoflemis/brain.test/config"
 	"github.com/taldoflemis/brain.test/internal/adapters/driven/auth"
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/media"
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/memory"
 	"github.com/taldoflemis/brain.test/internal/adapters/driven/misc"
 	"github.com/taldoflemis/brain.test/internal/adapters/driven/postgres"
 	"github.com/taldoflemis/brain.test/internal/adapters/drivers/web"
@@ -44,6 +47,10 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	mediaCfg, err := config.NewMediaConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Init Drivens
 	logger, err := zap.NewProduction()
@@ -64,13 +71,45 @@ func main() {
 
 	gameStorer := postgres.NewPostgresGameStorer(pool)
 	localIDPStorer := postgres.NewLocalIDPPostgresStorer(pool)
+	sessionStorer := memory.NewInMemorySessionStorer()
+	resultStorer := postgres.NewPostgresResultStorer(pool)
+	revisionStorer := postgres.NewPostgresRevisionStorer(pool)
+	bankStorer := postgres.NewPostgresBankStorer(pool)
+	mediaStorer, err := media.NewMediaStorer(*mediaCfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	localIDP := auth.NewLocalIdp(*localIDPCfg, zapLoggerAdapter, localIDPStorer)
 
 	// Init Services
 	validationService := services.NewValidationService()
 	authService := services.NewAuthenticationService(zapLoggerAdapter, localIDP, validationService)
-	gameService := services.NewGameService(zapLoggerAdapter, validationService, gameStorer)
+	gameService := services.NewGameService(
+		zapLoggerAdapter,
+		validationService,
+		gameStorer,
+		revisionStorer,
+		mediaStorer,
+	)
+	bankService := services.NewBankService(
+		zapLoggerAdapter,
+		validationService,
+		bankStorer,
+		gameService,
+	)
+	mediaService := services.NewMediaService(zapLoggerAdapter, mediaStorer)
+	scoringService := services.NewScoringService()
+	sessionHub := web.NewSessionHub()
+	sessionService := services.NewSessionService(
+		zapLoggerAdapter,
+		validationService,
+		scoringService,
+		gameStorer,
+		sessionStorer,
+		resultStorer,
+		sessionHub,
+	)
 
 	// Init Drivers
 	handlers := make([]web.Handler, 0)
@@ -82,6 +121,34 @@ func main() {
 	gameHandler := web.NewGameHandler(jwtMiddleware, validationService, gameService)
 	handlers = append(handlers, gameHandler)
 
+	bankHandler := web.NewBankHandler(jwtMiddleware, validationService, bankService)
+	handlers = append(handlers, bankHandler)
+
+	mediaHandler := web.NewMediaHandler(jwtMiddleware, mediaService)
+	handlers = append(handlers, mediaHandler)
+
+	sessionHandler := web.NewSessionHandler(
+		jwtMiddleware,
+		validationService,
+		sessionService,
+		sessionHub,
+	)
+	handlers = append(handlers, sessionHandler)
+
+	go func() {
+		ticker := time.NewTicker(services.MediaSweepInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			err := gameService.SweepMedia(
+				context.Background(),
+				time.Now().Add(-services.MediaGracePeriod),
+			)
+			if err != nil {
+				sugar.Errorf("Failed to sweep media %v", err)
+			}
+		}
+	}()
+
 	router := web.NewRouter(*fiberCfg, logger, handlers)
 	err = router.Serve()
 	if err != nil {
@@ -0,0 +1,36 @@
+package config
+
+import "github.com/taldoflemis/brain.test/internal/adapters/driven/media"
+
+type mediaConfig struct {
+	Driver string `koanf:"driver"`
+	Local  struct {
+		Dir string `koanf:"dir"`
+	} `koanf:"local"`
+	S3 struct {
+		Endpoint  string `koanf:"endpoint"`
+		Region    string `koanf:"region"`
+		Bucket    string `koanf:"bucket"`
+		AccessKey string `koanf:"access_key"`
+		SecretKey string `koanf:"secret_key"`
+	} `koanf:"s3"`
+}
+
+func NewMediaConfig() (*media.Config, error) {
+	var out mediaConfig
+	err := k.Unmarshal("media", &out)
+	if err != nil {
+		return nil, err
+	}
+	return &media.Config{
+		Driver:   out.Driver,
+		LocalDir: out.Local.Dir,
+		S3: media.S3Config{
+			Endpoint:  out.S3.Endpoint,
+			Region:    out.S3.Region,
+			Bucket:    out.S3.Bucket,
+			AccessKey: out.S3.AccessKey,
+			SecretKey: out.S3.SecretKey,
+		},
+	}, nil
+}
@@ -253,18 +253,116 @@ const docTemplate = `{
                 }
             }
         },
-        "/game/": {
+        "/bank/": {
             "get": {
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Game"
+                    "Bank"
+                ],
+                "summary": "Search the questions of the bank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text the title must contain",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags the questions must all have",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Kinds the questions may be of",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category of the questions",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "easy, medium or hard",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Questions per page, at most 50",
+                        "name": "page_size",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get games by user id",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.BankQuestionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bank"
+                ],
+                "summary": "Save a question to the bank",
+                "parameters": [
+                    {
+                        "description": "Question",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.SaveBankQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.BankQuestionResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -285,23 +383,101 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/bank/from-game/{gameId}": {
             "post": {
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Game"
+                    "Bank"
                 ],
-                "summary": "Create a Game",
+                "summary": "Save every question of a Game to the bank",
                 "parameters": [
                     {
-                        "description": "Create Game Request",
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags of the saved questions",
+                        "name": "req",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/web.SaveGameToBankRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.BankQuestionResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/bank/game": {
+            "post": {
+                "description": "Creates a Game out of copies of questions of the bank, either given by id or picked at random, e.g. 10 random questions tagged photosynthesis",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bank"
+                ],
+                "summary": "Create a Game from questions of the bank",
+                "parameters": [
+                    {
+                        "description": "Game and its questions",
                         "name": "req",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/web.CreateGameRequest"
+                            "$ref": "#/definitions/web.CreateGameFromBankRequest"
                         }
                     }
                 ],
@@ -321,6 +497,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -330,77 +518,2157 @@ const docTemplate = `{
                 }
             }
         },
-        "/game/:id": {
-            "get": {
+        "/bank/game/{gameId}": {
+            "post": {
+                "description": "Appends copies of questions of the bank to a Game, later changes to the bank don't affect them",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Game"
+                    "Bank"
+                ],
+                "summary": "Add questions of the bank to a Game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Questions to add",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.BankSelectionRequest"
+                        }
+                    }
                 ],
-                "summary": "Get games by id",
                 "responses": {
                     "200": {
                         "description": "OK"
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "web.CreateGameRequest": {
-            "description": "Request to create a Game",
-            "type": "object",
-            "required": [
-                "questions",
-                "title"
-            ],
-            "properties": {
-                "description": {
-                    "description": "the description of a game",
-                    "type": "string"
-                },
-                "questions": {
-                    "description": "questions of the game",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/web.CreateQuestionRequest"
+        },
+        "/bank/{questionId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bank"
+                ],
+                "summary": "Get a question of the bank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Question id",
+                        "name": "questionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.BankQuestionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
-                },
-                "title": {
-                    "description": "the title of a game",
-                    "type": "string"
                 }
-            }
-        },
-        "web.CreateQuestionRequest": {
-            "description": "a way to create questions dynamically",
-            "type": "object",
-            "required": [
-                "data",
-                "kind"
-            ],
-            "properties": {
-                "data": {
-                    "description": "data",
-                    "type": "object",
-                    "additionalProperties": {}
-                },
-                "kind": {
-                    "description": "kind",
-                    "type": "string"
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bank"
+                ],
+                "summary": "Replace a question of the bank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Question id",
+                        "name": "questionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Question",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.SaveBankQuestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.BankQuestionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Bank"
+                ],
+                "summary": "Delete a question of the bank",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Question id",
+                        "name": "questionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Get games by user id",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Skip loading the questions of each game",
+                        "name": "summary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text the title must contain",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags the games must all have",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category of the games",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "easy, medium or hard",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "created, updated or title, defaults to updated",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc, defaults to desc for times and asc for titles",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page before the one wanted",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page after the one wanted",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Games per page, at most 50",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.GamesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Create a Game",
+                "parameters": [
+                    {
+                        "description": "Create Game Request",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.CreateGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/:id": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Get games by id",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/import": {
+            "post": {
+                "description": "Creates a new Game from an export, sent as JSON or as YAML with a YAML content type",
+                "consumes": [
+                    "application/json",
+                    "application/yaml"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Import a Game",
+                "parameters": [
+                    {
+                        "description": "Exported Game",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.GameBundle"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/import/spreadsheet": {
+            "post": {
+                "description": "Creates a Game from a CSV or XLSX spreadsheet with a header row and one question per row. The columns are question, kind (quiz or true_false, defaults to quiz), time_limit, points, alternative_1 to alternative_6 and correct, which lists the correct alternatives by number or letter, e.g. \"1, 3\" or \"A C\".",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Import a Game from a spreadsheet",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX spreadsheet",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title of the game",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of the game",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only report the problems of the spreadsheet",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.SpreadsheetImportResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.SpreadsheetImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.SpreadsheetImportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/import/{format}": {
+            "post": {
+                "description": "Creates a Game from the multiple choice, true or false and short answer questions of a question bank. Whatever the format has that can't be represented is reported in warnings. Imported questions are worth 1 point and last 30 seconds unless the format says otherwise.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Import a Game from GIFT, Aiken or Moodle XML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "gift, aiken or moodle_xml",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Question bank",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title of the game",
+                        "name": "title",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Description of the game",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create the game as a draft, to be completed before publishing",
+                        "name": "draft",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.QuestionsImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/public": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "List the public catalogue of games",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Text the title or description must contain",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Tags the games must all have",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category of the games",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "easy, medium or hard",
+                        "name": "difficulty",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "recent or plays, defaults to recent",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Games per page, at most 50",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.PublicGamesResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/public/{gameId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Get an unlisted or public game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/tags": {
+            "get": {
+                "description": "Suggests tags already used on the games, questions or bank of the user, the most used first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Autocomplete a tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the tag, ignoring case",
+                        "name": "prefix",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.TagSuggestionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{gameId}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Replace a Game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Game Request",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.CreateGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Delete a Game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Change part of a Game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch Game Request",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.PatchGameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{gameId}/copy": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Copy a Game into a new one owned by the caller",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{gameId}/export": {
+            "get": {
+                "description": "Exports a Game with all its questions, to be imported back later or into another instance",
+                "produces": [
+                    "application/json",
+                    "application/yaml",
+                    "text/plain",
+                    "text/xml"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Export a Game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json or yaml, defaults to json, or gift, aiken or moodle_xml to only export the questions",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Embed the content of the media the questions reference",
+                        "name": "include_media",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.GameBundle"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{gameId}/publish": {
+            "post": {
+                "description": "Validates the game and freezes it as the version sessions play",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Publish the current version of a Game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{gameId}/revisions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "List the revisions of a Game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.RevisionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{gameId}/revisions/{number}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Get a Game as it was at a past revision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/game/{gameId}/revisions/{number}/restore": {
+            "post": {
+                "description": "The restored version is a draft until the game is published again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Game"
+                ],
+                "summary": "Restore a past revision of a Game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Game id",
+                        "name": "gameId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/": {
+            "post": {
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Media"
+                ],
+                "summary": "Upload an image, audio or video to show along questions",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "PNG, JPEG, GIF or WebP image up to 5MB, MP3 or WAV audio up to 10MB, MP4 or WebM video up to 50MB",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.MediaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/media/{mediaId}": {
+            "get": {
+                "tags": [
+                    "Media"
+                ],
+                "summary": "Get the content of an uploaded media",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Media id",
+                        "name": "mediaId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Start a hosted session of a game",
+                "parameters": [
+                    {
+                        "description": "Start Session Request",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.StartSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.StartSessionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{pin}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Get the public state of a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session pin",
+                        "name": "pin",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.SessionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Close a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session pin",
+                        "name": "pin",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{pin}/host": {
+            "get": {
+                "description": "Receives every session message and accepts the next, skip and end controls",
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Host websocket of a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session pin",
+                        "name": "pin",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT when the Authorization header can't be set",
+                        "name": "access_token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "426": {
+                        "description": "Upgrade Required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{pin}/join": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Join a session lobby",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session pin",
+                        "name": "pin",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Join Session Request",
+                        "name": "req",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.JoinSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.JoinSessionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/web.ValidationErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{pin}/next": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Advance a session to its next question",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session pin",
+                        "name": "pin",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.SessionResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/session/{pin}/play": {
+            "get": {
+                "description": "Players send a join message first, then answer messages",
+                "tags": [
+                    "Session"
+                ],
+                "summary": "Player websocket of a session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session pin",
+                        "name": "pin",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of a player that already joined",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    },
+                    "426": {
+                        "description": "Upgrade Required",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "web.BankQuestionResponse": {
+            "description": "A question of the bank",
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "description": "the question, as in games",
+                    "type": "object"
+                },
+                "id": {
+                    "description": "id of the question in the bank",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "kind of the question",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "tags, lowercase and sorted",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.BankQuestionsResponse": {
+            "description": "A page of the questions of the bank",
+            "type": "object",
+            "properties": {
+                "page": {
+                    "description": "the page, starting at 1",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "the maximum amount of questions in a page",
+                    "type": "integer"
+                },
+                "questions": {
+                    "description": "questions in the page, newest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.BankQuestionResponse"
+                    }
+                },
+                "total": {
+                    "description": "amount of questions matching the search across all pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.BankSelectionRequest": {
+            "description": "Questions of the bank, either by id or picked at random among the ones matching a search",
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "category": {
+                    "description": "category the questions picked at random must be of",
+                    "type": "string",
+                    "maxLength": 60
+                },
+                "count": {
+                    "description": "how many questions to pick at random when no ids are given",
+                    "type": "integer",
+                    "maximum": 50,
+                    "minimum": 0
+                },
+                "difficulty": {
+                    "description": "easy, medium or hard, the difficulty of the questions picked at random",
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "kinds": {
+                    "description": "kinds the questions picked at random may be of, any when empty",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "question_ids": {
+                    "description": "ids of the questions, in the order they are added",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "search": {
+                    "description": "text the title of the questions picked at random must contain",
+                    "type": "string",
+                    "maxLength": 120
+                },
+                "tags": {
+                    "description": "tags every question picked at random must have",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "web.CreateGameFromBankRequest": {
+            "description": "Request to create a Game out of copies of questions of the bank",
+            "type": "object",
+            "required": [
+                "selection",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "description": "the description of a game",
+                    "type": "string"
+                },
+                "draft": {
+                    "description": "save the game as an incomplete draft instead of publishing it",
+                    "type": "boolean"
+                },
+                "selection": {
+                    "description": "questions of the game",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.BankSelectionRequest"
+                        }
+                    ]
+                },
+                "title": {
+                    "description": "the title of a game",
+                    "type": "string"
+                }
+            }
+        },
+        "web.CreateGameRequest": {
+            "description": "Request to create a Game",
+            "type": "object",
+            "required": [
+                "questions",
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "category": {
+                    "description": "the subject, e.g. biology, ignoring case",
+                    "type": "string",
+                    "maxLength": 60
+                },
+                "description": {
+                    "description": "the description of a game",
+                    "type": "string"
+                },
+                "difficulty": {
+                    "description": "easy, medium or hard",
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "draft": {
+                    "description": "save the game as an incomplete draft instead of publishing it",
+                    "type": "boolean"
+                },
+                "questions": {
+                    "description": "questions of the game, drafts may have none",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.CreateQuestionRequest"
+                    }
+                },
+                "scoring": {
+                    "description": "how answers are scored, the default policy is used when omitted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.ScoringPolicyRequest"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "free-form tags, e.g. photosynthesis, ignoring case",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "the title of a game",
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "one of private, unlisted or public, defaults to private",
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ]
+                }
+            }
+        },
+        "web.CreateQuestionRequest": {
+            "description": "a way to create questions dynamically",
+            "type": "object",
+            "required": [
+                "data",
+                "kind"
+            ],
+            "properties": {
+                "data": {
+                    "description": "data",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "kind": {
+                    "description": "kind",
+                    "type": "string"
+                }
+            }
+        },
+        "web.GameBundle": {
+            "description": "A Game exported to be backed up or moved to another instance",
+            "type": "object",
+            "required": [
+                "format",
+                "game",
+                "version"
+            ],
+            "properties": {
+                "exported_at": {
+                    "description": "when the game was exported",
+                    "type": "string"
+                },
+                "format": {
+                    "description": "always brain.test/game",
+                    "type": "string"
+                },
+                "game": {
+                    "description": "the game, without its questions",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.GameBundleInfo"
+                        }
+                    ]
+                },
+                "media": {
+                    "description": "content of the media the questions reference, when exported along",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.GameBundleMedia"
+                    }
+                },
+                "questions": {
+                    "description": "questions of the game in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.GameBundleQuestion"
+                    }
+                },
+                "version": {
+                    "description": "version of the bundle format",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.GameBundleInfo": {
+            "description": "The info of an exported Game",
+            "type": "object",
+            "required": [
+                "tags",
+                "title"
+            ],
+            "properties": {
+                "category": {
+                    "description": "the subject, e.g. biology, ignoring case",
+                    "type": "string",
+                    "maxLength": 60
+                },
+                "description": {
+                    "description": "the description of a game",
+                    "type": "string"
+                },
+                "difficulty": {
+                    "description": "easy, medium or hard",
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "scoring": {
+                    "description": "how answers are scored, the default policy is used when omitted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.ScoringPolicyRequest"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "drafts are imported as drafts, published games are published again",
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published"
+                    ]
+                },
+                "tags": {
+                    "description": "free-form tags, e.g. photosynthesis, ignoring case",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "the title of a game",
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "one of private, unlisted or public, defaults to private",
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ]
+                }
+            }
+        },
+        "web.GameBundleMedia": {
+            "description": "An exported media, uploaded again on import",
+            "type": "object",
+            "required": [
+                "content",
+                "id"
+            ],
+            "properties": {
+                "content": {
+                    "description": "the file encoded in base64",
+                    "type": "string",
+                    "format": "base64"
+                },
+                "content_type": {
+                    "description": "the content type of the file",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "the id questions of the bundle reference the media by",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "image, audio or video",
+                    "type": "string",
+                    "enum": [
+                        "image",
+                        "audio",
+                        "video"
+                    ]
+                },
+                "size": {
+                    "description": "size of the file in bytes",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "web.GameBundleQuestion": {
+            "description": "An exported question, its data has every field of its kind",
+            "type": "object",
+            "required": [
+                "data",
+                "kind"
+            ],
+            "properties": {
+                "data": {
+                    "description": "data",
+                    "type": "object"
+                },
+                "kind": {
+                    "description": "kind",
+                    "type": "string"
+                }
+            }
+        },
+        "web.GamesResponse": {
+            "description": "A page of the games of the user",
+            "type": "object",
+            "properties": {
+                "games": {
+                    "description": "games in the page",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "next": {
+                    "description": "cursor of the next page, empty on the last one",
+                    "type": "string"
+                },
+                "prev": {
+                    "description": "cursor of the previous page, empty on the first one",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "amount of games matching the search and filters across all pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.JoinSessionRequest": {
+            "description": "Request to join a session lobby",
+            "type": "object",
+            "required": [
+                "nickname"
+            ],
+            "properties": {
+                "nickname": {
+                    "description": "the nickname shown to the other players",
+                    "type": "string"
+                }
+            }
+        },
+        "web.JoinSessionResponse": {
+            "description": "The player created when joining a session",
+            "type": "object",
+            "properties": {
+                "nickname": {
+                    "description": "the nickname of the player",
+                    "type": "string"
+                },
+                "player_id": {
+                    "description": "the player id",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "token that authenticates the player on the session websocket",
+                    "type": "string"
                 }
             }
         },
@@ -422,6 +2690,189 @@ const docTemplate = `{
                 }
             }
         },
+        "web.MediaResponse": {
+            "description": "An uploaded image, audio or video questions may reference",
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "description": "the content type detected from the file",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "the id to reference the media by",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "image, audio or video",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "size of the file in bytes",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.PatchGameRequest": {
+            "description": "Request to change part of a Game, omitted fields are kept",
+            "type": "object",
+            "required": [
+                "questions",
+                "tags"
+            ],
+            "properties": {
+                "category": {
+                    "description": "the subject, e.g. biology",
+                    "type": "string",
+                    "maxLength": 60
+                },
+                "description": {
+                    "description": "the description of a game",
+                    "type": "string"
+                },
+                "difficulty": {
+                    "description": "easy, medium or hard",
+                    "type": "string",
+                    "enum": [
+                        "easy",
+                        "medium",
+                        "hard"
+                    ]
+                },
+                "questions": {
+                    "description": "questions of the game, replacing all the current ones",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.CreateQuestionRequest"
+                    }
+                },
+                "scoring": {
+                    "description": "how answers are scored",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/web.ScoringPolicyRequest"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "free-form tags, replacing all the current ones",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "the title of a game",
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "one of private, unlisted or public",
+                    "type": "string",
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ]
+                }
+            }
+        },
+        "web.PlayerResponse": {
+            "description": "A player in a session",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "the player id",
+                    "type": "string"
+                },
+                "nickname": {
+                    "description": "the nickname of the player",
+                    "type": "string"
+                }
+            }
+        },
+        "web.PublicGameResponse": {
+            "description": "A game of the public catalogue",
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "the subject of the game",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "the description of the game",
+                    "type": "string"
+                },
+                "difficulty": {
+                    "description": "easy, medium or hard, empty when unset",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "the id of the game",
+                    "type": "string"
+                },
+                "play_count": {
+                    "description": "amount of times the game was played until the end",
+                    "type": "integer"
+                },
+                "question_count": {
+                    "description": "amount of questions in the game",
+                    "type": "integer"
+                },
+                "tags": {
+                    "description": "tags of the game, lowercase and sorted",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "the title of the game",
+                    "type": "string"
+                }
+            }
+        },
+        "web.PublicGamesResponse": {
+            "description": "A page of the public catalogue",
+            "type": "object",
+            "properties": {
+                "games": {
+                    "description": "games in the page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.PublicGameResponse"
+                    }
+                },
+                "page": {
+                    "description": "the page, starting at 1",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "the maximum amount of games in a page",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "amount of games matching the search across all pages",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.QuestionsImportResponse": {
+            "description": "A Game created from questions written in another tool's format",
+            "type": "object",
+            "properties": {
+                "game": {
+                    "description": "the created game",
+                    "type": "object"
+                },
+                "warnings": {
+                    "description": "what couldn't be converted, e.g. \"question 3: feedback can't be represented, it was left out\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "web.RefreshTokenRequest": {
             "description": "Request of Refresh Token",
             "type": "object",
@@ -457,6 +2908,234 @@ const docTemplate = `{
                 }
             }
         },
+        "web.RevisionResponse": {
+            "description": "A recorded change to a Game",
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "description": "who made the change",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "when the change was made",
+                    "type": "string"
+                },
+                "number": {
+                    "description": "the number of the revision, starting at 1",
+                    "type": "integer"
+                },
+                "summary": {
+                    "description": "what changed since the previous revision",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "web.RevisionsResponse": {
+            "description": "The history of a Game, newest first",
+            "type": "object",
+            "properties": {
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.RevisionResponse"
+                    }
+                }
+            }
+        },
+        "web.SaveBankQuestionRequest": {
+            "description": "A question to save to the bank, with the same kind and data as the questions of a game",
+            "type": "object",
+            "required": [
+                "data",
+                "kind",
+                "tags"
+            ],
+            "properties": {
+                "data": {
+                    "description": "data",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "kind": {
+                    "description": "kind",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "tags to find the question by, e.g. photosynthesis, ignoring case",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "web.SaveGameToBankRequest": {
+            "description": "Request to save every question of a Game to the bank",
+            "type": "object",
+            "required": [
+                "tags"
+            ],
+            "properties": {
+                "tags": {
+                    "description": "tags given to every saved question",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "web.ScoringPolicyRequest": {
+            "description": "How answers to a game are turned into points",
+            "type": "object",
+            "properties": {
+                "base_points": {
+                    "description": "points of a correct answer to a question worth 1 point",
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0
+                },
+                "max_streak_bonus": {
+                    "description": "cap of the streak bonus awarded for a single answer",
+                    "type": "integer",
+                    "maximum": 10000,
+                    "minimum": 0
+                },
+                "speed_weight": {
+                    "description": "percentage of the base points that depends on the answer speed",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "streak_bonus": {
+                    "description": "bonus for every consecutive correct answer after the first",
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0
+                }
+            }
+        },
+        "web.SessionResponse": {
+            "description": "The public state of a session",
+            "type": "object",
+            "properties": {
+                "current_question": {
+                    "description": "index of the current question, -1 when no question is running",
+                    "type": "integer"
+                },
+                "game_id": {
+                    "description": "the id of the game being played",
+                    "type": "string"
+                },
+                "game_title": {
+                    "description": "the title of the game being played",
+                    "type": "string"
+                },
+                "pin": {
+                    "description": "the pin of the session",
+                    "type": "string"
+                },
+                "players": {
+                    "description": "players in the session",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.PlayerResponse"
+                    }
+                },
+                "state": {
+                    "description": "one of lobby, question, results or finished",
+                    "type": "string"
+                },
+                "total_questions": {
+                    "description": "amount of questions in the game",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.SpreadsheetErrorResponse": {
+            "description": "A problem with a cell of an imported spreadsheet",
+            "type": "object",
+            "properties": {
+                "cell": {
+                    "description": "reference of the cell, e.g. C4, empty when the column is missing",
+                    "type": "string"
+                },
+                "column": {
+                    "description": "name of the column in the template",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "what is wrong with the cell",
+                    "type": "string"
+                },
+                "row": {
+                    "description": "number of the row, the header being row 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.SpreadsheetImportResponse": {
+            "description": "The outcome of importing a spreadsheet",
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "problems found, the game is only created when there are none",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.SpreadsheetErrorResponse"
+                    }
+                },
+                "game": {
+                    "description": "the created game, missing on dry runs and when there are errors",
+                    "type": "object"
+                },
+                "questions": {
+                    "description": "number of questions read from the spreadsheet",
+                    "type": "integer"
+                }
+            }
+        },
+        "web.StartSessionRequest": {
+            "description": "Request to start a hosted session of a game",
+            "type": "object",
+            "required": [
+                "game_id"
+            ],
+            "properties": {
+                "game_id": {
+                    "description": "the id of the game to be played",
+                    "type": "string"
+                }
+            }
+        },
+        "web.StartSessionResponse": {
+            "description": "A freshly started session",
+            "type": "object",
+            "properties": {
+                "pin": {
+                    "description": "the pin players use to join the session",
+                    "type": "string"
+                }
+            }
+        },
+        "web.TagSuggestionsResponse": {
+            "description": "Tags already used by the user",
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "description": "at most 10 tags, the most used first",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "web.TokenResponse": {
             "description": "A Token Response",
             "type": "object",
@@ -0,0 +1,162 @@
+package media
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+const (
+	metadataSuffix = ".json"
+)
+
+// LocalMediaStorer keeps every file in a directory, named by its id, along a
+// JSON file describing it.
+type LocalMediaStorer struct {
+	dir string
+}
+
+func NewLocalMediaStorer(dir string) (*LocalMediaStorer, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalMediaStorer{
+		dir: dir,
+	}, nil
+}
+
+func (s *LocalMediaStorer) contentPath(id uuid.UUID) string {
+	return filepath.Join(s.dir, id.String())
+}
+
+func (s *LocalMediaStorer) metadataPath(id uuid.UUID) string {
+	return s.contentPath(id) + metadataSuffix
+}
+
+func (s *LocalMediaStorer) StoreMedia(
+	ctx context.Context,
+	file *ports.MediaFile,
+	content io.Reader,
+) error {
+	// The content is written to a temporary file first so that a failed
+	// upload never leaves a partial file behind
+	tmp, err := os.CreateTemp(s.dir, "upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	metadata, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	// Files are found by their metadata, which is only written once the
+	// content is in place
+	err = os.Rename(tmp.Name(), s.contentPath(file.Id))
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(s.metadataPath(file.Id), metadata, 0o644)
+	if err != nil {
+		os.Remove(s.contentPath(file.Id))
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalMediaStorer) FindMedia(ctx context.Context, id uuid.UUID) (*ports.MediaFile, error) {
+	metadata, err := os.ReadFile(s.metadataPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ports.ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file ports.MediaFile
+	err = json.Unmarshal(metadata, &file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+func (s *LocalMediaStorer) OpenMedia(ctx context.Context, id uuid.UUID) (io.ReadCloser, error) {
+	file, err := os.Open(s.contentPath(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ports.ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *LocalMediaStorer) FindMediaStoredBefore(
+	ctx context.Context,
+	t time.Time,
+) ([]uuid.UUID, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*"+metadataSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []uuid.UUID{}
+	for _, path := range paths {
+		id, err := uuid.Parse(strings.TrimSuffix(filepath.Base(path), metadataSuffix))
+		if err != nil {
+			continue
+		}
+
+		file, err := s.FindMedia(ctx, id)
+		if errors.Is(err, ports.ErrMediaNotFound) {
+			// deleted since the directory was read
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if file.CreatedAt.Before(t) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+func (s *LocalMediaStorer) DeleteMedia(ctx context.Context, id uuid.UUID) error {
+	for _, path := range []string{s.contentPath(id), s.metadataPath(id)} {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package media
+
+import (
+	"fmt"
+
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+const (
+	LocalDriver = "local"
+	S3Driver    = "s3"
+)
+
+type Config struct {
+	// Driver is either local or s3
+	Driver   string
+	LocalDir string
+	S3       S3Config
+}
+
+// NewMediaStorer returns the storer of the configured driver.
+func NewMediaStorer(cfg Config) (ports.MediaStorer, error) {
+	switch cfg.Driver {
+	case LocalDriver:
+		return NewLocalMediaStorer(cfg.LocalDir)
+	case S3Driver:
+		return NewS3MediaStorer(cfg.S3), nil
+	default:
+		return nil, fmt.Errorf("unknown media driver %q", cfg.Driver)
+	}
+}
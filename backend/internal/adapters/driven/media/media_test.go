@@ -0,0 +1,286 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+var (
+	testContent = []byte("\x89PNG\r\n\x1a\nnot really an image")
+	testS3Cfg   = S3Config{
+		Region:    "us-east-1",
+		Bucket:    "media",
+		AccessKey: "access",
+		SecretKey: "secret",
+	}
+)
+
+// fakeS3 is a stand-in for an S3 compatible service, keeping objects in
+// memory and rejecting requests whose signature doesn't match.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+type fakeObject struct {
+	header   http.Header
+	data     []byte
+	modified time.Time
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !f.validSignature(r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2" {
+		f.list(w, r)
+		return
+	}
+
+	object, ok := f.objects[r.URL.Path]
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		header := http.Header{}
+		for name, values := range r.Header {
+			if name == "Content-Type" || strings.HasPrefix(name, "X-Amz-Meta-") {
+				header[name] = values
+			}
+		}
+		f.objects[r.URL.Path] = fakeObject{header: header, data: data, modified: time.Now()}
+	case http.MethodHead, http.MethodGet:
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		for name, values := range object.header {
+			w.Header()[name] = values
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.data)))
+		if r.Method == http.MethodGet {
+			w.Write(object.data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// list answers ListObjectsV2 with every object of the bucket in one page.
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	prefix := strings.TrimSuffix(r.URL.Path, "/") + "/"
+
+	var page s3ListResult
+	for path, object := range f.objects {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+
+		page.Contents = append(page.Contents, struct {
+			Key          string
+			LastModified time.Time
+		}{strings.TrimPrefix(path, prefix), object.modified})
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(page)
+}
+
+func (f *fakeS3) validSignature(r *http.Request) bool {
+	signedAt, err := time.Parse(s3TimeFormat, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return false
+	}
+
+	expected := r.Clone(context.Background())
+	expected.URL.Host = r.Host
+	expected.Header.Del("Authorization")
+	signRequest(expected, testS3Cfg, signedAt)
+
+	return r.Header.Get("Authorization") == expected.Header.Get("Authorization")
+}
+
+type MediaStorerTestSuite struct {
+	suite.Suite
+	ctx       context.Context
+	newStorer func() ports.MediaStorer
+	storer    ports.MediaStorer
+}
+
+func (suite *MediaStorerTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.storer = suite.newStorer()
+}
+
+func TestLocalMediaStorerTestSuite(t *testing.T) {
+	suite.Run(t, &MediaStorerTestSuite{
+		newStorer: func() ports.MediaStorer {
+			storer, err := NewLocalMediaStorer(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return storer
+		},
+	})
+}
+
+func TestS3MediaStorerTestSuite(t *testing.T) {
+	suite.Run(t, &MediaStorerTestSuite{
+		newStorer: func() ports.MediaStorer {
+			server := httptest.NewServer(&fakeS3{objects: make(map[string]fakeObject)})
+			t.Cleanup(server.Close)
+
+			cfg := testS3Cfg
+			cfg.Endpoint = server.URL
+			return NewS3MediaStorer(cfg)
+		},
+	})
+}
+
+func (suite *MediaStorerTestSuite) storeMockedMedia() *ports.MediaFile {
+	file := &ports.MediaFile{
+		Id:          uuid.New(),
+		OwnerId:     uuid.NewString(),
+		Kind:        game.ImageMedia,
+		ContentType: "image/png",
+		Size:        int64(len(testContent)),
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+
+	err := suite.storer.StoreMedia(suite.ctx, file, bytes.NewReader(testContent))
+	suite.Require().NoError(err)
+
+	return file
+}
+
+func (suite *MediaStorerTestSuite) TestStoreMedia() {
+	// Arrange
+	t := suite.T()
+	file := suite.storeMockedMedia()
+
+	// Act
+	found, err := suite.storer.FindMedia(suite.ctx, file.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, file.Id, found.Id)
+	assert.Equal(t, file.OwnerId, found.OwnerId)
+	assert.Equal(t, file.Kind, found.Kind)
+	assert.Equal(t, file.ContentType, found.ContentType)
+	assert.Equal(t, file.Size, found.Size)
+	assert.True(t, file.CreatedAt.Equal(found.CreatedAt))
+}
+
+func (suite *MediaStorerTestSuite) TestOpenMedia() {
+	// Arrange
+	t := suite.T()
+	file := suite.storeMockedMedia()
+
+	// Act
+	content, err := suite.storer.OpenMedia(suite.ctx, file.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	assert.NoError(t, err)
+	assert.Equal(t, testContent, data)
+}
+
+func (suite *MediaStorerTestSuite) TestFindMissingMedia() {
+	// Arrange
+	t := suite.T()
+
+	// Act
+	_, findErr := suite.storer.FindMedia(suite.ctx, uuid.New())
+	_, openErr := suite.storer.OpenMedia(suite.ctx, uuid.New())
+
+	// Assert
+	assert.ErrorIs(t, findErr, ports.ErrMediaNotFound)
+	assert.ErrorIs(t, openErr, ports.ErrMediaNotFound)
+}
+
+func (suite *MediaStorerTestSuite) TestDeleteMedia() {
+	// Arrange
+	t := suite.T()
+	file := suite.storeMockedMedia()
+
+	// Act
+	err := suite.storer.DeleteMedia(suite.ctx, file.Id)
+
+	// Assert
+	assert.NoError(t, err)
+
+	_, err = suite.storer.FindMedia(suite.ctx, file.Id)
+	assert.ErrorIs(t, err, ports.ErrMediaNotFound)
+
+	err = suite.storer.DeleteMedia(suite.ctx, file.Id)
+	assert.NoError(t, err)
+}
+
+func (suite *MediaStorerTestSuite) TestFindMediaStoredBefore() {
+	// Arrange
+	t := suite.T()
+	file := suite.storeMockedMedia()
+
+	// Act
+	later, laterErr := suite.storer.FindMediaStoredBefore(suite.ctx, time.Now().Add(time.Minute))
+	earlier, earlierErr := suite.storer.FindMediaStoredBefore(suite.ctx, time.Now().Add(-time.Hour))
+
+	// Assert
+	assert.NoError(t, laterErr)
+	assert.Equal(t, []uuid.UUID{file.Id}, later)
+	assert.NoError(t, earlierErr)
+	assert.Empty(t, earlier)
+}
+
+func TestS3RejectsWrongCredentials(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(&fakeS3{objects: make(map[string]fakeObject)})
+	defer server.Close()
+
+	cfg := testS3Cfg
+	cfg.Endpoint = server.URL
+	cfg.SecretKey = "wrong"
+	storer := NewS3MediaStorer(cfg)
+
+	// Act
+	err := storer.StoreMedia(context.Background(), &ports.MediaFile{
+		Id:          uuid.New(),
+		Kind:        game.ImageMedia,
+		ContentType: "image/png",
+		Size:        int64(len(testContent)),
+	}, bytes.NewReader(testContent))
+
+	// Assert
+	assert.Error(t, err)
+}
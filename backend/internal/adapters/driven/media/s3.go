@@ -0,0 +1,355 @@
+package media
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+const (
+	s3Service       = "s3"
+	s3Algorithm     = "AWS4-HMAC-SHA256"
+	s3DateFormat    = "20060102"
+	s3TimeFormat    = "20060102T150405Z"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	ownerIdHeader   = "X-Amz-Meta-Owner-Id"
+	kindHeader      = "X-Amz-Meta-Kind"
+	createdAtHeader = "X-Amz-Meta-Created-At"
+)
+
+type S3Config struct {
+	// Endpoint is the base URL of the service, e.g. http://localhost:9000,
+	// buckets being addressed by path
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// S3MediaStorer keeps every file as an object, named by its id, of a bucket
+// of any S3 compatible service.
+type S3MediaStorer struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3MediaStorer(cfg S3Config) *S3MediaStorer {
+	return &S3MediaStorer{
+		cfg:    cfg,
+		client: &http.Client{},
+		now:    time.Now,
+	}
+}
+
+func (s *S3MediaStorer) bucketURL() string {
+	endpoint := strings.TrimSuffix(s.cfg.Endpoint, "/")
+	return fmt.Sprintf("%s/%s", endpoint, s.cfg.Bucket)
+}
+
+func (s *S3MediaStorer) objectURL(id uuid.UUID) string {
+	return fmt.Sprintf("%s/%s", s.bucketURL(), id)
+}
+
+func (s *S3MediaStorer) StoreMedia(
+	ctx context.Context,
+	file *ports.MediaFile,
+	content io.Reader,
+) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(file.Id), content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = file.Size
+	req.Header.Set("Content-Type", file.ContentType)
+	req.Header.Set(ownerIdHeader, file.OwnerId)
+	req.Header.Set(kindHeader, string(file.Kind))
+	req.Header.Set(createdAtHeader, file.CreatedAt.UTC().Format(time.RFC3339))
+
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return s3Error(res)
+	}
+
+	return nil
+}
+
+func (s *S3MediaStorer) FindMedia(ctx context.Context, id uuid.UUID) (*ports.MediaFile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.objectURL(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, ports.ErrMediaNotFound
+	default:
+		return nil, s3Error(res)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, res.Header.Get(createdAtHeader))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ports.MediaFile{
+		Id:          id,
+		OwnerId:     res.Header.Get(ownerIdHeader),
+		Kind:        game.MediaKind(res.Header.Get(kindHeader)),
+		ContentType: res.Header.Get("Content-Type"),
+		Size:        res.ContentLength,
+		CreatedAt:   createdAt,
+	}, nil
+}
+
+func (s *S3MediaStorer) OpenMedia(ctx context.Context, id uuid.UUID) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return res.Body, nil
+	case http.StatusNotFound:
+		res.Body.Close()
+		return nil, ports.ErrMediaNotFound
+	default:
+		defer res.Body.Close()
+		return nil, s3Error(res)
+	}
+}
+
+func (s *S3MediaStorer) DeleteMedia(ctx context.Context, id uuid.UUID) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(id), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return s3Error(res)
+	}
+}
+
+// s3ListResult is the page of objects of a bucket listed by ListObjectsV2.
+type s3ListResult struct {
+	Contents []struct {
+		Key          string
+		LastModified time.Time
+	}
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// FindMediaStoredBefore goes through every object of the bucket, when they
+// were stored being when they were last modified.
+func (s *S3MediaStorer) FindMediaStoredBefore(
+	ctx context.Context,
+	t time.Time,
+) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	token := ""
+
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.bucketURL()+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := s.do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var page s3ListResult
+		if res.StatusCode == http.StatusOK {
+			err = xml.NewDecoder(res.Body).Decode(&page)
+		} else {
+			err = s3Error(res)
+		}
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range page.Contents {
+			id, err := uuid.Parse(object.Key)
+			if err != nil {
+				continue
+			}
+
+			if object.LastModified.Before(t) {
+				ids = append(ids, id)
+			}
+		}
+
+		if !page.IsTruncated {
+			return ids, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (s *S3MediaStorer) do(req *http.Request) (*http.Response, error) {
+	signRequest(req, s.cfg, s.now().UTC())
+	return s.client.Do(req)
+}
+
+func s3Error(res *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("s3: unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// signRequest signs the request with AWS Signature Version 4. The payload is
+// left unsigned so that uploads can be streamed.
+func signRequest(req *http.Request, cfg S3Config, now time.Time) {
+	amzDate := now.Format(s3TimeFormat)
+	date := now.Format(s3DateFormat)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	scope := strings.Join([]string{date, cfg.Region, s3Service, "aws4_request"}, "/")
+	signedHeaders, canonicalRequest := canonicalizeRequest(req)
+
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		amzDate,
+		scope,
+		hexSha256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+cfg.SecretKey), date)
+	key = hmacSha256(key, cfg.Region)
+	key = hmacSha256(key, s3Service)
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm,
+		cfg.AccessKey,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+// canonicalizeRequest returns the headers it signs, the host, the content
+// type and every x-amz header, and the canonical form of the request.
+func canonicalizeRequest(req *http.Request) (string, string) {
+	headers := map[string]string{
+		"host": req.URL.Host,
+	}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		req.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+
+	return signedHeaders, canonicalRequest
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSha256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/session_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+type InMemorySessionStorer struct {
+	mu       sync.Mutex
+	sessions map[string]*session.Session
+}
+
+func NewInMemorySessionStorer() *InMemorySessionStorer {
+	return &InMemorySessionStorer{
+		sessions: make(map[string]*session.Session),
+	}
+}
+
+func (m *InMemorySessionStorer) StoreSession(
+	ctx context.Context,
+	session *session.Session,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[session.Pin]; ok {
+		return ports.ErrPinAlreadyInUse
+	}
+
+	m.sessions[session.Pin] = session.Clone()
+
+	return nil
+}
+
+func (m *InMemorySessionStorer) FindSessionByPin(
+	ctx context.Context,
+	pin string,
+) (*session.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.sessions[pin]
+	if !ok {
+		return nil, ports.ErrSessionNotFound
+	}
+
+	return stored.Clone(), nil
+}
+
+func (m *InMemorySessionStorer) UpdateSession(
+	ctx context.Context,
+	pin string,
+	fn func(session *session.Session) error,
+) (*session.Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.sessions[pin]
+	if !ok {
+		return nil, ports.ErrSessionNotFound
+	}
+
+	candidate := stored.Clone()
+	err := fn(candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	m.sessions[pin] = candidate
+
+	return candidate.Clone(), nil
+}
+
+func (m *InMemorySessionStorer) DeleteSession(ctx context.Context, pin string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[pin]; !ok {
+		return ports.ErrSessionNotFound
+	}
+
+	delete(m.sessions, pin)
+
+	return nil
+}
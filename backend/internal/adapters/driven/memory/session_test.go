@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/domain/session_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+var (
+	testPin    = "123456"
+	testHostId = uuid.NewString()
+)
+
+type InMemorySessionStorerTestSuite struct {
+	suite.Suite
+	ctx  context.Context
+	repo *InMemorySessionStorer
+}
+
+func (suite *InMemorySessionStorerTestSuite) SetupTest() {
+	suite.ctx = context.Background()
+	suite.repo = NewInMemorySessionStorer()
+}
+
+func TestInMemorySessionStorerTestSuite(t *testing.T) {
+	suite.Run(t, new(InMemorySessionStorerTestSuite))
+}
+
+func (suite *InMemorySessionStorerTestSuite) generateMockedSession() *session.Session {
+	g := &game.Game{
+		Id:      uuid.New(),
+		Title:   "testGameTitle",
+		OwnerId: testHostId,
+	}
+
+	return session.NewSession(testPin, testHostId, g, time.Now())
+}
+
+func (suite *InMemorySessionStorerTestSuite) TestStoreSession() {
+	// Arrange
+	t := suite.T()
+	mockedSession := suite.generateMockedSession()
+
+	// Act
+	err := suite.repo.StoreSession(suite.ctx, mockedSession)
+
+	// Assert
+	assert.NoError(t, err)
+
+	stored, err := suite.repo.FindSessionByPin(suite.ctx, testPin)
+	assert.NoError(t, err)
+	assert.Equal(t, mockedSession.HostId, stored.HostId)
+	assert.Equal(t, session.LobbyState, stored.State)
+}
+
+func (suite *InMemorySessionStorerTestSuite) TestStoreSessionWithPinInUse() {
+	// Arrange
+	t := suite.T()
+	err := suite.repo.StoreSession(suite.ctx, suite.generateMockedSession())
+	assert.NoError(t, err)
+
+	// Act
+	err = suite.repo.StoreSession(suite.ctx, suite.generateMockedSession())
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrPinAlreadyInUse)
+}
+
+func (suite *InMemorySessionStorerTestSuite) TestFindSessionThatDoesNotExist() {
+	// Act
+	_, err := suite.repo.FindSessionByPin(suite.ctx, "000000")
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, ports.ErrSessionNotFound)
+}
+
+func (suite *InMemorySessionStorerTestSuite) TestUpdateSession() {
+	// Arrange
+	t := suite.T()
+	err := suite.repo.StoreSession(suite.ctx, suite.generateMockedSession())
+	assert.NoError(t, err)
+	player := &session.Player{Id: uuid.New(), Nickname: "tubias"}
+
+	// Act
+	updated, err := suite.repo.UpdateSession(
+		suite.ctx,
+		testPin,
+		func(s *session.Session) error {
+			s.Players = append(s.Players, player)
+			return nil
+		},
+	)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, updated.Players, 1)
+
+	stored, err := suite.repo.FindSessionByPin(suite.ctx, testPin)
+	assert.NoError(t, err)
+	assert.Len(t, stored.Players, 1)
+	assert.Equal(t, player.Nickname, stored.Players[0].Nickname)
+}
+
+func (suite *InMemorySessionStorerTestSuite) TestUpdateSessionDiscardsChangesOnError() {
+	// Arrange
+	t := suite.T()
+	err := suite.repo.StoreSession(suite.ctx, suite.generateMockedSession())
+	assert.NoError(t, err)
+	errBoom := errors.New("boom")
+
+	// Act
+	_, err = suite.repo.UpdateSession(suite.ctx, testPin, func(s *session.Session) error {
+		s.State = session.FinishedState
+		s.Players = append(s.Players, &session.Player{Id: uuid.New()})
+		return errBoom
+	})
+
+	// Assert
+	assert.ErrorIs(t, err, errBoom)
+
+	stored, err := suite.repo.FindSessionByPin(suite.ctx, testPin)
+	assert.NoError(t, err)
+	assert.Equal(t, session.LobbyState, stored.State)
+	assert.Empty(t, stored.Players)
+}
+
+func (suite *InMemorySessionStorerTestSuite) TestFoundSessionIsACopy() {
+	// Arrange
+	t := suite.T()
+	err := suite.repo.StoreSession(suite.ctx, suite.generateMockedSession())
+	assert.NoError(t, err)
+
+	// Act
+	found, err := suite.repo.FindSessionByPin(suite.ctx, testPin)
+	assert.NoError(t, err)
+	found.State = session.FinishedState
+
+	// Assert
+	stored, err := suite.repo.FindSessionByPin(suite.ctx, testPin)
+	assert.NoError(t, err)
+	assert.Equal(t, session.LobbyState, stored.State)
+}
+
+func (suite *InMemorySessionStorerTestSuite) TestDeleteSession() {
+	// Arrange
+	t := suite.T()
+	err := suite.repo.StoreSession(suite.ctx, suite.generateMockedSession())
+	assert.NoError(t, err)
+
+	// Act
+	err = suite.repo.DeleteSession(suite.ctx, testPin)
+
+	// Assert
+	assert.NoError(t, err)
+	_, err = suite.repo.FindSessionByPin(suite.ctx, testPin)
+	assert.ErrorIs(t, err, ports.ErrSessionNotFound)
+}
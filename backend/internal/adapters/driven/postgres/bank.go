@@ -0,0 +1,344 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+type PostgresBankStorer struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresBankStorer(pool *pgxpool.Pool) *PostgresBankStorer {
+	return &PostgresBankStorer{
+		pool: pool,
+	}
+}
+
+func (p *PostgresBankStorer) StoreBankQuestion(
+	ctx context.Context,
+	question *game.BankQuestion,
+) error {
+	return p.StoreBankQuestions(ctx, []*game.BankQuestion{question})
+}
+
+func (p *PostgresBankStorer) StoreBankQuestions(
+	ctx context.Context,
+	questions []*game.BankQuestion,
+) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, question := range questions {
+		err = storeBankQuestion(ctx, tx, question)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresBankStorer) UpdateBankQuestion(
+	ctx context.Context,
+	question *game.BankQuestion,
+) error {
+	args, err := bankQuestionArgs(question)
+	if err != nil {
+		return err
+	}
+
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	update := `UPDATE bank_questions SET kind = @kind, title = @title, data = @data, updated_at = @updatedAt WHERE id = @id`
+	t, err := tx.Exec(ctx, update, args)
+	if err != nil {
+		return err
+	}
+
+	if t.RowsAffected() == 0 {
+		return ports.ErrBankQuestionNotFound
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM bank_question_tags WHERE question_id = @id`, args)
+	if err != nil {
+		return err
+	}
+
+	err = storeBankTags(ctx, tx, question)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresBankStorer) DeleteBankQuestion(ctx context.Context, id uuid.UUID) error {
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	t, err := p.pool.Exec(ctx, `DELETE FROM bank_questions WHERE id = @id`, args)
+	if err != nil {
+		return err
+	}
+
+	if t.RowsAffected() == 0 {
+		return ports.ErrBankQuestionNotFound
+	}
+
+	return nil
+}
+
+func (p *PostgresBankStorer) FindBankQuestion(
+	ctx context.Context,
+	id uuid.UUID,
+) (*game.BankQuestion, error) {
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	query := `SELECT ` + bankQuestionColumns + ` FROM bank_questions WHERE id = @id`
+
+	question, err := scanBankQuestion(p.pool.QueryRow(ctx, query, args))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ports.ErrBankQuestionNotFound
+		}
+		return nil, err
+	}
+
+	return question, nil
+}
+
+func (p *PostgresBankStorer) FindBankQuestionsByIds(
+	ctx context.Context,
+	ids []uuid.UUID,
+) ([]*game.BankQuestion, error) {
+	args := pgx.NamedArgs{
+		"ids": ids,
+	}
+
+	query := `SELECT ` + bankQuestionColumns + ` FROM bank_questions WHERE id = ANY(@ids)`
+
+	found, err := p.findBankQuestions(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[uuid.UUID]*game.BankQuestion, len(found))
+	for _, question := range found {
+		byId[question.Id] = question
+	}
+
+	questions := make([]*game.BankQuestion, len(ids))
+	for i, id := range ids {
+		question, ok := byId[id]
+		if !ok {
+			return nil, ports.ErrBankQuestionNotFound
+		}
+
+		questions[i] = question
+	}
+
+	return questions, nil
+}
+
+func (p *PostgresBankStorer) FindBankQuestions(
+	ctx context.Context,
+	query ports.BankQuery,
+) ([]*game.BankQuestion, int, error) {
+	args := bankQueryArgs(query)
+	args["limit"] = query.PageSize
+	args["offset"] = (query.Page - 1) * query.PageSize
+
+	var total int
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM bank_questions `+bankQueryFilter, args).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	selectQuery := `SELECT ` + bankQuestionColumns + ` FROM bank_questions ` + bankQueryFilter +
+		` ORDER BY created_at DESC, id LIMIT @limit OFFSET @offset`
+
+	questions, err := p.findBankQuestions(ctx, selectQuery, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return questions, total, nil
+}
+
+func (p *PostgresBankStorer) FindRandomBankQuestions(
+	ctx context.Context,
+	query ports.BankQuery,
+	count int,
+) ([]*game.BankQuestion, error) {
+	args := bankQueryArgs(query)
+	args["count"] = count
+
+	selectQuery := `SELECT ` + bankQuestionColumns + ` FROM bank_questions ` + bankQueryFilter +
+		` ORDER BY random() LIMIT @count`
+
+	return p.findBankQuestions(ctx, selectQuery, args)
+}
+
+func (p *PostgresBankStorer) findBankQuestions(
+	ctx context.Context,
+	query string,
+	args pgx.NamedArgs,
+) ([]*game.BankQuestion, error) {
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []*game.BankQuestion{}
+	for rows.Next() {
+		question, err := scanBankQuestion(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		questions = append(questions, question)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// bankQueryFilter selects the questions of a BankQuery, a question matching
+// when it has every tag asked for. Category and difficulty are the ones of the
+// question itself, kept in its JSON.
+const bankQueryFilter = `WHERE owner_id = @ownerId
+	AND title ILIKE @search
+	AND (cardinality(@kinds::text[]) = 0 OR kind = ANY(@kinds::text[]))
+	AND @tags::text[] <@ ARRAY(SELECT tag FROM bank_question_tags WHERE question_id = bank_questions.id)
+	AND (@category = '' OR data->>'category' = @category)
+	AND (@difficulty = '' OR data->>'difficulty' = @difficulty)`
+
+func bankQueryArgs(query ports.BankQuery) pgx.NamedArgs {
+	kinds := make([]string, len(query.Kinds))
+	for i, kind := range query.Kinds {
+		kinds[i] = string(kind)
+	}
+
+	tags := query.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return pgx.NamedArgs{
+		"ownerId":    query.OwnerId,
+		"search":     "%" + escapeLike(query.Search) + "%",
+		"kinds":      kinds,
+		"tags":       tags,
+		"category":   query.Category,
+		"difficulty": string(query.Difficulty),
+	}
+}
+
+func bankQuestionArgs(question *game.BankQuestion) (pgx.NamedArgs, error) {
+	data, err := json.Marshal(question.Question)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.NamedArgs{
+		"id":        question.Id,
+		"ownerId":   question.OwnerId,
+		"kind":      question.Question.Kind(),
+		"title":     bankTitle(question.Question),
+		"data":      data,
+		"createdAt": question.CreatedAt,
+		"updatedAt": question.UpdatedAt,
+	}, nil
+}
+
+// bankTitle is the title of the question as readers see it, so that searches
+// don't match markup.
+func bankTitle(question game.Question) string {
+	format := game.PlainText
+	if rendered, ok := question.(game.RenderedQuestion); ok {
+		format = rendered.GetFormat()
+	}
+
+	return game.VisibleText(format, question.GetTitle())
+}
+
+func storeBankQuestion(ctx context.Context, tx pgx.Tx, question *game.BankQuestion) error {
+	args, err := bankQuestionArgs(question)
+	if err != nil {
+		return err
+	}
+
+	insert := `INSERT INTO bank_questions (id, owner_id, kind, title, data, created_at, updated_at)
+		VALUES (@id, @ownerId, @kind, @title, @data, @createdAt, @updatedAt)`
+	_, err = tx.Exec(ctx, insert, args)
+	if err != nil {
+		return err
+	}
+
+	return storeBankTags(ctx, tx, question)
+}
+
+func storeBankTags(ctx context.Context, tx pgx.Tx, question *game.BankQuestion) error {
+	if len(question.Tags) == 0 {
+		return nil
+	}
+
+	args := pgx.NamedArgs{
+		"id":   question.Id,
+		"tags": question.Tags,
+	}
+
+	_, err := tx.Exec(ctx, `INSERT INTO bank_question_tags (question_id, tag) SELECT @id, unnest(@tags::text[])`, args)
+	return err
+}
+
+const bankQuestionColumns = `id, owner_id, kind, data, created_at, updated_at,
+	ARRAY(SELECT tag FROM bank_question_tags WHERE question_id = bank_questions.id ORDER BY tag)`
+
+func scanBankQuestion(row pgx.Row) (*game.BankQuestion, error) {
+	var question game.BankQuestion
+	var kind game.Kind
+	var data []byte
+
+	err := row.Scan(
+		&question.Id,
+		&question.OwnerId,
+		&kind,
+		&data,
+		&question.CreatedAt,
+		&question.UpdatedAt,
+		&question.Tags,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	question.Question, err = game.DecodeQuestion(kind, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &question, nil
+}
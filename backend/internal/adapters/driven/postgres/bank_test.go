@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	testcontainers "github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+	"github.com/taldoflemis/brain.test/test/helpers"
+)
+
+type PostgresBankStorerTestSuite struct {
+	suite.Suite
+	pgContainer *testcontainers.PostgresContainer
+	ctx         context.Context
+	repo        *PostgresBankStorer
+	pool        *pgxpool.Pool
+}
+
+func (suite *PostgresBankStorerTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	pgContainer, pool, err := testshelpers.CreatePostgresContainerAndMigrate(
+		suite.ctx,
+		"./migrations/",
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pgContainer = pgContainer
+
+	suite.pool = pool
+	suite.repo = NewPostgresBankStorer(pool)
+}
+
+func (suite *PostgresBankStorerTestSuite) TearDownTest() {
+	_, err := suite.pool.Exec(suite.ctx, "TRUNCATE TABLE bank_questions CASCADE")
+	if err != nil {
+		log.Fatalf("error truncating bank_questions table: %s", err)
+	}
+}
+
+func (suite *PostgresBankStorerTestSuite) TearDownSuite() {
+	if err := suite.pgContainer.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating postgres container: %s", err)
+	}
+}
+
+func TestPostgresBankStorerTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	suite.Run(t, new(PostgresBankStorerTestSuite))
+}
+
+func (suite *PostgresBankStorerTestSuite) storeBankQuestion(
+	ownerId string,
+	question game.Question,
+	tags ...string,
+) *game.BankQuestion {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	saved := game.NewBankQuestion(ownerId, question, tags, now)
+
+	err := suite.repo.StoreBankQuestion(suite.ctx, saved)
+	if err != nil {
+		log.Fatalf("error storing bank question: %s", err)
+	}
+
+	return saved
+}
+
+func (suite *PostgresBankStorerTestSuite) TestStoreAndFindBankQuestion() {
+	// Arrange
+	t := suite.T()
+	saved := suite.storeBankQuestion("owner", &game.QuizQuestion{
+		Title:     "What is **2 + 2**?",
+		Format:    game.MarkdownText,
+		Points:    1,
+		TimeLimit: 30,
+		Alternatives: []game.Alternative{
+			{Data: "3"},
+			{Data: "4", IsCorrect: true},
+			{Data: "5"},
+		},
+	}, "Math", "arithmetic")
+
+	// Act
+	found, err := suite.repo.FindBankQuestion(suite.ctx, saved.Id)
+	_, missingErr := suite.repo.FindBankQuestion(suite.ctx, uuid.New())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, saved.Question, found.Question)
+	assert.Equal(t, []string{"arithmetic", "math"}, found.Tags)
+	assert.Equal(t, saved.OwnerId, found.OwnerId)
+	assert.True(t, saved.CreatedAt.Equal(found.CreatedAt))
+	assert.ErrorIs(t, missingErr, ports.ErrBankQuestionNotFound)
+}
+
+func (suite *PostgresBankStorerTestSuite) TestFindBankQuestions() {
+	// Arrange
+	t := suite.T()
+	trueFalse := func(title string) game.Question {
+		return &game.TrueFalseQuestion{
+			Title:            title,
+			Points:           1,
+			TimeLimit:        30,
+			TrueAlternative:  "yes",
+			FalseAlternative: "no",
+		}
+	}
+	light := suite.storeBankQuestion("owner", trueFalse("Plants need light"), "photosynthesis", "biology")
+	water := suite.storeBankQuestion("owner", trueFalse("Plants need water"), "photosynthesis")
+	suite.storeBankQuestion("owner", trueFalse("Cells have a nucleus"), "biology")
+	suite.storeBankQuestion("other", trueFalse("Plants need soil"), "photosynthesis")
+
+	table := []struct {
+		testDescription string
+		query           ports.BankQuery
+		expected        []uuid.UUID
+		total           int
+	}{
+		{
+			testDescription: "by tag",
+			query:           ports.BankQuery{OwnerId: "owner", Tags: []string{"photosynthesis"}, Page: 1, PageSize: 10},
+			expected:        []uuid.UUID{water.Id, light.Id},
+			total:           2,
+		},
+		{
+			testDescription: "by every tag",
+			query:           ports.BankQuery{OwnerId: "owner", Tags: []string{"photosynthesis", "biology"}, Page: 1, PageSize: 10},
+			expected:        []uuid.UUID{light.Id},
+			total:           1,
+		},
+		{
+			testDescription: "by title and page",
+			query:           ports.BankQuery{OwnerId: "owner", Search: "plants", Page: 2, PageSize: 1},
+			expected:        []uuid.UUID{light.Id},
+			total:           2,
+		},
+		{
+			testDescription: "by kind",
+			query:           ports.BankQuery{OwnerId: "owner", Kinds: []game.Kind{game.QuizKind}, Page: 1, PageSize: 10},
+			expected:        []uuid.UUID{},
+			total:           0,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Act
+			found, total, err := suite.repo.FindBankQuestions(suite.ctx, tt.query)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.total, total)
+			ids := make([]uuid.UUID, len(found))
+			for i, q := range found {
+				ids[i] = q.Id
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+
+	// Act
+	random, err := suite.repo.FindRandomBankQuestions(suite.ctx, ports.BankQuery{
+		OwnerId: "owner",
+		Tags:    []string{"photosynthesis"},
+	}, 10)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, random, 2)
+}
+
+func (suite *PostgresBankStorerTestSuite) TestUpdateAndDeleteBankQuestion() {
+	// Arrange
+	t := suite.T()
+	saved := suite.storeBankQuestion("owner", &game.TrueFalseQuestion{
+		Title:            "Plants need light",
+		Points:           1,
+		TimeLimit:        30,
+		TrueAlternative:  "yes",
+		FalseAlternative: "no",
+	}, "photosynthesis")
+
+	saved.Question.(*game.TrueFalseQuestion).Title = "Plants need sunlight"
+	saved.Tags = []string{"light"}
+	saved.UpdatedAt = saved.UpdatedAt.Add(time.Minute)
+
+	// Act
+	updateErr := suite.repo.UpdateBankQuestion(suite.ctx, saved)
+	found, findErr := suite.repo.FindBankQuestion(suite.ctx, saved.Id)
+	deleteErr := suite.repo.DeleteBankQuestion(suite.ctx, saved.Id)
+	deleteAgainErr := suite.repo.DeleteBankQuestion(suite.ctx, saved.Id)
+
+	// Assert
+	assert.NoError(t, updateErr)
+	assert.NoError(t, findErr)
+	assert.Equal(t, "Plants need sunlight", found.Question.GetTitle())
+	assert.Equal(t, []string{"light"}, found.Tags)
+	assert.NoError(t, deleteErr)
+	assert.ErrorIs(t, deleteAgainErr, ports.ErrBankQuestionNotFound)
+}
+
+func (suite *PostgresBankStorerTestSuite) TestStoreAndFindBankQuestionsByIds() {
+	// Arrange
+	t := suite.T()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	questions := []*game.BankQuestion{}
+	for _, title := range []string{"Plants need light", "Cells have a nucleus"} {
+		questions = append(questions, game.NewBankQuestion("owner", &game.TrueFalseQuestion{
+			Title:            title,
+			Points:           1,
+			TimeLimit:        30,
+			TrueAlternative:  "yes",
+			FalseAlternative: "no",
+		}, []string{"biology"}, now))
+	}
+	duplicated := []*game.BankQuestion{
+		game.NewBankQuestion("owner", &game.TrueFalseQuestion{Title: "Leaves are green"}, nil, now),
+		questions[0],
+	}
+
+	// Act
+	err := suite.repo.StoreBankQuestions(suite.ctx, questions)
+	duplicatedErr := suite.repo.StoreBankQuestions(suite.ctx, duplicated)
+	found, findErr := suite.repo.FindBankQuestionsByIds(
+		suite.ctx,
+		[]uuid.UUID{questions[1].Id, questions[0].Id},
+	)
+	_, missingErr := suite.repo.FindBankQuestionsByIds(
+		suite.ctx,
+		[]uuid.UUID{questions[0].Id, duplicated[0].Id},
+	)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Error(t, duplicatedErr)
+	assert.NoError(t, findErr)
+	assert.Len(t, found, 2)
+	assert.Equal(t, "Cells have a nucleus", found[0].Question.GetTitle())
+	assert.Equal(t, "Plants need light", found[1].Question.GetTitle())
+	assert.Equal(t, []string{"biology"}, found[0].Tags)
+	assert.ErrorIs(t, missingErr, ports.ErrBankQuestionNotFound)
+}
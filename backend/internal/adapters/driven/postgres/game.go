@@ -3,6 +3,9 @@ package postgres
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -12,142 +15,1278 @@ import (
 	"github.com/taldoflemis/brain.test/internal/ports"
 )
 
-type QuestionKind string
+type PostgresGameStorer struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresGameStorer(pool *pgxpool.Pool) *PostgresGameStorer {
+	return &PostgresGameStorer{
+		pool: pool,
+	}
+}
+
+func (p *PostgresGameStorer) StoreGame(
+	ctx context.Context,
+	game *game.Game,
+) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = p.storeGame(ctx, tx, game)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresGameStorer) CreateGame(
+	ctx context.Context,
+	g *game.Game,
+	revision *game.Revision,
+	publishedAt *time.Time,
+) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = p.storeGame(ctx, tx, g)
+	if err != nil {
+		return err
+	}
+
+	if revision != nil {
+		err = storeRevision(ctx, tx, revision)
+		if err != nil {
+			return err
+		}
+	}
+
+	if publishedAt == nil {
+		return tx.Commit(ctx)
+	}
+
+	snapshot := g.Snapshot(*publishedAt)
+	err = p.publishGame(ctx, tx, snapshot)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+
+	g.Status = snapshot.Status
+	g.PublishedAt = snapshot.PublishedAt
+
+	return nil
+}
+
+func (p *PostgresGameStorer) storeGame(
+	ctx context.Context,
+	tx pgx.Tx,
+	game *game.Game,
+) error {
+	args := pgx.NamedArgs{
+		"id":          game.Id,
+		"title":       game.Title,
+		"description": game.Description,
+		"owner_id":    game.OwnerId,
+		"visibility":  game.VisibilityOrDefault(),
+		"forked_from": game.ForkedFrom,
+		"status":      game.StatusOrDefault(),
+		"revision":    max(game.Revision, 1),
+		"category":    game.Category,
+		"difficulty":  game.Difficulty,
+	}
+
+	insert := `INSERT INTO games (id, title, description, owner_id, visibility, forked_from, status, revision, category, difficulty) VALUES (@id, @title, @description, @owner_id, @visibility, @forked_from, @status, @revision, @category, @difficulty)
+		RETURNING created_at, updated_at`
+	err := tx.QueryRow(ctx, insert, args).Scan(&game.CreatedAt, &game.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	err = storeTags(ctx, tx.Conn(), "game_tags", game.Id, game.Tags)
+	if err != nil {
+		return err
+	}
+
+	for i, question := range game.Questions {
+		err := p.storeQuestion(ctx, tx.Conn(), game.Id, i, question)
+		if err != nil {
+			return err
+		}
+	}
+
+	if game.Scoring != nil {
+		err = p.storeScoringPolicy(ctx, tx.Conn(), game.Id, game.Scoring)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *PostgresGameStorer) UpdateGameInfo(
+	ctx context.Context,
+	id uuid.UUID,
+	info *ports.GameInfo,
+) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = p.updateGameInfo(ctx, tx, id, info)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresGameStorer) UpdateGameQuestions(
+	ctx context.Context,
+	id uuid.UUID,
+	questions []game.Question,
+) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = p.updateGameQuestions(ctx, tx, id, questions)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresGameStorer) UpdateGame(
+	ctx context.Context,
+	id uuid.UUID,
+	info *ports.GameInfo,
+	questions []game.Question,
+) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = p.updateGameInfo(ctx, tx, id, info)
+	if err != nil {
+		return err
+	}
+
+	err = p.updateGameQuestions(ctx, tx, id, questions)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresGameStorer) updateGameInfo(
+	ctx context.Context,
+	tx pgx.Tx,
+	id uuid.UUID,
+	info *ports.GameInfo,
+) error {
+	args := pgx.NamedArgs{
+		"id":          id,
+		"title":       info.Title,
+		"description": info.Description,
+		"visibility":  info.Visibility,
+		"category":    info.Metadata.Category,
+		"difficulty":  info.Metadata.Difficulty,
+	}
+
+	update := `UPDATE games SET title = @title, description = @description, visibility = @visibility, category = @category, difficulty = @difficulty, ` + draftAssignments + ` WHERE id = @id`
+	t, err := tx.Exec(ctx, update, args)
+	if err != nil {
+		return err
+	}
+
+	if t.RowsAffected() == 0 {
+		return ports.ErrGameNotFound
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM scoring_policies WHERE game_id = @id`, args)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `DELETE FROM game_tags WHERE game_id = @id`, args)
+	if err != nil {
+		return err
+	}
+
+	err = storeTags(ctx, tx.Conn(), "game_tags", id, info.Metadata.Tags)
+	if err != nil {
+		return err
+	}
+
+	if info.Scoring != nil {
+		err = p.storeScoringPolicy(ctx, tx.Conn(), id, info.Scoring)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *PostgresGameStorer) updateGameQuestions(
+	ctx context.Context,
+	tx pgx.Tx,
+	id uuid.UUID,
+	questions []game.Question,
+) error {
+	args := pgx.NamedArgs{
+		"gameId": id,
+	}
+
+	// locks the game so that concurrent updates don't interleave questions
+	var found uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT id FROM games WHERE id = @gameId FOR UPDATE`, args).
+		Scan(&found)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ports.ErrGameNotFound
+		}
+		return err
+	}
+
+	deletes := []string{}
+	for _, table := range questionTables() {
+		deletes = append(deletes, `DELETE FROM `+table+` WHERE question_id IN (SELECT id FROM questions WHERE game_id = @gameId)`)
+	}
+	deletes = append(deletes, `DELETE FROM questions WHERE game_id = @gameId`)
+
+	for _, query := range deletes {
+		_, err = tx.Exec(ctx, query, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, question := range questions {
+		err = p.storeQuestion(ctx, tx.Conn(), id, i, question)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE games SET `+draftAssignments+` WHERE id = @gameId`, args)
+	return err
+}
+
+func (p *PostgresGameStorer) PublishGame(ctx context.Context, snapshot *game.Game) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = p.publishGame(ctx, tx, snapshot)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresGameStorer) publishGame(ctx context.Context, tx pgx.Tx, snapshot *game.Game) error {
+	data, err := encodeGame(snapshot)
+	if err != nil {
+		return err
+	}
+
+	args := pgx.NamedArgs{
+		"gameId":      snapshot.Id,
+		"status":      game.PublishedStatus,
+		"revision":    snapshot.Revision,
+		"data":        data,
+		"publishedAt": snapshot.PublishedAt,
+	}
+
+	update := `UPDATE games SET status = @status, published_at = @publishedAt WHERE id = @gameId`
+	t, err := tx.Exec(ctx, update, args)
+	if err != nil {
+		return err
+	}
+
+	if t.RowsAffected() == 0 {
+		return ports.ErrGameNotFound
+	}
+
+	upsert := `INSERT INTO game_snapshots (game_id, revision, data, published_at) VALUES (@gameId, @revision, @data, @publishedAt)
+		ON CONFLICT (game_id) DO UPDATE SET revision = EXCLUDED.revision, data = EXCLUDED.data, published_at = EXCLUDED.published_at`
+	_, err = tx.Exec(ctx, upsert, args)
+	return err
+}
+
+func (p *PostgresGameStorer) FindPublishedGame(
+	ctx context.Context,
+	id uuid.UUID,
+) (*game.Game, error) {
+	args := pgx.NamedArgs{
+		"gameId": id,
+	}
+
+	query := `SELECT game_snapshots.data FROM games LEFT JOIN game_snapshots ON game_snapshots.game_id = games.id WHERE games.id = @gameId`
+
+	var data []byte
+	err := p.pool.QueryRow(ctx, query, args).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ports.ErrGameNotFound
+		}
+		return nil, err
+	}
+
+	if data == nil {
+		return nil, ports.ErrGameNotPublished
+	}
+
+	return decodeGame(data)
+}
+
+// FindUnreferencedMedia looks for the media in the columns questions keep
+// them in, and in the JSON of snapshots, revisions and documents where any
+// mention of their id counts as a reference.
+func (p *PostgresGameStorer) FindUnreferencedMedia(
+	ctx context.Context,
+	ids []uuid.UUID,
+) ([]uuid.UUID, error) {
+	unreferenced := []uuid.UUID{}
+	if len(ids) == 0 {
+		return unreferenced, nil
+	}
+
+	args := pgx.NamedArgs{
+		"ids": ids,
+	}
+
+	query := unattachedMediaQuery +
+		` AND NOT EXISTS (SELECT 1 FROM game_revisions WHERE strpos(data::text, candidate.id::text) > 0)`
+
+	return p.findMediaIds(ctx, query, args)
+}
+
+func (p *PostgresGameStorer) FindUnattachedMedia(
+	ctx context.Context,
+	ids []uuid.UUID,
+) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return []uuid.UUID{}, nil
+	}
+
+	args := pgx.NamedArgs{
+		"ids": ids,
+	}
+
+	return p.findMediaIds(ctx, unattachedMediaQuery, args)
+}
+
+// unattachedMediaQuery selects the media, among @ids, that neither the
+// current nor the published version of a game references, nor a question of
+// a bank.
+const unattachedMediaQuery = `SELECT candidate.id FROM unnest(@ids::uuid[]) AS candidate(id)
+	WHERE NOT EXISTS (SELECT 1 FROM questions WHERE media->>'Id' = candidate.id::text)
+	AND NOT EXISTS (SELECT 1 FROM quiz_questions WHERE media->>'Id' = candidate.id::text)
+	AND NOT EXISTS (SELECT 1 FROM question_documents WHERE strpos(data::text, candidate.id::text) > 0)
+	AND NOT EXISTS (SELECT 1 FROM game_snapshots WHERE strpos(data::text, candidate.id::text) > 0)
+	AND NOT EXISTS (SELECT 1 FROM bank_questions WHERE strpos(data::text, candidate.id::text) > 0)`
+
+// FindMediaReferencedByUser looks for the media in the same columns as
+// FindUnreferencedMedia, only among the games and bank of userId.
+func (p *PostgresGameStorer) FindMediaReferencedByUser(
+	ctx context.Context,
+	userId string,
+	ids []uuid.UUID,
+) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return []uuid.UUID{}, nil
+	}
+
+	args := pgx.NamedArgs{
+		"userId": userId,
+		"ids":    ids,
+	}
+
+	query := `SELECT candidate.id FROM unnest(@ids::uuid[]) AS candidate(id)
+		WHERE EXISTS (SELECT 1 FROM questions JOIN games ON games.id = questions.game_id
+			WHERE games.owner_id = @userId AND questions.media->>'Id' = candidate.id::text)
+		OR EXISTS (SELECT 1 FROM quiz_questions JOIN questions ON questions.id = quiz_questions.question_id
+			JOIN games ON games.id = questions.game_id
+			WHERE games.owner_id = @userId AND quiz_questions.media->>'Id' = candidate.id::text)
+		OR EXISTS (SELECT 1 FROM question_documents JOIN questions ON questions.id = question_documents.question_id
+			JOIN games ON games.id = questions.game_id
+			WHERE games.owner_id = @userId AND strpos(question_documents.data::text, candidate.id::text) > 0)
+		OR EXISTS (SELECT 1 FROM bank_questions
+			WHERE bank_questions.owner_id = @userId AND strpos(bank_questions.data::text, candidate.id::text) > 0)`
+
+	return p.findMediaIds(ctx, query, args)
+}
+
+func (p *PostgresGameStorer) findMediaIds(
+	ctx context.Context,
+	query string,
+	args pgx.NamedArgs,
+) ([]uuid.UUID, error) {
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (p *PostgresGameStorer) DeleteGame(ctx context.Context, id uuid.UUID) error {
+	args := pgx.NamedArgs{
+		"gameId": id,
+	}
+
+	query := "DELETE FROM games WHERE id = @gameId"
+
+	t, err := p.pool.Exec(ctx, query, args)
+
+	if err != nil {
+		return err
+	}
+
+	if t.RowsAffected() == 0 {
+		return ports.ErrGameNotFound
+	}
+
+	return nil
+}
+
+func (p *PostgresGameStorer) FindGameById(
+	ctx context.Context,
+	id uuid.UUID,
+) (*game.Game, error) {
+	args := pgx.NamedArgs{
+		"gameId": id,
+	}
+
+	query := `SELECT ` + gameColumns + ` FROM games ` + scoringPolicyJoin + ` WHERE id = @gameId;`
+
+	row := p.pool.QueryRow(ctx, query, args)
+
+	game, err := scanGame(row)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ports.ErrGameNotFound
+		}
+
+		return nil, err
+	}
+
+	err = p.loadQuestions(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+
+	return game, nil
+}
+
+// FindAllGamesByUserId pages through the games with keyset pagination, i.e.
+// games are kept by comparing their sort key and id with the ones of the
+// cursor rather than skipped, so that pages don't shift when games are added
+// or removed meanwhile.
+func (p *PostgresGameStorer) FindAllGamesByUserId(
+	ctx context.Context,
+	userId string,
+	opts ports.FindGamesOptions,
+) ([]*game.Game, int, error) {
+	args := metadataFilterArgs(opts.Filter)
+	args["userId"] = userId
+	args["search"] = "%" + escapeLike(opts.Search) + "%"
+
+	filter := `WHERE owner_id = @userId AND title ILIKE @search AND ` + metadataFilter
+
+	var total int
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) FROM games `+filter, args).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	key, ok := gameSortKeys[opts.Sort]
+	if !ok {
+		key = gameSortKeys[ports.CreatedGameSort]
+	}
+
+	// games before the cursor are found walking the listing backwards, then
+	// put back in order
+	backwards := opts.Before != nil
+	descending := opts.Descending != backwards
+
+	cursor := opts.After
+	if backwards {
+		cursor = opts.Before
+	}
+	if cursor != nil {
+		args["cursorKey"] = cursor.Key
+		args["cursorId"] = cursor.Id
+
+		comparison := `>`
+		if descending {
+			comparison = `<`
+		}
+		filter += ` AND (` + key.column + `, id) ` + comparison + ` (@cursorKey::` + key.cast + `, @cursorId)`
+	}
+
+	direction := ` ASC`
+	if descending {
+		direction = ` DESC`
+	}
+	query := `SELECT ` + gameColumns + ` FROM games ` + scoringPolicyJoin + ` ` + filter +
+		` ORDER BY ` + key.column + direction + `, id` + direction
+
+	if opts.Limit > 0 {
+		args["limit"] = opts.Limit
+		query += ` LIMIT @limit`
+	}
+
+	rows, err := p.pool.Query(ctx, query, args)
+
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	games := []*game.Game{}
+
+	for rows.Next() {
+		game, err := scanGame(rows)
+
+		if err != nil {
+			return nil, 0, err
+		}
+		games = append(games, game)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if backwards {
+		slices.Reverse(games)
+	}
+
+	if opts.SummaryOnly {
+		return games, total, nil
+	}
+
+	err = p.loadQuestions(ctx, games...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return games, total, nil
+}
+
+// FindPublicGames lists the published version of public games, so that
+// drafts are never seen by others. Games hidden since they were published
+// are left out right away.
+func (p *PostgresGameStorer) FindPublicGames(
+	ctx context.Context,
+	query ports.PublicGamesQuery,
+) ([]*ports.GameListing, int, error) {
+	args := metadataFilterArgs(query.Filter)
+	args["visibility"] = game.PublicVisibility
+	args["search"] = "%" + escapeLike(query.Search) + "%"
+	args["limit"] = query.PageSize
+	args["offset"] = (query.Page - 1) * query.PageSize
+
+	from := `FROM game_snapshots JOIN games ON games.id = game_snapshots.game_id
+		WHERE games.visibility = @visibility AND game_snapshots.data->>'visibility' = @visibility
+		AND (game_snapshots.data->>'title' ILIKE @search OR game_snapshots.data->>'description' ILIKE @search)
+		AND ` + snapshotMetadataFilter
+
+	var total int
+	err := p.pool.QueryRow(ctx, `SELECT COUNT(*) `+from, args).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	order := `games.created_at DESC, games.id`
+	if query.Sort == ports.PlayCountGameSort {
+		order = `play_count DESC, games.created_at DESC, games.id`
+	}
+
+	selectQuery := `SELECT game_snapshots.data - 'questions',
+		jsonb_array_length(game_snapshots.data->'questions') AS question_count,
+		(SELECT COUNT(*) FROM session_results WHERE session_results.game_id = games.id) AS play_count
+		` + from + ` ORDER BY ` + order + ` LIMIT @limit OFFSET @offset`
+
+	rows, err := p.pool.Query(ctx, selectQuery, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	listings := []*ports.GameListing{}
+
+	for rows.Next() {
+		listing := ports.GameListing{}
+
+		var data []byte
+		err = rows.Scan(&data, &listing.QuestionCount, &listing.PlayCount)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		listing.Game, err = decodeGame(data)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		listings = append(listings, &listing)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return listings, total, nil
+}
+
+// FindTagsByUserId counts a tag once for every game, question or bank
+// question having it.
+func (p *PostgresGameStorer) FindTagsByUserId(
+	ctx context.Context,
+	userId string,
+	prefix string,
+	limit int,
+) ([]string, error) {
+	args := pgx.NamedArgs{
+		"userId": userId,
+		"prefix": escapeLike(prefix) + "%",
+		"limit":  limit,
+	}
+
+	query := `SELECT tag FROM (
+			SELECT tag FROM game_tags JOIN games ON games.id = game_tags.game_id WHERE games.owner_id = @userId
+			UNION ALL
+			SELECT tag FROM question_tags JOIN questions ON questions.id = question_tags.question_id
+				JOIN games ON games.id = questions.game_id WHERE games.owner_id = @userId
+			UNION ALL
+			SELECT tag FROM bank_question_tags JOIN bank_questions ON bank_questions.id = bank_question_tags.question_id
+				WHERE bank_questions.owner_id = @userId
+		) AS used
+		WHERE tag LIKE @prefix
+		GROUP BY tag ORDER BY COUNT(*) DESC, tag LIMIT @limit`
+
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		err := rows.Scan(&tag)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// loadQuestions fills the questions of the given games, using one query for
+// the questions and one for each kind of question no matter how many games
+// there are.
+func (p *PostgresGameStorer) loadQuestions(ctx context.Context, games ...*game.Game) error {
+	if len(games) == 0 {
+		return nil
+	}
+
+	gameIds := make([]uuid.UUID, len(games))
+	byGameId := make(map[uuid.UUID]*game.Game, len(games))
+	for i, g := range games {
+		g.Questions = []game.Question{}
+		gameIds[i] = g.Id
+		byGameId[g.Id] = g
+	}
+
+	args := pgx.NamedArgs{
+		"gameIds": gameIds,
+	}
+
+	query := `SELECT id, game_id, kind, title, time_limit, points, media, format, rendered_title, category, difficulty,
+		ARRAY(SELECT tag FROM question_tags WHERE question_tags.question_id = questions.id ORDER BY tag)
+		FROM questions WHERE game_id = ANY(@gameIds) ORDER BY game_id, "order"`
+
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byKind := make(map[game.Kind]map[uuid.UUID]game.Question)
+
+	for rows.Next() {
+		var id, gameId uuid.UUID
+		var kind game.Kind
+		var header game.Header
+		var media []byte
+
+		err := rows.Scan(
+			&id,
+			&gameId,
+			&kind,
+			&header.Title,
+			&header.TimeLimit,
+			&header.Points,
+			&media,
+			&header.Format,
+			&header.RenderedTitle,
+			&header.Metadata.Category,
+			&header.Metadata.Difficulty,
+			&header.Metadata.Tags,
+		)
+		if err != nil {
+			return err
+		}
+		header.Id = id
+
+		header.Media, err = decodeMedia(media)
+		if err != nil {
+			return err
+		}
+
+		spec, err := game.LookupKind(kind)
+		if err != nil {
+			return err
+		}
+
+		question := spec.New(header)
+		if byKind[kind] == nil {
+			byKind[kind] = make(map[uuid.UUID]game.Question)
+		}
+		byKind[kind][id] = question
+
+		g := byGameId[gameId]
+		g.Questions = append(g.Questions, question)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for kind, questions := range byKind {
+		mapper := questionMapperOf(kind)
+		if mapper.load == nil {
+			continue
+		}
+
+		err = mapper.load(p, ctx, questions)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadQuizQuestions loads both the alternatives and the settings of quiz
+// questions.
+func (p *PostgresGameStorer) loadQuizQuestions(
+	ctx context.Context,
+	quizzes map[uuid.UUID]*game.QuizQuestion,
+) error {
+	err := p.loadQuizAlternatives(ctx, quizzes)
+	if err != nil {
+		return err
+	}
+
+	return p.loadQuizSettings(ctx, quizzes)
+}
+
+func (p *PostgresGameStorer) loadQuizSettings(
+	ctx context.Context,
+	quizzes map[uuid.UUID]*game.QuizQuestion,
+) error {
+	if len(quizzes) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(quizzes))
+	for id := range quizzes {
+		ids = append(ids, id)
+	}
+
+	args := pgx.NamedArgs{
+		"questionIds": ids,
+	}
+
+	query := `SELECT question_id, mode, credit FROM quiz_settings WHERE question_id = ANY(@questionIds)`
+
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var questionId uuid.UUID
+		var mode game.QuizMode
+		var credit game.CreditPolicy
+
+		err := rows.Scan(&questionId, &mode, &credit)
+		if err != nil {
+			return err
+		}
+
+		q := quizzes[questionId]
+		q.Mode = mode
+		q.Credit = credit
+	}
+
+	return rows.Err()
+}
+
+func (p *PostgresGameStorer) loadQuizAlternatives(
+	ctx context.Context,
+	quizzes map[uuid.UUID]*game.QuizQuestion,
+) error {
+	if len(quizzes) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(quizzes))
+	for id := range quizzes {
+		ids = append(ids, id)
+	}
 
-const (
-	QuizQuestionKind      QuestionKind = "quiz"
-	TrueFalseQuestionKind QuestionKind = "true_false"
-)
+	args := pgx.NamedArgs{
+		"questionIds": ids,
+	}
 
-type PostgresGameStorer struct {
-	pool *pgxpool.Pool
-}
+	query := `SELECT question_id, data, correct, media, format, rendered FROM quiz_questions WHERE question_id = ANY(@questionIds) ORDER BY question_id, "order"`
 
-func NewPostgresGameStorer(pool *pgxpool.Pool) *PostgresGameStorer {
-	return &PostgresGameStorer{
-		pool: pool,
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var questionId uuid.UUID
+		var alternative game.Alternative
+		var media []byte
+
+		err := rows.Scan(
+			&questionId,
+			&alternative.Data,
+			&alternative.IsCorrect,
+			&media,
+			&alternative.Format,
+			&alternative.Rendered,
+		)
+		if err != nil {
+			return err
+		}
+
+		alternative.Media, err = decodeMedia(media)
+		if err != nil {
+			return err
+		}
+
+		q := quizzes[questionId]
+		q.Alternatives = append(q.Alternatives, alternative)
 	}
+
+	return rows.Err()
 }
 
-func (p *PostgresGameStorer) StoreGame(
+func (p *PostgresGameStorer) loadTrueFalseAlternatives(
 	ctx context.Context,
-	game *game.Game,
+	trueFalses map[uuid.UUID]*game.TrueFalseQuestion,
 ) error {
-	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
-	if err != nil {
-		return err
+	if len(trueFalses) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(trueFalses))
+	for id := range trueFalses {
+		ids = append(ids, id)
 	}
 
 	args := pgx.NamedArgs{
-		"id":          game.Id,
-		"title":       game.Title,
-		"description": game.Description,
-		"owner_id":    game.OwnerId,
+		"questionIds": ids,
 	}
 
-	insert := `INSERT INTO games (id, title, description, owner_id) VALUES (@id, @title, @description, @owner_id)`
-	_, err = tx.Exec(ctx, insert, args)
+	query := `SELECT question_id, true_alternative, false_alternative, rendered_true_alternative, rendered_false_alternative FROM true_false_questions WHERE question_id = ANY(@questionIds)`
 
+	rows, err := p.pool.Query(ctx, query, args)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	for i, question := range game.Questions {
-		err := p.storeQuestion(ctx, tx.Conn(), game.Id, i, question)
+	for rows.Next() {
+		var questionId uuid.UUID
+		var trueAlternative, falseAlternative string
+		var renderedTrue, renderedFalse string
+
+		err := rows.Scan(
+			&questionId,
+			&trueAlternative,
+			&falseAlternative,
+			&renderedTrue,
+			&renderedFalse,
+		)
 		if err != nil {
 			return err
 		}
-	}
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		return tx.Rollback(ctx)
+		q := trueFalses[questionId]
+		q.TrueAlternative = trueAlternative
+		q.FalseAlternative = falseAlternative
+		q.RenderedTrueAlternative = renderedTrue
+		q.RenderedFalseAlternative = renderedFalse
 	}
-	return nil
-}
 
-func (p *PostgresGameStorer) UpdateGameInfo(ctx context.Context) error {
-	panic("not implemented") // TODO: Implement
+	return rows.Err()
 }
 
-func (p *PostgresGameStorer) UpdateGameQuestions(ctx context.Context) error {
-	panic("not implemented") // TODO: Implement
-}
+func (p *PostgresGameStorer) loadTypeAnswers(
+	ctx context.Context,
+	typeAnswers map[uuid.UUID]*game.TypeAnswerQuestion,
+) error {
+	if len(typeAnswers) == 0 {
+		return nil
+	}
 
-func (p *PostgresGameStorer) DeleteGame(ctx context.Context, id uuid.UUID) error {
-	args := pgx.NamedArgs{
-		"gameId": id,
+	ids := make([]uuid.UUID, 0, len(typeAnswers))
+	for id := range typeAnswers {
+		ids = append(ids, id)
 	}
 
-	query := "DELETE FROM games WHERE id = @gameId"
+	args := pgx.NamedArgs{
+		"questionIds": ids,
+	}
 
-	_, err := p.pool.Exec(ctx, query, args)
+	query := `SELECT question_id, accepted_answers, case_sensitive, accent_sensitive, tolerance, pattern FROM type_answer_questions WHERE question_id = ANY(@questionIds)`
 
+	rows, err := p.pool.Query(ctx, query, args)
 	if err != nil {
 		return err
 	}
+	defer rows.Close()
 
-	return nil
+	for rows.Next() {
+		var questionId uuid.UUID
+		var accepted []string
+		var caseSensitive, accentSensitive bool
+		var tolerance int
+		var pattern string
+
+		err := rows.Scan(
+			&questionId,
+			&accepted,
+			&caseSensitive,
+			&accentSensitive,
+			&tolerance,
+			&pattern,
+		)
+		if err != nil {
+			return err
+		}
+
+		q := typeAnswers[questionId]
+		q.AcceptedAnswers = accepted
+		q.CaseSensitive = caseSensitive
+		q.AccentSensitive = accentSensitive
+		q.Tolerance = tolerance
+		q.Pattern = pattern
+	}
+
+	return rows.Err()
 }
 
-func (p *PostgresGameStorer) FindGameById(
+func (p *PostgresGameStorer) loadOrderingItems(
 	ctx context.Context,
-	id uuid.UUID,
-) (*game.Game, error) {
+	orderings map[uuid.UUID]*game.OrderingQuestion,
+) error {
+	if len(orderings) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(orderings))
+	for id := range orderings {
+		ids = append(ids, id)
+	}
+
 	args := pgx.NamedArgs{
-		"gameId": id,
+		"questionIds": ids,
 	}
 
-	query := `SELECT id, title, description, owner_id FROM games WHERE id = @gameId;`
+	query := `SELECT question_id, items, rendered_items, partial_scoring FROM ordering_questions WHERE question_id = ANY(@questionIds)`
 
-	row := p.pool.QueryRow(ctx, query, args)
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var questionId uuid.UUID
+		var items, renderedItems []string
+		var partialScoring bool
+
+		err := rows.Scan(&questionId, &items, &renderedItems, &partialScoring)
+		if err != nil {
+			return err
+		}
+
+		q := orderings[questionId]
+		q.Items = items
+		q.RenderedItems = renderedItems
+		q.PartialScoring = partialScoring
+	}
+
+	return rows.Err()
+}
+
+func (p *PostgresGameStorer) loadPollOptions(
+	ctx context.Context,
+	polls map[uuid.UUID]*game.PollQuestion,
+) error {
+	if len(polls) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(polls))
+	for id := range polls {
+		ids = append(ids, id)
+	}
 
-	game := game.Game{Questions: nil}
+	args := pgx.NamedArgs{
+		"questionIds": ids,
+	}
 
-	err := row.Scan(&game.Id, &game.Title, &game.Description, &game.OwnerId)
+	query := `SELECT question_id, options, rendered_options FROM poll_questions WHERE question_id = ANY(@questionIds)`
 
+	rows, err := p.pool.Query(ctx, query, args)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, ports.ErrGameNotFound
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var questionId uuid.UUID
+		var options, renderedOptions []string
+
+		err := rows.Scan(&questionId, &options, &renderedOptions)
+		if err != nil {
+			return err
 		}
 
-		return nil, err
+		q := polls[questionId]
+		q.Options = options
+		q.RenderedOptions = renderedOptions
 	}
 
-	return &game, nil
+	return rows.Err()
 }
 
-func (p *PostgresGameStorer) FindAllGamesByUserId(
+func (p *PostgresGameStorer) loadSliderRanges(
 	ctx context.Context,
-	userId string,
-) ([]*game.Game, error) {
+	sliders map[uuid.UUID]*game.SliderQuestion,
+) error {
+	if len(sliders) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(sliders))
+	for id := range sliders {
+		ids = append(ids, id)
+	}
+
 	args := pgx.NamedArgs{
-		"userId": userId,
+		"questionIds": ids,
 	}
 
-	query := `SELECT id, title, description, owner_id FROM games WHERE owner_id = @userId;`
+	query := `SELECT question_id, min, max, step, target, tolerance, proportional_scoring FROM slider_questions WHERE question_id = ANY(@questionIds)`
 
 	rows, err := p.pool.Query(ctx, query, args)
-
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	games := []*game.Game{}
-
 	for rows.Next() {
-		game := game.Game{Questions: nil}
-
-		err := rows.Scan(&game.Id, &game.Title, &game.Description, &game.OwnerId)
+		var questionId uuid.UUID
+		var slider game.SliderQuestion
 
+		err := rows.Scan(
+			&questionId,
+			&slider.Min,
+			&slider.Max,
+			&slider.Step,
+			&slider.Target,
+			&slider.Tolerance,
+			&slider.ProportionalScoring,
+		)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		q := sliders[questionId]
+		q.Min = slider.Min
+		q.Max = slider.Max
+		q.Step = slider.Step
+		q.Target = slider.Target
+		q.Tolerance = slider.Tolerance
+		q.ProportionalScoring = slider.ProportionalScoring
+	}
+
+	return rows.Err()
+}
+
+const (
+	gameColumns       = `id, title, description, owner_id, visibility, forked_from, status, revision, published_at, base_points, speed_weight, streak_bonus, max_streak_bonus, category, difficulty, ARRAY(SELECT tag FROM game_tags WHERE game_tags.game_id = games.id ORDER BY tag), created_at, updated_at`
+	scoringPolicyJoin = `LEFT JOIN scoring_policies ON scoring_policies.game_id = games.id`
+	// draftAssignments turns an edited game back into a draft, moving on to
+	// the next revision when the edit is the first one since it was published
+	draftAssignments = `revision = revision + CASE WHEN status = 'published' THEN 1 ELSE 0 END, status = 'draft', updated_at = now()`
+)
+
+// scanGame scans a row selected with gameColumns followed by extra columns,
+// games without a scoring policy of their own are left with a nil one
+func scanGame(row pgx.Row, extra ...any) (*game.Game, error) {
+	g := game.Game{Questions: nil}
+
+	var basePoints, speedWeight, streakBonus, maxStreakBonus *int
+	dest := []any{
+		&g.Id,
+		&g.Title,
+		&g.Description,
+		&g.OwnerId,
+		&g.Visibility,
+		&g.ForkedFrom,
+		&g.Status,
+		&g.Revision,
+		&g.PublishedAt,
+		&basePoints,
+		&speedWeight,
+		&streakBonus,
+		&maxStreakBonus,
+		&g.Category,
+		&g.Difficulty,
+		&g.Tags,
+		&g.CreatedAt,
+		&g.UpdatedAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if basePoints != nil {
+		g.Scoring = &game.ScoringPolicy{
+			BasePoints:     *basePoints,
+			SpeedWeight:    *speedWeight,
+			StreakBonus:    *streakBonus,
+			MaxStreakBonus: *maxStreakBonus,
 		}
-		games = append(games, &game)
 	}
 
-	return games, nil
+	return &g, nil
+}
+
+// gameSortKeys are the columns the games of an owner can be sorted by, along
+// with the type cursor keys are cast to before being compared to them.
+var gameSortKeys = map[ports.GameSort]struct {
+	column string
+	cast   string
+}{
+	ports.CreatedGameSort: {column: "created_at", cast: "timestamptz"},
+	ports.UpdatedGameSort: {column: "updated_at", cast: "timestamptz"},
+	ports.TitleGameSort:   {column: "title", cast: "text"},
+}
+
+// metadataFilter keeps the games matching a ports.MetadataFilter, its
+// arguments being the ones of metadataFilterArgs.
+const metadataFilter = `(@category = '' OR category = @category)
+	AND (@difficulty = '' OR difficulty = @difficulty)
+	AND @tags::text[] <@ ARRAY(SELECT tag FROM game_tags WHERE game_tags.game_id = games.id)`
+
+// snapshotMetadataFilter is metadataFilter for the published version of
+// games, kept as JSON in game_snapshots.
+const snapshotMetadataFilter = `(@category = '' OR game_snapshots.data->>'category' = @category)
+	AND (@difficulty = '' OR game_snapshots.data->>'difficulty' = @difficulty)
+	AND (cardinality(@tags::text[]) = 0 OR game_snapshots.data->'tags' @> to_jsonb(@tags::text[]))`
+
+func metadataFilterArgs(filter ports.MetadataFilter) pgx.NamedArgs {
+	tags := filter.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return pgx.NamedArgs{
+		"category":   filter.Category,
+		"difficulty": string(filter.Difficulty),
+		"tags":       tags,
+	}
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern so that s is matched
+// literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (p *PostgresGameStorer) storeScoringPolicy(
+	ctx context.Context,
+	conn *pgx.Conn,
+	gameId uuid.UUID,
+	policy *game.ScoringPolicy,
+) error {
+	args := pgx.NamedArgs{
+		"game_id":          gameId,
+		"base_points":      policy.BasePoints,
+		"speed_weight":     policy.SpeedWeight,
+		"streak_bonus":     policy.StreakBonus,
+		"max_streak_bonus": policy.MaxStreakBonus,
+	}
+
+	insert := `INSERT INTO scoring_policies (game_id, base_points, speed_weight, streak_bonus, max_streak_bonus) VALUES (@game_id, @base_points, @speed_weight, @streak_bonus, @max_streak_bonus)`
+
+	_, err := conn.Exec(ctx, insert, args)
+	return err
 }
 
 func (p *PostgresGameStorer) storeQuestion(
@@ -157,38 +1296,71 @@ func (p *PostgresGameStorer) storeQuestion(
 	order int,
 	question game.Question,
 ) error {
-	var err error
-	var kind QuestionKind
 	id := uuid.New()
 
-	switch q := question.(type) {
-	case *game.QuizQuestion:
-		kind = QuizQuestionKind
-		err = p.storeQuizQuestion(ctx, conn, id, q)
-	case *game.TrueFalseQuestion:
-		kind = TrueFalseQuestionKind
-		err = p.storeTrueFalseQuestion(ctx, conn, id, q)
-	default:
-		err = ports.ErrUnknownQuestionKind
+	mapper := questionMapperOf(question.Kind())
+	if mapper.store != nil {
+		err := mapper.store(p, ctx, conn, id, question)
+		if err != nil {
+			return err
+		}
 	}
 
+	media, err := encodeMedia(question.GetMedia())
 	if err != nil {
 		return err
 	}
 
+	metadata := question.GetMetadata()
+
+	format, renderedTitle := game.PlainText, ""
+	if rendered, ok := question.(game.RenderedQuestion); ok {
+		format, renderedTitle = rendered.GetFormat(), rendered.GetRenderedTitle()
+	}
+
 	args := pgx.NamedArgs{
-		"id":         id,
-		"game_id":    gameId,
-		"order":      order,
-		"title":      question.GetTitle(),
-		"time_limit": question.GetTimeLimit(),
-		"points":     question.GetPoints(),
-		"kind":       kind,
+		"id":             id,
+		"game_id":        gameId,
+		"order":          order,
+		"title":          question.GetTitle(),
+		"time_limit":     question.GetTimeLimit(),
+		"points":         question.GetPoints(),
+		"kind":           question.Kind(),
+		"media":          media,
+		"format":         format,
+		"rendered_title": renderedTitle,
+		"category":       metadata.Category,
+		"difficulty":     metadata.Difficulty,
 	}
 
-	insert := `INSERT INTO questions (id, game_id, "order", kind, title, time_limit, points) VALUES (@id, @game_id, @order, @kind, @title, @time_limit, @points)`
+	insert := `INSERT INTO questions (id, game_id, "order", kind, title, time_limit, points, media, format, rendered_title, category, difficulty) VALUES (@id, @game_id, @order, @kind, @title, @time_limit, @points, @media, @format, @rendered_title, @category, @difficulty)`
 	_, err = conn.Exec(ctx, insert, args)
+	if err != nil {
+		return err
+	}
+
+	return storeTags(ctx, conn, "question_tags", id, metadata.Tags)
+}
+
+// storeTags inserts the tags of the game or question with the given id into
+// table, one of game_tags or question_tags.
+func storeTags(
+	ctx context.Context,
+	conn *pgx.Conn,
+	table string,
+	id uuid.UUID,
+	tags []string,
+) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	args := pgx.NamedArgs{
+		"id":   id,
+		"tags": tags,
+	}
 
+	_, err := conn.Exec(ctx, `INSERT INTO `+table+` SELECT @id, unnest(@tags::text[])`, args)
 	return err
 }
 
@@ -198,18 +1370,41 @@ func (p *PostgresGameStorer) storeQuizQuestion(
 	questionId uuid.UUID,
 	question *game.QuizQuestion,
 ) error {
-	INSERT := `INSERT INTO quiz_questions (id, question_id, "order", data, correct) VALUES (@id, @question_id, @order, @data, @correct)`
+	settings := pgx.NamedArgs{
+		"question_id": questionId,
+		"mode":        question.ModeOrDefault(),
+		"credit":      question.CreditOrDefault(),
+	}
+
+	_, err := conn.Exec(
+		ctx,
+		`INSERT INTO quiz_settings (question_id, mode, credit) VALUES (@question_id, @mode, @credit)`,
+		settings,
+	)
+	if err != nil {
+		return err
+	}
+
+	INSERT := `INSERT INTO quiz_questions (id, question_id, "order", data, correct, media, format, rendered) VALUES (@id, @question_id, @order, @data, @correct, @media, @format, @rendered)`
 
 	for i, alternative := range question.Alternatives {
+		media, err := encodeMedia(alternative.Media)
+		if err != nil {
+			return err
+		}
+
 		args := pgx.NamedArgs{
 			"id":          uuid.New(),
 			"question_id": questionId,
 			"order":       i,
 			"data":        alternative.Data,
 			"correct":     alternative.IsCorrect,
+			"media":       media,
+			"format":      alternative.Format,
+			"rendered":    alternative.Rendered,
 		}
 
-		_, err := conn.Exec(ctx, INSERT, args)
+		_, err = conn.Exec(ctx, INSERT, args)
 		if err != nil {
 			return err
 		}
@@ -225,12 +1420,117 @@ func (p *PostgresGameStorer) storeTrueFalseQuestion(
 	question *game.TrueFalseQuestion,
 ) error {
 	args := pgx.NamedArgs{
-		"question_id":       questionId,
-		"true_alternative":  question.TrueAlternative,
-		"false_alternative": question.FalseAlternative,
+		"question_id":                questionId,
+		"true_alternative":           question.TrueAlternative,
+		"false_alternative":          question.FalseAlternative,
+		"rendered_true_alternative":  question.RenderedTrueAlternative,
+		"rendered_false_alternative": question.RenderedFalseAlternative,
+	}
+
+	insert := `INSERT INTO true_false_questions (question_id, true_alternative, false_alternative, rendered_true_alternative, rendered_false_alternative) VALUES (@question_id, @true_alternative, @false_alternative, @rendered_true_alternative, @rendered_false_alternative)`
+
+	_, err := conn.Exec(ctx, insert, args)
+	return err
+}
+
+func (p *PostgresGameStorer) storeTypeAnswerQuestion(
+	ctx context.Context,
+	conn *pgx.Conn,
+	questionId uuid.UUID,
+	question *game.TypeAnswerQuestion,
+) error {
+	accepted := question.AcceptedAnswers
+	if accepted == nil {
+		accepted = []string{}
+	}
+
+	args := pgx.NamedArgs{
+		"question_id":      questionId,
+		"accepted_answers": accepted,
+		"case_sensitive":   question.CaseSensitive,
+		"accent_sensitive": question.AccentSensitive,
+		"tolerance":        question.Tolerance,
+		"pattern":          question.Pattern,
+	}
+
+	insert := `INSERT INTO type_answer_questions (question_id, accepted_answers, case_sensitive, accent_sensitive, tolerance, pattern) VALUES (@question_id, @accepted_answers, @case_sensitive, @accent_sensitive, @tolerance, @pattern)`
+
+	_, err := conn.Exec(ctx, insert, args)
+	return err
+}
+
+func (p *PostgresGameStorer) storeOrderingQuestion(
+	ctx context.Context,
+	conn *pgx.Conn,
+	questionId uuid.UUID,
+	question *game.OrderingQuestion,
+) error {
+	items := question.Items
+	if items == nil {
+		items = []string{}
+	}
+	renderedItems := question.RenderedItems
+	if renderedItems == nil {
+		renderedItems = []string{}
+	}
+
+	args := pgx.NamedArgs{
+		"question_id":     questionId,
+		"items":           items,
+		"rendered_items":  renderedItems,
+		"partial_scoring": question.PartialScoring,
+	}
+
+	insert := `INSERT INTO ordering_questions (question_id, items, rendered_items, partial_scoring) VALUES (@question_id, @items, @rendered_items, @partial_scoring)`
+
+	_, err := conn.Exec(ctx, insert, args)
+	return err
+}
+
+func (p *PostgresGameStorer) storeSliderQuestion(
+	ctx context.Context,
+	conn *pgx.Conn,
+	questionId uuid.UUID,
+	question *game.SliderQuestion,
+) error {
+	args := pgx.NamedArgs{
+		"question_id":          questionId,
+		"min":                  question.Min,
+		"max":                  question.Max,
+		"step":                 question.Step,
+		"target":               question.Target,
+		"tolerance":            question.Tolerance,
+		"proportional_scoring": question.ProportionalScoring,
+	}
+
+	insert := `INSERT INTO slider_questions (question_id, min, max, step, target, tolerance, proportional_scoring) VALUES (@question_id, @min, @max, @step, @target, @tolerance, @proportional_scoring)`
+
+	_, err := conn.Exec(ctx, insert, args)
+	return err
+}
+
+func (p *PostgresGameStorer) storePollQuestion(
+	ctx context.Context,
+	conn *pgx.Conn,
+	questionId uuid.UUID,
+	question *game.PollQuestion,
+) error {
+	options := question.Options
+	if options == nil {
+		options = []string{}
+	}
+	renderedOptions := question.RenderedOptions
+	if renderedOptions == nil {
+		renderedOptions = []string{}
+	}
+
+	args := pgx.NamedArgs{
+		"question_id":      questionId,
+		"options":          options,
+		"rendered_options": renderedOptions,
 	}
 
-	insert := `INSERT INTO true_false_questions (question_id, true_alternative, false_alternative) VALUES (@question_id, @true_alternative, @false_alternative)`
+	insert := `INSERT INTO poll_questions (question_id, options, rendered_options) VALUES (@question_id, @options, @rendered_options)`
 
 	_, err := conn.Exec(ctx, insert, args)
 	return err
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"encoding/json"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+// gameDocument is how a whole game is stored in a single JSONB column, e.g.
+// when it is frozen as a snapshot.
+type gameDocument struct {
+	*game.Game
+	Questions []questionDocument `json:"questions"`
+}
+
+// questionDocument tags a question with its kind so that it can be decoded
+// back into the right type.
+type questionDocument struct {
+	Kind game.Kind       `json:"kind"`
+	Data json.RawMessage `json:"data"`
+}
+
+func encodeGame(g *game.Game) ([]byte, error) {
+	doc := gameDocument{
+		Game:      g,
+		Questions: make([]questionDocument, len(g.Questions)),
+	}
+
+	for i, question := range g.Questions {
+		data, err := json.Marshal(question)
+		if err != nil {
+			return nil, err
+		}
+
+		doc.Questions[i] = questionDocument{Kind: question.Kind(), Data: data}
+	}
+
+	return json.Marshal(doc)
+}
+
+func decodeGame(data []byte) (*game.Game, error) {
+	doc := gameDocument{Game: &game.Game{}}
+
+	err := json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	g := doc.Game
+	g.Questions = make([]game.Question, len(doc.Questions))
+
+	for i, q := range doc.Questions {
+		question, err := game.DecodeQuestion(q.Kind, q.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		g.Questions[i] = question
+	}
+
+	return g, nil
+}
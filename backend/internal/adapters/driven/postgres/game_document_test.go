@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+const ratingKind game.Kind = "test_rating"
+
+// ratingQuestion is a kind only known to the tests, registered without a
+// table of its own.
+type ratingQuestion struct {
+	game.Metadata
+	Id        uuid.UUID
+	Title     string
+	Points    game.Points
+	TimeLimit game.TimeLimit
+	Media     *game.Media
+	Stars     int
+	Labels    []string
+}
+
+func init() {
+	game.RegisterKind(game.KindSpec{
+		Kind: ratingKind,
+		New: func(header game.Header) game.Question {
+			return &ratingQuestion{
+				Id:        header.Id,
+				Title:     header.Title,
+				Points:    header.Points,
+				TimeLimit: header.TimeLimit,
+				Media:     header.Media,
+				Metadata:  header.Metadata,
+			}
+		},
+		DecodeAnswer: func(question game.Question, raw json.RawMessage) (any, error) {
+			var stars int
+			err := json.Unmarshal(raw, &stars)
+			return stars, err
+		},
+	})
+}
+
+func (q *ratingQuestion) Kind() game.Kind {
+	return ratingKind
+}
+
+func (q *ratingQuestion) GetTitle() string {
+	return q.Title
+}
+
+func (q *ratingQuestion) IsCorrect(answer any) bool {
+	return answer == q.Stars
+}
+
+func (q *ratingQuestion) GetPoints() game.Points {
+	return q.Points
+}
+
+func (q *ratingQuestion) GetTimeLimit() game.TimeLimit {
+	return q.TimeLimit
+}
+
+func (q *ratingQuestion) GetMedia() *game.Media {
+	return q.Media
+}
+
+func (q *ratingQuestion) Clone() game.Question {
+	clone := *q
+	clone.Id = uuid.New()
+	clone.Media = q.Media.Clone()
+	clone.Metadata = q.Metadata.Clone()
+
+	return &clone
+}
+
+func TestGameDocumentRoundTrip(t *testing.T) {
+	// Arrange
+	publishedAt := time.Now().UTC().Truncate(time.Second)
+	original := &game.Game{
+		Id:          uuid.New(),
+		Title:       testGameTitle,
+		Description: testGameDescription,
+		OwnerId:     uuid.NewString(),
+		Visibility:  game.PublicVisibility,
+		Status:      game.PublishedStatus,
+		Revision:    3,
+		PublishedAt: &publishedAt,
+		Scoring:     &game.ScoringPolicy{BasePoints: 100, SpeedWeight: 10},
+		Questions: []game.Question{
+			&game.QuizQuestion{
+				Id:        uuid.New(),
+				Title:     "testQuiz",
+				Points:    1,
+				TimeLimit: 30,
+				Format:    game.MarkdownText,
+				Media:     &game.Media{Id: uuid.New(), Kind: game.VideoMedia, Start: 1, End: 4},
+				Alternatives: []game.Alternative{
+					{Data: "a", IsCorrect: true, Media: &game.Media{Id: uuid.New(), Kind: game.ImageMedia}},
+					{Data: "b"},
+					{Data: "c"},
+				},
+			},
+			&game.TrueFalseQuestion{
+				Id:               uuid.New(),
+				Title:            "testTrueFalse",
+				Points:           2,
+				TimeLimit:        20,
+				TrueAlternative:  "yes",
+				FalseAlternative: "no",
+			},
+			&game.TypeAnswerQuestion{
+				Id:              uuid.New(),
+				Title:           "testTypeAnswer",
+				Points:          1,
+				TimeLimit:       30,
+				AcceptedAnswers: []string{"color", "colour"},
+				Tolerance:       1,
+				Pattern:         `colou?r`,
+			},
+			&game.OrderingQuestion{
+				Id:             uuid.New(),
+				Title:          "testOrdering",
+				Points:         1,
+				TimeLimit:      30,
+				Items:          []string{"first", "second", "third"},
+				PartialScoring: true,
+			},
+			&game.SliderQuestion{
+				Id:                  uuid.New(),
+				Title:               "testSlider",
+				Points:              1,
+				TimeLimit:           30,
+				Min:                 -10,
+				Max:                 10,
+				Step:                0.5,
+				Target:              2.5,
+				Tolerance:           1,
+				ProportionalScoring: true,
+			},
+			&game.PollQuestion{
+				Id:        uuid.New(),
+				Title:     "testPoll",
+				TimeLimit: 30,
+				Options:   []string{"yes", "no", "maybe"},
+			},
+			&game.WordCloudQuestion{
+				Id:        uuid.New(),
+				Title:     "testWordCloud",
+				TimeLimit: 30,
+			},
+			&ratingQuestion{
+				Id:        uuid.New(),
+				Title:     "testRating",
+				Points:    1,
+				TimeLimit: 30,
+				Stars:     4,
+				Labels:    []string{"bad", "good"},
+			},
+		},
+	}
+
+	// Act
+	data, err := encodeGame(original)
+	assert.NoError(t, err)
+	decoded, err := decodeGame(data)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, original, decoded)
+}
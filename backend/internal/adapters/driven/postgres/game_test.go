@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,6 +13,7 @@ import (
 	testcontainers "github.com/testcontainers/testcontainers-go/modules/postgres"
 
 	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
 	"github.com/taldoflemis/brain.test/test/helpers"
 )
 
@@ -374,3 +376,997 @@ func (suite *PostgresGameStorerTestSuite) TestStoreGameWithDiverseQuestions() {
 	assert.NoError(t, err)
 	assert.Equal(t, len(trueFalseQuestions), amount)
 }
+
+func (suite *PostgresGameStorerTestSuite) TestStoreGameWithScoringPolicy() {
+	// Arrange
+	t := suite.T()
+	withPolicy := suite.generateMockedGame()
+	withPolicy.Scoring = &game.ScoringPolicy{
+		BasePoints:     500,
+		SpeedWeight:    25,
+		StreakBonus:    50,
+		MaxStreakBonus: 200,
+	}
+	withoutPolicy := suite.generateMockedGame()
+	withoutPolicy.Id = uuid.New()
+
+	// Act
+	err := suite.repo.StoreGame(suite.ctx, withPolicy)
+	assert.NoError(t, err)
+	err = suite.repo.StoreGame(suite.ctx, withoutPolicy)
+	assert.NoError(t, err)
+
+	// Assert
+	found, err := suite.repo.FindGameById(suite.ctx, withPolicy.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, withPolicy.Scoring, found.Scoring)
+
+	found, err = suite.repo.FindGameById(suite.ctx, withoutPolicy.Id)
+	assert.NoError(t, err)
+	assert.Nil(t, found.Scoring)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestUpdateGameInfo() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	mockedGame.Scoring = game.DefaultScoringPolicy()
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+	assert.NoError(t, err)
+
+	// Act
+	err = suite.repo.UpdateGameInfo(suite.ctx, mockedGame.Id, &ports.GameInfo{
+		Title:       "new title",
+		Description: "new description",
+	})
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "new title", found.Title)
+	assert.Equal(t, "new description", found.Description)
+	assert.Nil(t, found.Scoring)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestUpdateUnknownGame() {
+	// Arrange
+	t := suite.T()
+
+	// Act
+	infoErr := suite.repo.UpdateGameInfo(suite.ctx, uuid.New(), &ports.GameInfo{
+		Title:       "new title",
+		Description: "new description",
+	})
+	questionsErr := suite.repo.UpdateGameQuestions(suite.ctx, uuid.New(), []game.Question{})
+	gameErr := suite.repo.UpdateGame(suite.ctx, uuid.New(), &ports.GameInfo{
+		Title:       "new title",
+		Description: "new description",
+	}, []game.Question{})
+
+	// Assert
+	assert.ErrorIs(t, infoErr, ports.ErrGameNotFound)
+	assert.ErrorIs(t, questionsErr, ports.ErrGameNotFound)
+	assert.ErrorIs(t, gameErr, ports.ErrGameNotFound)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestUpdateGame() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	mockedGame.Scoring = game.DefaultScoringPolicy()
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+	assert.NoError(t, err)
+
+	questions := []game.Question{
+		&game.TrueFalseQuestion{
+			Title:            "new question",
+			Points:           2,
+			TimeLimit:        20,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+	}
+
+	// Act
+	err = suite.repo.UpdateGame(suite.ctx, mockedGame.Id, &ports.GameInfo{
+		Title:       "new title",
+		Description: "new description",
+	}, questions)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "new title", found.Title)
+	assert.Equal(t, "new description", found.Description)
+	assert.Nil(t, found.Scoring)
+	assert.Len(t, found.Questions, 1)
+	assert.Equal(t, "new question", found.Questions[0].GetTitle())
+}
+
+func (suite *PostgresGameStorerTestSuite) TestUpdateGameQuestions() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	mockedGame.Questions = []game.Question{
+		&game.QuizQuestion{
+			Title:     "old quiz",
+			Points:    1,
+			TimeLimit: 30,
+			Alternatives: []game.Alternative{
+				{Data: "testAlternative 1", IsCorrect: true},
+				{Data: "testAlternative 2"},
+				{Data: "testAlternative 3"},
+			},
+		},
+	}
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+	assert.NoError(t, err)
+
+	questions := []game.Question{
+		&game.TrueFalseQuestion{
+			Title:            "new question",
+			Points:           2,
+			TimeLimit:        20,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+	}
+
+	// Act
+	err = suite.repo.UpdateGameQuestions(suite.ctx, mockedGame.Id, questions)
+
+	// Assert
+	assert.NoError(t, err)
+
+	var title string
+	var amount int
+	err = suite.pool.QueryRow(
+		suite.ctx,
+		`SELECT title, COUNT(*) OVER () FROM questions WHERE game_id = $1`,
+		mockedGame.Id,
+	).Scan(&title, &amount)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, amount)
+	assert.Equal(t, "new question", title)
+
+	err = suite.pool.QueryRow(suite.ctx, `SELECT COUNT(*) FROM quiz_questions`).Scan(&amount)
+	assert.NoError(t, err)
+	assert.Zero(t, amount)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestFindGameByIdLoadsQuestions() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	mockedGame.Questions = []game.Question{
+		&game.TrueFalseQuestion{
+			Title:            "first",
+			Points:           1,
+			TimeLimit:        30,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+		&game.QuizQuestion{
+			Title:     "second",
+			Points:    2,
+			TimeLimit: 20,
+			Alternatives: []game.Alternative{
+				{Data: "testAlternative 1"},
+				{Data: "testAlternative 2", IsCorrect: true},
+				{Data: "testAlternative 3"},
+			},
+		},
+		&game.TrueFalseQuestion{
+			Title:            "third",
+			Points:           0,
+			TimeLimit:        10,
+			TrueAlternative:  "yes",
+			FalseAlternative: "no",
+		},
+	}
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+	assert.NoError(t, err)
+
+	// Act
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, found.Questions, len(mockedGame.Questions))
+
+	for i, expected := range mockedGame.Questions {
+		suite.assertQuestions(t, expected, found.Questions[i])
+	}
+
+	quiz, ok := found.Questions[1].(*game.QuizQuestion)
+	assert.True(t, ok)
+	assert.Equal(t, mockedGame.Questions[1].(*game.QuizQuestion).Alternatives, quiz.Alternatives)
+
+	trueFalse, ok := found.Questions[2].(*game.TrueFalseQuestion)
+	assert.True(t, ok)
+	assert.Equal(t, "yes", trueFalse.TrueAlternative)
+	assert.Equal(t, "no", trueFalse.FalseAlternative)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestFindAllGamesByUserId() {
+	// Arrange
+	t := suite.T()
+	first := suite.generateMockedGame()
+	first.Questions = []game.Question{
+		&game.TrueFalseQuestion{
+			Title:            "first",
+			Points:           1,
+			TimeLimit:        30,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+	}
+	second := suite.generateMockedGame()
+	second.Id = uuid.New()
+	second.Questions = []game.Question{
+		&game.QuizQuestion{
+			Title:     "second",
+			Points:    1,
+			TimeLimit: 20,
+			Alternatives: []game.Alternative{
+				{Data: "testAlternative 1", IsCorrect: true},
+				{Data: "testAlternative 2"},
+				{Data: "testAlternative 3"},
+			},
+		},
+	}
+
+	for _, g := range []*game.Game{first, second} {
+		err := suite.repo.StoreGame(suite.ctx, g)
+		assert.NoError(t, err)
+	}
+
+	table := []struct {
+		desc      string
+		opts      ports.FindGamesOptions
+		questions int
+	}{
+		{
+			desc:      "full games",
+			opts:      ports.FindGamesOptions{},
+			questions: 1,
+		},
+		{
+			desc:      "summaries only",
+			opts:      ports.FindGamesOptions{SummaryOnly: true},
+			questions: 0,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			games, total, err := suite.repo.FindAllGamesByUserId(suite.ctx, first.OwnerId, tt.opts)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Len(t, games, 2)
+			assert.Equal(t, 2, total)
+			for _, g := range games {
+				assert.Len(t, g.Questions, tt.questions)
+			}
+		})
+	}
+}
+
+func (suite *PostgresGameStorerTestSuite) TestFindAllGamesByUserIdPages() {
+	// Arrange
+	t := suite.T()
+	ownerId := uuid.NewString()
+	games := map[string]*game.Game{}
+	for _, title := range []string{"cells", "atoms", "bees"} {
+		g := suite.generateMockedGame()
+		g.Id = uuid.New()
+		g.OwnerId = ownerId
+		g.Title = title
+		err := suite.repo.StoreGame(suite.ctx, g)
+		assert.NoError(t, err)
+		games[title] = g
+	}
+	cursor := func(title string) *ports.GameCursor {
+		return &ports.GameCursor{Key: title, Id: games[title].Id}
+	}
+
+	table := []struct {
+		desc     string
+		opts     ports.FindGamesOptions
+		expected []string
+		total    int
+	}{
+		{
+			desc:     "first page",
+			opts:     ports.FindGamesOptions{Sort: ports.TitleGameSort, Limit: 2},
+			expected: []string{"atoms", "bees"},
+			total:    3,
+		},
+		{
+			desc:     "page after",
+			opts:     ports.FindGamesOptions{Sort: ports.TitleGameSort, Limit: 2, After: cursor("bees")},
+			expected: []string{"cells"},
+			total:    3,
+		},
+		{
+			desc:     "page before",
+			opts:     ports.FindGamesOptions{Sort: ports.TitleGameSort, Limit: 1, Before: cursor("cells")},
+			expected: []string{"bees"},
+			total:    3,
+		},
+		{
+			desc: "descending page after",
+			opts: ports.FindGamesOptions{
+				Sort:       ports.TitleGameSort,
+				Descending: true,
+				After:      cursor("cells"),
+			},
+			expected: []string{"bees", "atoms"},
+			total:    3,
+		},
+		{
+			desc:     "search",
+			opts:     ports.FindGamesOptions{Sort: ports.TitleGameSort, Search: "E"},
+			expected: []string{"bees", "cells"},
+			total:    2,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			found, total, err := suite.repo.FindAllGamesByUserId(suite.ctx, ownerId, tt.opts)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.total, total)
+			titles := make([]string, len(found))
+			for i, g := range found {
+				titles[i] = g.Title
+			}
+			assert.Equal(t, tt.expected, titles)
+		})
+	}
+
+	// Act
+	err := suite.repo.UpdateGameQuestions(suite.ctx, games["atoms"].Id, games["atoms"].Questions)
+	updated, _, updatedErr := suite.repo.FindAllGamesByUserId(suite.ctx, ownerId, ports.FindGamesOptions{
+		Sort:       ports.UpdatedGameSort,
+		Descending: true,
+		Limit:      1,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, updatedErr)
+	assert.Equal(t, games["atoms"].Id, updated[0].Id)
+	assert.True(t, updated[0].UpdatedAt.After(updated[0].CreatedAt))
+	assert.Equal(t, games["atoms"].CreatedAt, updated[0].CreatedAt)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestDeleteGame() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	mockedGame.Scoring = game.DefaultScoringPolicy()
+	mockedGame.Questions = []game.Question{
+		&game.TrueFalseQuestion{
+			Title:            "testQuestion",
+			Points:           1,
+			TimeLimit:        30,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+		&game.QuizQuestion{
+			Title:     "quiz",
+			Points:    1,
+			TimeLimit: 30,
+			Alternatives: []game.Alternative{
+				{Data: "testAlternative 1", IsCorrect: true},
+				{Data: "testAlternative 2"},
+				{Data: "testAlternative 3"},
+			},
+		},
+	}
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+	assert.NoError(t, err)
+
+	// Act
+	err = suite.repo.DeleteGame(suite.ctx, mockedGame.Id)
+	errAgain := suite.repo.DeleteGame(suite.ctx, mockedGame.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, errAgain, ports.ErrGameNotFound)
+
+	var amount int
+	err = suite.pool.QueryRow(suite.ctx, `SELECT COUNT(*) FROM questions`).Scan(&amount)
+	assert.NoError(t, err)
+	assert.Zero(t, amount)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestFindPublicGames() {
+	// Arrange
+	t := suite.T()
+	storeGame := func(title string, visibility game.Visibility, plays int) *game.Game {
+		g := suite.generateMockedGame()
+		g.Id = uuid.New()
+		g.Title = title
+		g.Visibility = visibility
+		err := suite.repo.StoreGame(suite.ctx, g)
+		assert.NoError(t, err)
+		err = suite.repo.PublishGame(suite.ctx, g.Snapshot(time.Now()))
+		assert.NoError(t, err)
+
+		for i := 0; i < plays; i++ {
+			_, err := suite.pool.Exec(
+				suite.ctx,
+				`INSERT INTO session_results (id, pin, game_id, game_title, host_id, started_at, finished_at) VALUES ($1, '123456', $2, $3, $4, now(), now())`,
+				uuid.New(),
+				g.Id,
+				g.Title,
+				g.OwnerId,
+			)
+			assert.NoError(t, err)
+		}
+
+		return g
+	}
+
+	older := storeGame("geography 100%", game.PublicVisibility, 2)
+	newer := storeGame("history", game.PublicVisibility, 0)
+	storeGame("private geography", game.PrivateVisibility, 5)
+	storeGame("unlisted geography", game.UnlistedVisibility, 5)
+	hidden := storeGame("hidden geography", game.PublicVisibility, 0)
+	err := suite.repo.UpdateGameInfo(suite.ctx, hidden.Id, &ports.GameInfo{
+		Title:      hidden.Title,
+		Visibility: game.PrivateVisibility,
+	})
+	assert.NoError(t, err)
+	err = suite.repo.UpdateGameInfo(suite.ctx, newer.Id, &ports.GameInfo{
+		Title:      "draft geography",
+		Visibility: game.PublicVisibility,
+	})
+	assert.NoError(t, err)
+	draft := suite.generateMockedGame()
+	draft.Id = uuid.New()
+	draft.Title = "unpublished geography"
+	draft.Visibility = game.PublicVisibility
+	err = suite.repo.StoreGame(suite.ctx, draft)
+	assert.NoError(t, err)
+	_, err = suite.pool.Exec(
+		suite.ctx,
+		`UPDATE games SET created_at = now() - interval '1 day' WHERE id = $1`,
+		older.Id,
+	)
+	assert.NoError(t, err)
+
+	table := []struct {
+		desc     string
+		query    ports.PublicGamesQuery
+		expected []uuid.UUID
+		total    int
+	}{
+		{
+			desc:     "most recent first",
+			query:    ports.PublicGamesQuery{Sort: ports.RecentGameSort, Page: 1, PageSize: 10},
+			expected: []uuid.UUID{newer.Id, older.Id},
+			total:    2,
+		},
+		{
+			desc:     "most played first",
+			query:    ports.PublicGamesQuery{Sort: ports.PlayCountGameSort, Page: 1, PageSize: 10},
+			expected: []uuid.UUID{older.Id, newer.Id},
+			total:    2,
+		},
+		{
+			desc:     "second page",
+			query:    ports.PublicGamesQuery{Sort: ports.RecentGameSort, Page: 2, PageSize: 1},
+			expected: []uuid.UUID{older.Id},
+			total:    2,
+		},
+		{
+			desc:     "search ignoring case",
+			query:    ports.PublicGamesQuery{Search: "GEOGRAPHY", Page: 1, PageSize: 10},
+			expected: []uuid.UUID{older.Id},
+			total:    1,
+		},
+		{
+			desc:     "search with wildcards",
+			query:    ports.PublicGamesQuery{Search: "%", Page: 1, PageSize: 10},
+			expected: []uuid.UUID{older.Id},
+			total:    1,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			listings, total, err := suite.repo.FindPublicGames(suite.ctx, tt.query)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.total, total)
+
+			ids := make([]uuid.UUID, len(listings))
+			for i, l := range listings {
+				ids[i] = l.Game.Id
+				if l.Game.Id == newer.Id {
+					assert.Equal(t, "history", l.Game.Title)
+					assert.Equal(t, len(newer.Questions), l.QuestionCount)
+				}
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func (suite *PostgresGameStorerTestSuite) TestPublishGame() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	mockedGame.Questions = []game.Question{
+		&game.TrueFalseQuestion{
+			Title:            "testQuestion",
+			Points:           1,
+			TimeLimit:        30,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+	}
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+	assert.NoError(t, err)
+	_, unpublishedErr := suite.repo.FindPublishedGame(suite.ctx, mockedGame.Id)
+
+	// Act
+	err = suite.repo.PublishGame(suite.ctx, mockedGame.Snapshot(time.Now()))
+	assert.NoError(t, err)
+	err = suite.repo.UpdateGameInfo(suite.ctx, mockedGame.Id, &ports.GameInfo{
+		Title:       "new title",
+		Description: testGameDescription,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, unpublishedErr, ports.ErrGameNotPublished)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, game.DraftStatus, found.Status)
+	assert.Equal(t, 2, found.Revision)
+	assert.NotNil(t, found.PublishedAt)
+
+	published, err := suite.repo.FindPublishedGame(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, testGameTitle, published.Title)
+	assert.Equal(t, game.PublishedStatus, published.Status)
+	assert.Equal(t, 1, published.Revision)
+	assert.Len(t, published.Questions, 1)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestStoreQuizSettings() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	mockedGame.Questions = []game.Question{
+		&game.QuizQuestion{
+			Title:     "testQuiz",
+			Points:    1,
+			TimeLimit: 30,
+			Mode:      game.MultiSelectQuizMode,
+			Credit:    game.PenaltyCredit,
+			Alternatives: []game.Alternative{
+				{Data: "first", IsCorrect: true},
+				{Data: "second", IsCorrect: true},
+				{Data: "third"},
+			},
+		},
+		&game.QuizQuestion{
+			Title:     "testDefaultQuiz",
+			Points:    1,
+			TimeLimit: 30,
+			Alternatives: []game.Alternative{
+				{Data: "first", IsCorrect: true},
+				{Data: "second"},
+				{Data: "third"},
+			},
+		},
+	}
+
+	// Act
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, found.Questions, 2)
+
+	quiz := found.Questions[0].(*game.QuizQuestion)
+	assert.Equal(t, game.MultiSelectQuizMode, quiz.Mode)
+	assert.Equal(t, game.PenaltyCredit, quiz.Credit)
+
+	defaultQuiz := found.Questions[1].(*game.QuizQuestion)
+	assert.Equal(t, game.MultiSelectQuizMode, defaultQuiz.ModeOrDefault())
+	assert.Equal(t, game.AllOrNothingCredit, defaultQuiz.CreditOrDefault())
+}
+
+func (suite *PostgresGameStorerTestSuite) TestStoreGameWithTypeAnswerQuestions() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	question := &game.TypeAnswerQuestion{
+		Title:           "testTypeAnswer",
+		Points:          1,
+		TimeLimit:       30,
+		AcceptedAnswers: []string{"color", "colour"},
+		CaseSensitive:   true,
+		Tolerance:       2,
+		Pattern:         `colou?r`,
+	}
+	mockedGame.Questions = []game.Question{question}
+
+	// Act
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, found.Questions, 1)
+
+	typeAnswer, ok := found.Questions[0].(*game.TypeAnswerQuestion)
+	assert.True(t, ok)
+	question.Id = typeAnswer.Id
+	assert.Equal(t, question, typeAnswer)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestStoreGameWithOrderingQuestions() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	question := &game.OrderingQuestion{
+		Title:          "testOrdering",
+		Points:         1,
+		TimeLimit:      30,
+		Items:          []string{"first", "second", "third"},
+		PartialScoring: true,
+	}
+	mockedGame.Questions = []game.Question{question}
+
+	// Act
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, found.Questions, 1)
+
+	ordering, ok := found.Questions[0].(*game.OrderingQuestion)
+	assert.True(t, ok)
+	question.Id = ordering.Id
+	assert.Equal(t, question, ordering)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestStoreGameWithSliderQuestions() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	question := &game.SliderQuestion{
+		Title:               "testSlider",
+		Points:              1,
+		TimeLimit:           30,
+		Min:                 0,
+		Max:                 1000,
+		Step:                10,
+		Target:              340,
+		Tolerance:           20,
+		ProportionalScoring: true,
+	}
+	mockedGame.Questions = []game.Question{question}
+
+	// Act
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, found.Questions, 1)
+
+	slider, ok := found.Questions[0].(*game.SliderQuestion)
+	assert.True(t, ok)
+	question.Id = slider.Id
+	assert.Equal(t, question, slider)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestStoreGameWithUngradedQuestions() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	poll := &game.PollQuestion{
+		Title:     "testPoll",
+		TimeLimit: 30,
+		Options:   []string{"yes", "no", "maybe"},
+	}
+	wordCloud := &game.WordCloudQuestion{
+		Title:     "testWordCloud",
+		TimeLimit: 30,
+	}
+	mockedGame.Questions = []game.Question{poll, wordCloud}
+
+	// Act
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, found.Questions, 2)
+
+	foundPoll, ok := found.Questions[0].(*game.PollQuestion)
+	assert.True(t, ok)
+	poll.Id = foundPoll.Id
+	assert.Equal(t, poll, foundPoll)
+
+	foundWordCloud, ok := found.Questions[1].(*game.WordCloudQuestion)
+	assert.True(t, ok)
+	wordCloud.Id = foundWordCloud.Id
+	assert.Equal(t, wordCloud, foundWordCloud)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestStoreGameWithKindWithoutTables() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	question := &ratingQuestion{
+		Title:     "testRating",
+		Points:    2,
+		TimeLimit: 30,
+		Stars:     4,
+		Labels:    []string{"bad", "good"},
+	}
+	mockedGame.Questions = []game.Question{question}
+
+	// Act
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, found.Questions, 1)
+
+	rating, ok := found.Questions[0].(*ratingQuestion)
+	assert.True(t, ok)
+	question.Id = rating.Id
+	assert.Equal(t, question, rating)
+
+	err = suite.repo.UpdateGameQuestions(suite.ctx, mockedGame.Id, []game.Question{})
+	assert.NoError(t, err)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestStoreGameWithMedia() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	image := &game.Media{Id: uuid.New(), Kind: game.ImageMedia}
+	audio := &game.Media{Id: uuid.New(), Kind: game.AudioMedia, Start: 2.5, End: 10}
+	quiz := &game.QuizQuestion{
+		Title:     "testQuiz",
+		Points:    1,
+		TimeLimit: 30,
+		Media:     audio,
+		Alternatives: []game.Alternative{
+			{Data: "a", IsCorrect: true, Media: image},
+			{Data: "b"},
+		},
+	}
+	mockedGame.Questions = []game.Question{quiz}
+	unused := uuid.New()
+
+	// Act
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+
+	foundQuiz, ok := found.Questions[0].(*game.QuizQuestion)
+	assert.True(t, ok)
+	assert.Equal(t, audio, foundQuiz.Media)
+	assert.Equal(t, image, foundQuiz.Alternatives[0].Media)
+	assert.Nil(t, foundQuiz.Alternatives[1].Media)
+
+	unreferenced, err := suite.repo.FindUnreferencedMedia(
+		suite.ctx,
+		[]uuid.UUID{image.Id, audio.Id, unused},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{unused}, unreferenced)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestStoreGameWithFormattedText() {
+	// Arrange
+	t := suite.T()
+	mockedGame := suite.generateMockedGame()
+	quiz := &game.QuizQuestion{
+		Title:     "What is **H~2~O**?",
+		Points:    1,
+		TimeLimit: 30,
+		Format:    game.MarkdownText,
+		Alternatives: []game.Alternative{
+			{Data: "Water", IsCorrect: true},
+			{Data: `$\frac{1}{2}$`, Format: game.MarkdownLatexText},
+			{Data: "Salt"},
+		},
+	}
+	trueFalse := &game.TrueFalseQuestion{
+		Title:            "Is **H~2~O** water?",
+		Points:           1,
+		TimeLimit:        30,
+		Format:           game.MarkdownText,
+		TrueAlternative:  "*yes*",
+		FalseAlternative: "*no*",
+	}
+	ordering := &game.OrderingQuestion{
+		Title:     "Sort",
+		Points:    1,
+		TimeLimit: 30,
+		Format:    game.MarkdownText,
+		Items:     []string{"H~2~", "O~2~"},
+	}
+	poll := &game.PollQuestion{
+		Title:     "Pick",
+		TimeLimit: 30,
+		Format:    game.MarkdownText,
+		Options:   []string{"**a**", "b"},
+	}
+	mockedGame.Questions = []game.Question{quiz, trueFalse, ordering, poll}
+	mockedGame.Render()
+
+	// Act
+	err := suite.repo.StoreGame(suite.ctx, mockedGame)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindGameById(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+
+	foundQuiz, ok := found.Questions[0].(*game.QuizQuestion)
+	assert.True(t, ok)
+	assert.Equal(t, game.MarkdownText, foundQuiz.Format)
+	assert.Equal(t, "What is <strong>H<sub>2</sub>O</strong>?", foundQuiz.RenderedTitle)
+	assert.Equal(t, quiz.Alternatives, foundQuiz.Alternatives)
+
+	foundTrueFalse, ok := found.Questions[1].(*game.TrueFalseQuestion)
+	assert.True(t, ok)
+	assert.Equal(t, "<em>yes</em>", foundTrueFalse.RenderedTrueAlternative)
+	assert.Equal(t, "<em>no</em>", foundTrueFalse.RenderedFalseAlternative)
+
+	foundOrdering, ok := found.Questions[2].(*game.OrderingQuestion)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"H<sub>2</sub>", "O<sub>2</sub>"}, foundOrdering.RenderedItems)
+
+	foundPoll, ok := found.Questions[3].(*game.PollQuestion)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"<strong>a</strong>", "b"}, foundPoll.RenderedOptions)
+}
+
+func (suite *PostgresGameStorerTestSuite) TestGameMetadata() {
+	// Arrange
+	t := suite.T()
+	trueFalse := func(metadata game.Metadata) game.Question {
+		return &game.TrueFalseQuestion{
+			Metadata:         metadata,
+			Title:            "testQuestion",
+			Points:           1,
+			TimeLimit:        30,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		}
+	}
+	plants := suite.generateMockedGame()
+	plants.Metadata = game.Metadata{
+		Tags:       []string{"photosynthesis", "plants"},
+		Category:   "biology",
+		Difficulty: game.EasyDifficulty,
+	}
+	plants.Questions = []game.Question{
+		trueFalse(game.Metadata{Tags: []string{"chlorophyll"}, Difficulty: game.HardDifficulty}),
+	}
+	cells := suite.generateMockedGame()
+	cells.Id = uuid.New()
+	cells.Metadata = game.Metadata{Tags: []string{"cells"}, Category: "biology"}
+	cells.Questions = []game.Question{trueFalse(game.Metadata{Tags: []string{"plants"}})}
+
+	for _, g := range []*game.Game{plants, cells} {
+		err := suite.repo.StoreGame(suite.ctx, g)
+		assert.NoError(t, err)
+	}
+
+	table := []struct {
+		desc     string
+		filter   ports.MetadataFilter
+		expected []uuid.UUID
+	}{
+		{
+			desc:     "by category",
+			filter:   ports.MetadataFilter{Category: "biology"},
+			expected: []uuid.UUID{plants.Id, cells.Id},
+		},
+		{
+			desc:     "by every tag",
+			filter:   ports.MetadataFilter{Tags: []string{"plants", "photosynthesis"}},
+			expected: []uuid.UUID{plants.Id},
+		},
+		{
+			desc:     "by difficulty",
+			filter:   ports.MetadataFilter{Difficulty: game.EasyDifficulty},
+			expected: []uuid.UUID{plants.Id},
+		},
+		{
+			desc:     "tags of questions don't count",
+			filter:   ports.MetadataFilter{Tags: []string{"chlorophyll"}},
+			expected: []uuid.UUID{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			games, _, err := suite.repo.FindAllGamesByUserId(
+				suite.ctx,
+				plants.OwnerId,
+				ports.FindGamesOptions{SummaryOnly: true, Filter: tt.filter},
+			)
+
+			// Assert
+			assert.NoError(t, err)
+			ids := make([]uuid.UUID, len(games))
+			for i, g := range games {
+				ids[i] = g.Id
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+
+	// Act
+	found, findErr := suite.repo.FindGameById(suite.ctx, plants.Id)
+	infoErr := suite.repo.UpdateGameInfo(suite.ctx, cells.Id, &ports.GameInfo{
+		Title:       cells.Title,
+		Description: cells.Description,
+		Visibility:  game.PrivateVisibility,
+		Metadata:    game.Metadata{Tags: []string{"plants"}, Difficulty: game.MediumDifficulty},
+	})
+	tags, tagsErr := suite.repo.FindTagsByUserId(suite.ctx, plants.OwnerId, "p", 10)
+	otherTags, otherErr := suite.repo.FindTagsByUserId(suite.ctx, "someone else", "", 10)
+
+	// Assert
+	assert.NoError(t, findErr)
+	assert.Equal(t, plants.Metadata, found.Metadata)
+	assert.Equal(t, []string{"chlorophyll"}, found.Questions[0].GetMetadata().Tags)
+	assert.Equal(t, game.HardDifficulty, found.Questions[0].GetMetadata().Difficulty)
+
+	assert.NoError(t, infoErr)
+	assert.NoError(t, tagsErr)
+	assert.Equal(t, []string{"plants", "photosynthesis"}, tags)
+	assert.NoError(t, otherErr)
+	assert.Empty(t, otherTags)
+}
@@ -0,0 +1 @@
+DROP TABLE scoring_policies;
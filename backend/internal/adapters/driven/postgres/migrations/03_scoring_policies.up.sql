@@ -0,0 +1,8 @@
+CREATE TABLE scoring_policies(
+	game_id UUID PRIMARY KEY,
+	base_points INT NOT NULL,
+	speed_weight INT NOT NULL,
+	streak_bonus INT NOT NULL,
+	max_streak_bonus INT NOT NULL,
+	CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE
+);
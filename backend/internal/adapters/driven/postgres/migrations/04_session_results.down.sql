@@ -0,0 +1,3 @@
+DROP TABLE session_answers;
+DROP TABLE session_participants;
+DROP TABLE session_results;
@@ -0,0 +1,40 @@
+-- results don't reference games on purpose, they must outlive them
+CREATE TABLE session_results(
+	id UUID PRIMARY KEY,
+	pin TEXT NOT NULL,
+	game_id UUID NOT NULL,
+	game_title TEXT NOT NULL,
+	host_id TEXT NOT NULL,
+	started_at TIMESTAMPTZ NOT NULL,
+	finished_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX session_results_game_id ON session_results(game_id);
+CREATE INDEX session_results_host_id ON session_results(host_id);
+
+CREATE TABLE session_participants(
+	result_id UUID NOT NULL,
+	player_id UUID NOT NULL,
+	nickname TEXT NOT NULL,
+	score INT NOT NULL,
+	rank INT NOT NULL,
+	joined_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (result_id, player_id),
+	CONSTRAINT fk_result_id FOREIGN KEY(result_id) REFERENCES session_results(id) ON DELETE CASCADE
+);
+
+CREATE TABLE session_answers(
+	id UUID PRIMARY KEY,
+	result_id UUID NOT NULL,
+	player_id UUID NOT NULL,
+	question_index INT NOT NULL,
+	question_title TEXT NOT NULL,
+	value JSONB,
+	correct BOOLEAN NOT NULL,
+	points INT NOT NULL,
+	response_time_ms BIGINT NOT NULL,
+	answered_at TIMESTAMPTZ NOT NULL,
+	CONSTRAINT fk_participant FOREIGN KEY(result_id, player_id) REFERENCES session_participants(result_id, player_id) ON DELETE CASCADE
+);
+
+CREATE INDEX session_answers_result_id ON session_answers(result_id);
@@ -0,0 +1,8 @@
+ALTER TABLE true_false_questions DROP CONSTRAINT true_false_questions_question_id_fkey;
+ALTER TABLE true_false_questions ADD CONSTRAINT true_false_questions_question_id_fkey FOREIGN KEY(question_id) REFERENCES questions(id) DEFERRABLE INITIALLY DEFERRED;
+
+ALTER TABLE quiz_questions DROP CONSTRAINT fk_question_id;
+ALTER TABLE quiz_questions ADD CONSTRAINT fk_question_id FOREIGN KEY(question_id) REFERENCES questions(id) DEFERRABLE INITIALLY DEFERRED;
+
+ALTER TABLE questions DROP CONSTRAINT fk_game_id;
+ALTER TABLE questions ADD CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(id);
@@ -0,0 +1,4 @@
+DROP INDEX games_public_created_at;
+ALTER TABLE games DROP COLUMN created_at;
+ALTER TABLE games DROP CONSTRAINT games_visibility;
+ALTER TABLE games DROP COLUMN visibility;
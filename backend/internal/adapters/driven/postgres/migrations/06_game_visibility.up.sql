@@ -0,0 +1,5 @@
+ALTER TABLE games ADD COLUMN visibility TEXT NOT NULL DEFAULT 'private';
+ALTER TABLE games ADD CONSTRAINT games_visibility CHECK (visibility IN ('private', 'unlisted', 'public'));
+ALTER TABLE games ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX games_public_created_at ON games(created_at DESC) WHERE visibility = 'public';
@@ -0,0 +1,2 @@
+-- no foreign key, forks keep pointing at their origin after it is deleted
+ALTER TABLE games ADD COLUMN forked_from UUID;
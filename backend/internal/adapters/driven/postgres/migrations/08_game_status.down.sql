@@ -0,0 +1,5 @@
+DROP TABLE game_snapshots;
+ALTER TABLE games DROP COLUMN published_at;
+ALTER TABLE games DROP COLUMN revision;
+ALTER TABLE games DROP CONSTRAINT games_status;
+ALTER TABLE games DROP COLUMN status;
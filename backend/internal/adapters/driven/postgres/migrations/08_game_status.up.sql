@@ -0,0 +1,14 @@
+-- games created before this migration have no snapshot yet, so they start as
+-- drafts and have to be published once before being played
+ALTER TABLE games ADD COLUMN status TEXT NOT NULL DEFAULT 'draft';
+ALTER TABLE games ADD CONSTRAINT games_status CHECK (status IN ('draft', 'published'));
+ALTER TABLE games ADD COLUMN revision INT NOT NULL DEFAULT 1;
+ALTER TABLE games ADD COLUMN published_at TIMESTAMPTZ;
+
+CREATE TABLE game_snapshots(
+	game_id UUID PRIMARY KEY,
+	revision INT NOT NULL,
+	data JSONB NOT NULL,
+	published_at TIMESTAMPTZ NOT NULL,
+	CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+CREATE TABLE game_revisions(
+	game_id UUID NOT NULL,
+	number INT NOT NULL,
+	author_id TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	summary TEXT[] NOT NULL,
+	data JSONB NOT NULL,
+	PRIMARY KEY (game_id, number),
+	CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+DROP TABLE quiz_settings;
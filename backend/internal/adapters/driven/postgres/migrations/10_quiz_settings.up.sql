@@ -0,0 +1,9 @@
+-- quiz questions without settings are multi-select and scored all-or-nothing
+CREATE TABLE quiz_settings(
+	question_id UUID PRIMARY KEY,
+	mode TEXT NOT NULL,
+	credit TEXT NOT NULL,
+	CONSTRAINT quiz_settings_mode CHECK (mode IN ('single', 'multiple')),
+	CONSTRAINT quiz_settings_credit CHECK (credit IN ('all_or_nothing', 'proportional', 'penalty')),
+	CONSTRAINT fk_question_id FOREIGN KEY(question_id) REFERENCES questions(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
@@ -0,0 +1 @@
+DROP TABLE type_answer_questions;
@@ -0,0 +1,9 @@
+CREATE TABLE type_answer_questions(
+	question_id UUID PRIMARY KEY,
+	accepted_answers TEXT[] NOT NULL,
+	case_sensitive BOOLEAN NOT NULL,
+	accent_sensitive BOOLEAN NOT NULL,
+	tolerance INT NOT NULL,
+	pattern TEXT NOT NULL,
+	CONSTRAINT fk_question_id FOREIGN KEY(question_id) REFERENCES questions(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
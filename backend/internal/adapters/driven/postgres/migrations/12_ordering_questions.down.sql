@@ -0,0 +1 @@
+DROP TABLE ordering_questions;
@@ -0,0 +1,6 @@
+CREATE TABLE ordering_questions(
+	question_id UUID PRIMARY KEY,
+	items TEXT[] NOT NULL,
+	partial_scoring BOOLEAN NOT NULL,
+	CONSTRAINT fk_question_id FOREIGN KEY(question_id) REFERENCES questions(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
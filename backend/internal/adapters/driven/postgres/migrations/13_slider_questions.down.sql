@@ -0,0 +1 @@
+DROP TABLE slider_questions;
@@ -0,0 +1,10 @@
+CREATE TABLE slider_questions(
+	question_id UUID PRIMARY KEY,
+	min DOUBLE PRECISION NOT NULL,
+	max DOUBLE PRECISION NOT NULL,
+	step DOUBLE PRECISION NOT NULL,
+	target DOUBLE PRECISION NOT NULL,
+	tolerance DOUBLE PRECISION NOT NULL,
+	proportional_scoring BOOLEAN NOT NULL,
+	CONSTRAINT fk_question_id FOREIGN KEY(question_id) REFERENCES questions(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
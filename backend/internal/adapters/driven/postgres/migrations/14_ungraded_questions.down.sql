@@ -0,0 +1,3 @@
+ALTER TABLE session_results DROP COLUMN distributions;
+ALTER TABLE session_answers DROP COLUMN ungraded;
+DROP TABLE poll_questions;
@@ -0,0 +1,8 @@
+CREATE TABLE poll_questions(
+	question_id UUID PRIMARY KEY,
+	options TEXT[] NOT NULL,
+	CONSTRAINT fk_question_id FOREIGN KEY(question_id) REFERENCES questions(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
+
+ALTER TABLE session_answers ADD COLUMN ungraded BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE session_results ADD COLUMN distributions JSONB NOT NULL DEFAULT '[]';
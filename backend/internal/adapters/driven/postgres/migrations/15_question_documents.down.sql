@@ -0,0 +1 @@
+DROP TABLE question_documents;
@@ -0,0 +1,6 @@
+-- kinds without tables of their own keep their fields here as JSON
+CREATE TABLE question_documents(
+	question_id UUID PRIMARY KEY,
+	data JSONB NOT NULL,
+	CONSTRAINT fk_question_id FOREIGN KEY(question_id) REFERENCES questions(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED
+);
@@ -0,0 +1,2 @@
+ALTER TABLE quiz_questions DROP COLUMN media;
+ALTER TABLE questions DROP COLUMN media;
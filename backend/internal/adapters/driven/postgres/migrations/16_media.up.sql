@@ -0,0 +1,3 @@
+-- media are referenced as JSON, their files are kept by the media storer
+ALTER TABLE questions ADD COLUMN media JSONB;
+ALTER TABLE quiz_questions ADD COLUMN media JSONB;
@@ -0,0 +1,4 @@
+ALTER TABLE quiz_questions DROP COLUMN rendered;
+ALTER TABLE quiz_questions DROP COLUMN format;
+ALTER TABLE questions DROP COLUMN rendered_title;
+ALTER TABLE questions DROP COLUMN format;
@@ -0,0 +1,11 @@
+-- the source of the text is kept in title and data, along its render
+ALTER TABLE questions ADD COLUMN format TEXT NOT NULL DEFAULT 'plain';
+ALTER TABLE questions ADD COLUMN rendered_title TEXT NOT NULL DEFAULT '';
+ALTER TABLE quiz_questions ADD COLUMN format TEXT NOT NULL DEFAULT 'plain';
+ALTER TABLE quiz_questions ADD COLUMN rendered TEXT NOT NULL DEFAULT '';
+
+-- existing text is plain, its render is the text escaped like html.EscapeString
+UPDATE questions SET rendered_title = replace(replace(replace(replace(replace(replace(
+	title, '&', '&amp;'), '''', '&#39;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), E'\n', '<br>');
+UPDATE quiz_questions SET rendered = replace(replace(replace(replace(replace(replace(
+	data, '&', '&amp;'), '''', '&#39;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), E'\n', '<br>');
@@ -0,0 +1,2 @@
+DROP TABLE bank_question_tags;
+DROP TABLE bank_questions;
@@ -0,0 +1,22 @@
+-- questions saved apart from any game, kept whole as JSON like revisions
+CREATE TABLE bank_questions(
+	id UUID PRIMARY KEY,
+	owner_id TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	-- title without formatting, to search by
+	title TEXT NOT NULL,
+	data JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX bank_questions_owner_created_at ON bank_questions(owner_id, created_at DESC);
+
+CREATE TABLE bank_question_tags(
+	question_id UUID NOT NULL,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (question_id, tag),
+	CONSTRAINT fk_question_id FOREIGN KEY(question_id) REFERENCES bank_questions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX bank_question_tags_tag ON bank_question_tags(tag);
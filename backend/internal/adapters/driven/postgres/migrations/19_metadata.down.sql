@@ -0,0 +1,9 @@
+DROP TABLE question_tags;
+DROP TABLE game_tags;
+DROP INDEX games_owner_category;
+ALTER TABLE questions DROP CONSTRAINT questions_difficulty;
+ALTER TABLE questions DROP COLUMN difficulty;
+ALTER TABLE questions DROP COLUMN category;
+ALTER TABLE games DROP CONSTRAINT games_difficulty;
+ALTER TABLE games DROP COLUMN difficulty;
+ALTER TABLE games DROP COLUMN category;
@@ -0,0 +1,28 @@
+-- tags, category and difficulty only help owners find their games and
+-- questions, empty meaning unset
+ALTER TABLE games ADD COLUMN category TEXT NOT NULL DEFAULT '';
+ALTER TABLE games ADD COLUMN difficulty TEXT NOT NULL DEFAULT '';
+ALTER TABLE games ADD CONSTRAINT games_difficulty CHECK (difficulty IN ('', 'easy', 'medium', 'hard'));
+ALTER TABLE questions ADD COLUMN category TEXT NOT NULL DEFAULT '';
+ALTER TABLE questions ADD COLUMN difficulty TEXT NOT NULL DEFAULT '';
+ALTER TABLE questions ADD CONSTRAINT questions_difficulty CHECK (difficulty IN ('', 'easy', 'medium', 'hard'));
+
+CREATE INDEX games_owner_category ON games(owner_id, category);
+
+CREATE TABLE game_tags(
+	game_id UUID NOT NULL,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (game_id, tag),
+	CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+
+CREATE INDEX game_tags_tag ON game_tags(tag);
+
+CREATE TABLE question_tags(
+	question_id UUID NOT NULL,
+	tag TEXT NOT NULL,
+	PRIMARY KEY (question_id, tag),
+	CONSTRAINT fk_question_id FOREIGN KEY(question_id) REFERENCES questions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX question_tags_tag ON question_tags(tag);
@@ -0,0 +1,4 @@
+DROP INDEX games_owner_title;
+DROP INDEX games_owner_updated_at;
+DROP INDEX games_owner_created_at;
+ALTER TABLE games DROP COLUMN updated_at;
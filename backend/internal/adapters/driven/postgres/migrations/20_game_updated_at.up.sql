@@ -0,0 +1,9 @@
+-- updated_at is bumped by every edit of the game, games edited before it
+-- existed are taken as last edited when created
+ALTER TABLE games ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+UPDATE games SET updated_at = created_at;
+
+-- the keys owners list their games by, see PostgresGameStorer.FindAllGamesByUserId
+CREATE INDEX games_owner_created_at ON games(owner_id, created_at, id);
+CREATE INDEX games_owner_updated_at ON games(owner_id, updated_at, id);
+CREATE INDEX games_owner_title ON games(owner_id, title, id);
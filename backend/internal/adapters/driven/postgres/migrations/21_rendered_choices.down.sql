@@ -0,0 +1,4 @@
+ALTER TABLE poll_questions DROP COLUMN rendered_options;
+ALTER TABLE ordering_questions DROP COLUMN rendered_items;
+ALTER TABLE true_false_questions DROP COLUMN rendered_false_alternative;
+ALTER TABLE true_false_questions DROP COLUMN rendered_true_alternative;
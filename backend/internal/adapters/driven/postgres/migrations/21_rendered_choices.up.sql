@@ -0,0 +1,6 @@
+-- the choices of true or false, ordering and poll questions follow the format
+-- of their question, existing ones have no render and are rendered when shown
+ALTER TABLE true_false_questions ADD COLUMN rendered_true_alternative TEXT NOT NULL DEFAULT '';
+ALTER TABLE true_false_questions ADD COLUMN rendered_false_alternative TEXT NOT NULL DEFAULT '';
+ALTER TABLE ordering_questions ADD COLUMN rendered_items TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE poll_questions ADD COLUMN rendered_options TEXT[] NOT NULL DEFAULT '{}';
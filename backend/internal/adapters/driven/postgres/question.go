@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+// questionMapper stores and loads the fields specific to a kind of question,
+// what every question has lives in the questions table.
+type questionMapper struct {
+	// tables holding the fields of the kind, keyed by question_id
+	tables []string
+	store  func(p *PostgresGameStorer, ctx context.Context, conn *pgx.Conn, questionId uuid.UUID, question game.Question) error
+	// load fills in the questions, built from their header, by id
+	load func(p *PostgresGameStorer, ctx context.Context, questions map[uuid.UUID]game.Question) error
+}
+
+// newQuestionMapper adapts the functions storing and loading questions of
+// type Q to any question. Kinds with nothing besides the header pass nil.
+func newQuestionMapper[Q game.Question](
+	store func(p *PostgresGameStorer, ctx context.Context, conn *pgx.Conn, questionId uuid.UUID, question Q) error,
+	load func(p *PostgresGameStorer, ctx context.Context, questions map[uuid.UUID]Q) error,
+	tables ...string,
+) questionMapper {
+	mapper := questionMapper{tables: tables}
+
+	if store != nil {
+		mapper.store = func(
+			p *PostgresGameStorer,
+			ctx context.Context,
+			conn *pgx.Conn,
+			questionId uuid.UUID,
+			question game.Question,
+		) error {
+			q, ok := question.(Q)
+			if !ok {
+				return game.ErrUnknownQuestionKind
+			}
+
+			return store(p, ctx, conn, questionId, q)
+		}
+	}
+
+	if load != nil {
+		mapper.load = func(
+			p *PostgresGameStorer,
+			ctx context.Context,
+			questions map[uuid.UUID]game.Question,
+		) error {
+			typed := make(map[uuid.UUID]Q, len(questions))
+			for id, question := range questions {
+				q, ok := question.(Q)
+				if !ok {
+					return game.ErrUnknownQuestionKind
+				}
+				typed[id] = q
+			}
+
+			return load(p, ctx, typed)
+		}
+	}
+
+	return mapper
+}
+
+// questionMappers are the kinds with tables of their own, see
+// registerQuestionMapper. Kinds registered without one are kept as a document
+// instead, see documentMapper.
+var questionMappers = make(map[game.Kind]questionMapper)
+
+// registerQuestionMapper makes the tables of a kind known, every kind is
+// registered from the init below. Registering a kind twice panics.
+func registerQuestionMapper(kind game.Kind, mapper questionMapper) {
+	if _, ok := questionMappers[kind]; ok {
+		panic(fmt.Sprintf("postgres: question kind %q mapped twice", kind))
+	}
+
+	questionMappers[kind] = mapper
+}
+
+func init() {
+	registerQuestionMapper(game.QuizKind, newQuestionMapper(
+		(*PostgresGameStorer).storeQuizQuestion,
+		(*PostgresGameStorer).loadQuizQuestions,
+		"quiz_questions", "quiz_settings",
+	))
+	registerQuestionMapper(game.TrueFalseKind, newQuestionMapper(
+		(*PostgresGameStorer).storeTrueFalseQuestion,
+		(*PostgresGameStorer).loadTrueFalseAlternatives,
+		"true_false_questions",
+	))
+	registerQuestionMapper(game.TypeAnswerKind, newQuestionMapper(
+		(*PostgresGameStorer).storeTypeAnswerQuestion,
+		(*PostgresGameStorer).loadTypeAnswers,
+		"type_answer_questions",
+	))
+	registerQuestionMapper(game.OrderingKind, newQuestionMapper(
+		(*PostgresGameStorer).storeOrderingQuestion,
+		(*PostgresGameStorer).loadOrderingItems,
+		"ordering_questions",
+	))
+	registerQuestionMapper(game.SliderKind, newQuestionMapper(
+		(*PostgresGameStorer).storeSliderQuestion,
+		(*PostgresGameStorer).loadSliderRanges,
+		"slider_questions",
+	))
+	registerQuestionMapper(game.PollKind, newQuestionMapper(
+		(*PostgresGameStorer).storePollQuestion,
+		(*PostgresGameStorer).loadPollOptions,
+		"poll_questions",
+	))
+	// word clouds have nothing besides their header, nor a document
+	registerQuestionMapper(game.WordCloudKind, newQuestionMapper[*game.WordCloudQuestion](nil, nil))
+}
+
+// documentMapper keeps the fields of a question besides its header as JSON,
+// so that registering a kind is enough to store it.
+var documentMapper = questionMapper{
+	tables: []string{"question_documents"},
+	store:  (*PostgresGameStorer).storeQuestionDocument,
+	load:   (*PostgresGameStorer).loadQuestionDocuments,
+}
+
+// headerFields are the JSON fields of a question already kept in the
+// questions table.
+var headerFields = []string{"Id", "Title", "Points", "TimeLimit", "Media", "Format", "RenderedTitle", "tags", "category", "difficulty"}
+
+func questionMapperOf(kind game.Kind) questionMapper {
+	mapper, ok := questionMappers[kind]
+	if !ok {
+		return documentMapper
+	}
+
+	return mapper
+}
+
+// questionTables lists every table holding the fields of some kind, in a
+// stable order.
+func questionTables() []string {
+	seen := make(map[string]bool)
+	tables := []string{}
+
+	mappers := []questionMapper{documentMapper}
+	for _, mapper := range questionMappers {
+		mappers = append(mappers, mapper)
+	}
+
+	for _, mapper := range mappers {
+		for _, table := range mapper.tables {
+			if !seen[table] {
+				seen[table] = true
+				tables = append(tables, table)
+			}
+		}
+	}
+	sort.Strings(tables)
+
+	return tables
+}
+
+// encodeMedia turns a media reference into JSON, nil staying NULL.
+func encodeMedia(media *game.Media) ([]byte, error) {
+	if media == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(media)
+}
+
+func decodeMedia(data []byte) (*game.Media, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	media := &game.Media{}
+	err := json.Unmarshal(data, media)
+	if err != nil {
+		return nil, err
+	}
+
+	return media, nil
+}
+
+func (p *PostgresGameStorer) storeQuestionDocument(
+	ctx context.Context,
+	conn *pgx.Conn,
+	questionId uuid.UUID,
+	question game.Question,
+) error {
+	data, err := json.Marshal(question)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]json.RawMessage)
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+	for _, field := range headerFields {
+		delete(fields, field)
+	}
+
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	args := pgx.NamedArgs{
+		"question_id": questionId,
+		"data":        data,
+	}
+
+	insert := `INSERT INTO question_documents (question_id, data) VALUES (@question_id, @data)`
+
+	_, err = conn.Exec(ctx, insert, args)
+	return err
+}
+
+func (p *PostgresGameStorer) loadQuestionDocuments(
+	ctx context.Context,
+	questions map[uuid.UUID]game.Question,
+) error {
+	if len(questions) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(questions))
+	for id := range questions {
+		ids = append(ids, id)
+	}
+
+	args := pgx.NamedArgs{
+		"questionIds": ids,
+	}
+
+	query := `SELECT question_id, data FROM question_documents WHERE question_id = ANY(@questionIds)`
+
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var questionId uuid.UUID
+		var data []byte
+
+		err := rows.Scan(&questionId, &data)
+		if err != nil {
+			return err
+		}
+
+		err = json.Unmarshal(data, questions[questionId])
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
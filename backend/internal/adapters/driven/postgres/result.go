@@ -0,0 +1,345 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/result_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+type PostgresResultStorer struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresResultStorer(pool *pgxpool.Pool) *PostgresResultStorer {
+	return &PostgresResultStorer{
+		pool: pool,
+	}
+}
+
+func (p *PostgresResultStorer) StoreResult(
+	ctx context.Context,
+	res *result.SessionResult,
+) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	distributions := res.Distributions
+	if distributions == nil {
+		distributions = []*result.Distribution{}
+	}
+	encodedDistributions, err := json.Marshal(distributions)
+	if err != nil {
+		return err
+	}
+
+	args := pgx.NamedArgs{
+		"id":            res.Id,
+		"pin":           res.Pin,
+		"game_id":       res.GameId,
+		"game_title":    res.GameTitle,
+		"host_id":       res.HostId,
+		"started_at":    res.StartedAt,
+		"finished_at":   res.FinishedAt,
+		"distributions": encodedDistributions,
+	}
+
+	insert := `INSERT INTO session_results (id, pin, game_id, game_title, host_id, started_at, finished_at, distributions) VALUES (@id, @pin, @game_id, @game_title, @host_id, @started_at, @finished_at, @distributions)`
+	_, err = tx.Exec(ctx, insert, args)
+	if err != nil {
+		return err
+	}
+
+	for _, participant := range res.Participants {
+		err = p.storeParticipant(ctx, tx, res.Id, participant)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, answer := range res.Answers {
+		err = p.storeAnswer(ctx, tx, res.Id, answer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *PostgresResultStorer) FindResultById(
+	ctx context.Context,
+	id uuid.UUID,
+) (*result.SessionResult, error) {
+	args := pgx.NamedArgs{
+		"id": id,
+	}
+
+	query := `SELECT ` + resultColumns + ` FROM session_results WHERE id = @id`
+
+	results, err := p.findResults(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, ports.ErrResultNotFound
+	}
+
+	return results[0], nil
+}
+
+func (p *PostgresResultStorer) FindAllResultsByHostId(
+	ctx context.Context,
+	hostId string,
+) ([]*result.SessionResult, error) {
+	args := pgx.NamedArgs{
+		"hostId": hostId,
+	}
+
+	query := `SELECT ` + resultColumns + ` FROM session_results WHERE host_id = @hostId ORDER BY finished_at DESC`
+
+	return p.findResults(ctx, query, args)
+}
+
+func (p *PostgresResultStorer) FindAllResultsByGameId(
+	ctx context.Context,
+	gameId uuid.UUID,
+) ([]*result.SessionResult, error) {
+	args := pgx.NamedArgs{
+		"gameId": gameId,
+	}
+
+	query := `SELECT ` + resultColumns + ` FROM session_results WHERE game_id = @gameId ORDER BY finished_at DESC`
+
+	return p.findResults(ctx, query, args)
+}
+
+const resultColumns = `id, pin, game_id, game_title, host_id, started_at, finished_at, distributions`
+
+// findResults runs a query selecting resultColumns and then loads the
+// participants and answers of every result found with one query each.
+func (p *PostgresResultStorer) findResults(
+	ctx context.Context,
+	query string,
+	args pgx.NamedArgs,
+) ([]*result.SessionResult, error) {
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*result.SessionResult{}
+	byId := make(map[uuid.UUID]*result.SessionResult)
+	ids := []uuid.UUID{}
+
+	for rows.Next() {
+		res := result.SessionResult{
+			Participants: []*result.Participant{},
+			Answers:      []*result.Answer{},
+		}
+		var distributions []byte
+
+		err := rows.Scan(
+			&res.Id,
+			&res.Pin,
+			&res.GameId,
+			&res.GameTitle,
+			&res.HostId,
+			&res.StartedAt,
+			&res.FinishedAt,
+			&distributions,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(distributions, &res.Distributions)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, &res)
+		byId[res.Id] = &res
+		ids = append(ids, res.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	err = p.loadParticipants(ctx, ids, byId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.loadAnswers(ctx, ids, byId)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (p *PostgresResultStorer) loadParticipants(
+	ctx context.Context,
+	ids []uuid.UUID,
+	byId map[uuid.UUID]*result.SessionResult,
+) error {
+	args := pgx.NamedArgs{
+		"ids": ids,
+	}
+
+	query := `SELECT result_id, player_id, nickname, score, rank, joined_at FROM session_participants WHERE result_id = ANY(@ids) ORDER BY rank`
+
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var resultId uuid.UUID
+		var participant result.Participant
+
+		err := rows.Scan(
+			&resultId,
+			&participant.Id,
+			&participant.Nickname,
+			&participant.Score,
+			&participant.Rank,
+			&participant.JoinedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		res := byId[resultId]
+		res.Participants = append(res.Participants, &participant)
+	}
+
+	return rows.Err()
+}
+
+func (p *PostgresResultStorer) loadAnswers(
+	ctx context.Context,
+	ids []uuid.UUID,
+	byId map[uuid.UUID]*result.SessionResult,
+) error {
+	args := pgx.NamedArgs{
+		"ids": ids,
+	}
+
+	query := `SELECT result_id, player_id, question_index, question_title, value, correct, ungraded, points, response_time_ms, answered_at FROM session_answers WHERE result_id = ANY(@ids) ORDER BY answered_at, question_index`
+
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var resultId uuid.UUID
+		var value []byte
+		var responseTime int64
+		var answer result.Answer
+
+		err := rows.Scan(
+			&resultId,
+			&answer.PlayerId,
+			&answer.QuestionIndex,
+			&answer.QuestionTitle,
+			&value,
+			&answer.Correct,
+			&answer.Ungraded,
+			&answer.Points,
+			&responseTime,
+			&answer.AnsweredAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		if value != nil {
+			err = json.Unmarshal(value, &answer.Value)
+			if err != nil {
+				return err
+			}
+		}
+		answer.ResponseTime = time.Duration(responseTime) * time.Millisecond
+
+		res := byId[resultId]
+		res.Answers = append(res.Answers, &answer)
+	}
+
+	return rows.Err()
+}
+
+func (p *PostgresResultStorer) storeParticipant(
+	ctx context.Context,
+	tx pgx.Tx,
+	resultId uuid.UUID,
+	participant *result.Participant,
+) error {
+	args := pgx.NamedArgs{
+		"result_id": resultId,
+		"player_id": participant.Id,
+		"nickname":  participant.Nickname,
+		"score":     participant.Score,
+		"rank":      participant.Rank,
+		"joined_at": participant.JoinedAt,
+	}
+
+	insert := `INSERT INTO session_participants (result_id, player_id, nickname, score, rank, joined_at) VALUES (@result_id, @player_id, @nickname, @score, @rank, @joined_at)`
+
+	_, err := tx.Exec(ctx, insert, args)
+	return err
+}
+
+func (p *PostgresResultStorer) storeAnswer(
+	ctx context.Context,
+	tx pgx.Tx,
+	resultId uuid.UUID,
+	answer *result.Answer,
+) error {
+	var value []byte
+	if answer.Value != nil {
+		var err error
+		value, err = json.Marshal(answer.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	args := pgx.NamedArgs{
+		"id":               uuid.New(),
+		"result_id":        resultId,
+		"player_id":        answer.PlayerId,
+		"question_index":   answer.QuestionIndex,
+		"question_title":   answer.QuestionTitle,
+		"value":            value,
+		"correct":          answer.Correct,
+		"ungraded":         answer.Ungraded,
+		"points":           answer.Points,
+		"response_time_ms": answer.ResponseTime.Milliseconds(),
+		"answered_at":      answer.AnsweredAt,
+	}
+
+	insert := `INSERT INTO session_answers (id, result_id, player_id, question_index, question_title, value, correct, ungraded, points, response_time_ms, answered_at) VALUES (@id, @result_id, @player_id, @question_index, @question_title, @value, @correct, @ungraded, @points, @response_time_ms, @answered_at)`
+
+	_, err := tx.Exec(ctx, insert, args)
+	return err
+}
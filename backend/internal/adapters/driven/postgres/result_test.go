@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	testcontainers "github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/domain/result_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+	"github.com/taldoflemis/brain.test/test/helpers"
+)
+
+type PostgresResultStorerTestSuite struct {
+	suite.Suite
+	pgContainer *testcontainers.PostgresContainer
+	ctx         context.Context
+	repo        *PostgresResultStorer
+	gameRepo    *PostgresGameStorer
+	pool        *pgxpool.Pool
+}
+
+func (suite *PostgresResultStorerTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	pgContainer, pool, err := testshelpers.CreatePostgresContainerAndMigrate(
+		suite.ctx,
+		"./migrations/",
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pgContainer = pgContainer
+
+	suite.pool = pool
+	suite.repo = NewPostgresResultStorer(pool)
+	suite.gameRepo = NewPostgresGameStorer(pool)
+}
+
+func (suite *PostgresResultStorerTestSuite) TearDownTest() {
+	_, err := suite.pool.Exec(suite.ctx, "TRUNCATE TABLE session_results, games CASCADE")
+	if err != nil {
+		log.Fatalf("error truncating session_results table: %s", err)
+	}
+}
+
+func (suite *PostgresResultStorerTestSuite) TearDownSuite() {
+	if err := suite.pgContainer.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating postgres container: %s", err)
+	}
+}
+
+func TestPostgresResultStorerTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	suite.Run(t, new(PostgresResultStorerTestSuite))
+}
+
+func (suite *PostgresResultStorerTestSuite) generateMockedResult(gameId uuid.UUID) *result.SessionResult {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	first := uuid.New()
+	second := uuid.New()
+
+	return &result.SessionResult{
+		Id:         uuid.New(),
+		Pin:        "123456",
+		GameId:     gameId,
+		GameTitle:  testGameTitle,
+		HostId:     testGameID.String(),
+		StartedAt:  now.Add(-time.Minute),
+		FinishedAt: now,
+		Participants: []*result.Participant{
+			{Id: first, Nickname: "tubias", Score: 900, Rank: 1, JoinedAt: now.Add(-time.Minute)},
+			{Id: second, Nickname: "gepeto", Score: 0, Rank: 2, JoinedAt: now.Add(-time.Minute)},
+		},
+		Answers: []*result.Answer{
+			{
+				PlayerId:      first,
+				QuestionIndex: 0,
+				QuestionTitle: "testQuestion",
+				Value:         "testTrueAlternative",
+				Correct:       true,
+				Points:        900,
+				ResponseTime:  1500 * time.Millisecond,
+				AnsweredAt:    now.Add(-30 * time.Second),
+			},
+			{
+				PlayerId:      second,
+				QuestionIndex: 0,
+				QuestionTitle: "testQuestion",
+				Value:         "testFalseAlternative",
+				Correct:       false,
+				Points:        0,
+				ResponseTime:  3 * time.Second,
+				AnsweredAt:    now.Add(-29 * time.Second),
+			},
+		},
+		Distributions: []*result.Distribution{
+			{
+				QuestionIndex: 1,
+				QuestionTitle: "testPoll",
+				Tallies:       []game.Tally{{Value: "yes", Count: 2}, {Value: "no", Count: 0}},
+			},
+		},
+	}
+}
+
+func (suite *PostgresResultStorerTestSuite) TestStoreResult() {
+	// Arrange
+	t := suite.T()
+	mockedResult := suite.generateMockedResult(uuid.New())
+
+	// Act
+	err := suite.repo.StoreResult(suite.ctx, mockedResult)
+
+	// Assert
+	assert.NoError(t, err)
+
+	found, err := suite.repo.FindResultById(suite.ctx, mockedResult.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, mockedResult.GameTitle, found.GameTitle)
+	assert.True(t, mockedResult.FinishedAt.Equal(found.FinishedAt))
+	assert.Len(t, found.Participants, 2)
+	assert.Equal(t, "tubias", found.Participants[0].Nickname)
+	assert.Equal(t, 1, found.Participants[0].Rank)
+	assert.Len(t, found.Answers, 2)
+	assert.Equal(t, "testTrueAlternative", found.Answers[0].Value)
+	assert.Equal(t, 1500*time.Millisecond, found.Answers[0].ResponseTime)
+	assert.False(t, found.Answers[1].Correct)
+	assert.Equal(t, mockedResult.Distributions, found.Distributions)
+}
+
+func (suite *PostgresResultStorerTestSuite) TestFindUnknownResult() {
+	// Act
+	_, err := suite.repo.FindResultById(suite.ctx, uuid.New())
+
+	// Assert
+	assert.ErrorIs(suite.T(), err, ports.ErrResultNotFound)
+}
+
+func (suite *PostgresResultStorerTestSuite) TestResultOutlivesGame() {
+	// Arrange
+	t := suite.T()
+	mockedGame := &game.Game{
+		Id:          uuid.New(),
+		Title:       testGameTitle,
+		Description: testGameDescription,
+		OwnerId:     testGameID.String(),
+	}
+	err := suite.gameRepo.StoreGame(suite.ctx, mockedGame)
+	assert.NoError(t, err)
+	mockedResult := suite.generateMockedResult(mockedGame.Id)
+	err = suite.repo.StoreResult(suite.ctx, mockedResult)
+	assert.NoError(t, err)
+
+	// Act
+	err = suite.gameRepo.DeleteGame(suite.ctx, mockedGame.Id)
+
+	// Assert
+	assert.NoError(t, err)
+
+	results, err := suite.repo.FindAllResultsByGameId(suite.ctx, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, testGameTitle, results[0].GameTitle)
+	assert.Len(t, results[0].Answers, 2)
+}
+
+func (suite *PostgresResultStorerTestSuite) TestFindAllResultsByHostId() {
+	// Arrange
+	t := suite.T()
+	older := suite.generateMockedResult(uuid.New())
+	older.FinishedAt = older.FinishedAt.Add(-time.Hour)
+	newer := suite.generateMockedResult(uuid.New())
+	someoneElses := suite.generateMockedResult(uuid.New())
+	someoneElses.HostId = uuid.NewString()
+
+	for _, r := range []*result.SessionResult{older, newer, someoneElses} {
+		err := suite.repo.StoreResult(suite.ctx, r)
+		assert.NoError(t, err)
+	}
+
+	// Act
+	results, err := suite.repo.FindAllResultsByHostId(suite.ctx, testGameID.String())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, newer.Id, results[0].Id)
+	assert.Equal(t, older.Id, results[1].Id)
+	assert.Len(t, results[1].Participants, 2)
+}
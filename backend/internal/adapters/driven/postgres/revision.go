@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+type PostgresRevisionStorer struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresRevisionStorer(pool *pgxpool.Pool) *PostgresRevisionStorer {
+	return &PostgresRevisionStorer{
+		pool: pool,
+	}
+}
+
+func (p *PostgresRevisionStorer) StoreRevision(
+	ctx context.Context,
+	revision *game.Revision,
+) error {
+	tx, err := p.pool.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = storeRevision(ctx, tx, revision)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func storeRevision(ctx context.Context, tx pgx.Tx, revision *game.Revision) error {
+	data, err := encodeGame(revision.Game)
+	if err != nil {
+		return err
+	}
+
+	args := pgx.NamedArgs{
+		"gameId":    revision.GameId,
+		"authorId":  revision.AuthorId,
+		"createdAt": revision.CreatedAt,
+		"summary":   revision.Summary,
+		"data":      data,
+	}
+
+	// locks the game so that concurrent revisions don't take the same number
+	var found uuid.UUID
+	err = tx.QueryRow(ctx, `SELECT id FROM games WHERE id = @gameId FOR UPDATE`, args).
+		Scan(&found)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ports.ErrGameNotFound
+		}
+		return err
+	}
+
+	insert := `INSERT INTO game_revisions (game_id, number, author_id, created_at, summary, data)
+		SELECT @gameId, COALESCE(MAX(number), 0) + 1, @authorId, @createdAt, @summary, @data FROM game_revisions WHERE game_id = @gameId
+		RETURNING number`
+
+	return tx.QueryRow(ctx, insert, args).Scan(&revision.Number)
+}
+
+func (p *PostgresRevisionStorer) FindRevisionsByGameId(
+	ctx context.Context,
+	gameId uuid.UUID,
+) ([]*game.Revision, error) {
+	args := pgx.NamedArgs{
+		"gameId": gameId,
+	}
+
+	query := `SELECT ` + revisionColumns + ` FROM game_revisions WHERE game_id = @gameId ORDER BY number DESC`
+
+	rows, err := p.pool.Query(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []*game.Revision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (p *PostgresRevisionStorer) FindRevision(
+	ctx context.Context,
+	gameId uuid.UUID,
+	number int,
+) (*game.Revision, error) {
+	args := pgx.NamedArgs{
+		"gameId": gameId,
+		"number": number,
+	}
+
+	query := `SELECT ` + revisionColumns + `, data FROM game_revisions WHERE game_id = @gameId AND number = @number`
+
+	var data []byte
+	revision, err := scanRevision(p.pool.QueryRow(ctx, query, args), &data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ports.ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	revision.Game, err = decodeGame(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return revision, nil
+}
+
+const revisionColumns = `game_id, number, author_id, created_at, summary`
+
+func scanRevision(row pgx.Row, extra ...any) (*game.Revision, error) {
+	var revision game.Revision
+
+	dest := append([]any{
+		&revision.GameId,
+		&revision.Number,
+		&revision.AuthorId,
+		&revision.CreatedAt,
+		&revision.Summary,
+	}, extra...)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	testcontainers "github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+	"github.com/taldoflemis/brain.test/test/helpers"
+)
+
+type PostgresRevisionStorerTestSuite struct {
+	suite.Suite
+	pgContainer *testcontainers.PostgresContainer
+	ctx         context.Context
+	repo        *PostgresRevisionStorer
+	gameRepo    *PostgresGameStorer
+	pool        *pgxpool.Pool
+}
+
+func (suite *PostgresRevisionStorerTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	pgContainer, pool, err := testshelpers.CreatePostgresContainerAndMigrate(
+		suite.ctx,
+		"./migrations/",
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	suite.pgContainer = pgContainer
+
+	suite.pool = pool
+	suite.repo = NewPostgresRevisionStorer(pool)
+	suite.gameRepo = NewPostgresGameStorer(pool)
+}
+
+func (suite *PostgresRevisionStorerTestSuite) TearDownTest() {
+	_, err := suite.pool.Exec(suite.ctx, "TRUNCATE TABLE games CASCADE")
+	if err != nil {
+		log.Fatalf("error truncating games table: %s", err)
+	}
+}
+
+func (suite *PostgresRevisionStorerTestSuite) TearDownSuite() {
+	if err := suite.pgContainer.Terminate(suite.ctx); err != nil {
+		log.Fatalf("error terminating postgres container: %s", err)
+	}
+}
+
+func TestPostgresRevisionStorerTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("too slow for testing.Short")
+	}
+
+	suite.Run(t, new(PostgresRevisionStorerTestSuite))
+}
+
+func (suite *PostgresRevisionStorerTestSuite) TestStoreAndFindRevisions() {
+	// Arrange
+	t := suite.T()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	original := &game.Game{
+		Id:          uuid.New(),
+		Title:       testGameTitle,
+		Description: testGameDescription,
+		OwnerId:     testGameID.String(),
+		Questions: []game.Question{
+			&game.TrueFalseQuestion{
+				Title:            "testQuestion",
+				Points:           1,
+				TimeLimit:        30,
+				TrueAlternative:  "testTrueAlternative",
+				FalseAlternative: "testFalseAlternative",
+			},
+		},
+	}
+	err := suite.gameRepo.StoreGame(suite.ctx, original)
+	assert.NoError(t, err)
+	changed := *original
+	changed.Title = "new title"
+
+	// Act
+	first := game.NewRevision(original.OwnerId, nil, original, now)
+	err = suite.repo.StoreRevision(suite.ctx, first)
+	assert.NoError(t, err)
+	second := game.NewRevision(original.OwnerId, original, &changed, now.Add(time.Minute))
+	err = suite.repo.StoreRevision(suite.ctx, second)
+	assert.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, 1, first.Number)
+	assert.Equal(t, 2, second.Number)
+
+	revisions, err := suite.repo.FindRevisionsByGameId(suite.ctx, original.Id)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Number)
+	assert.Equal(t, second.Summary, revisions[0].Summary)
+	assert.Nil(t, revisions[0].Game)
+
+	found, err := suite.repo.FindRevision(suite.ctx, original.Id, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, original.OwnerId, found.AuthorId)
+	assert.True(t, now.Equal(found.CreatedAt))
+	assert.Equal(t, testGameTitle, found.Game.Title)
+	assert.Len(t, found.Game.Questions, 1)
+
+	_, err = suite.repo.FindRevision(suite.ctx, original.Id, 3)
+	assert.ErrorIs(t, err, ports.ErrRevisionNotFound)
+}
+
+func (suite *PostgresRevisionStorerTestSuite) TestStoreConcurrentRevisions() {
+	// Arrange
+	t := suite.T()
+	original := &game.Game{
+		Id:          uuid.New(),
+		Title:       testGameTitle,
+		Description: testGameDescription,
+		OwnerId:     testGameID.String(),
+	}
+	err := suite.gameRepo.StoreGame(suite.ctx, original)
+	assert.NoError(t, err)
+	amount := 10
+
+	// Act
+	var wg sync.WaitGroup
+	errs := make([]error, amount)
+	for i := 0; i < amount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			revision := game.NewRevision(original.OwnerId, nil, original, time.Now())
+			errs[i] = suite.repo.StoreRevision(suite.ctx, revision)
+		}(i)
+	}
+	wg.Wait()
+
+	// Assert
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+
+	revisions, err := suite.repo.FindRevisionsByGameId(suite.ctx, original.Id)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, amount)
+	assert.Equal(t, amount, revisions[0].Number)
+}
+
+func (suite *PostgresRevisionStorerTestSuite) TestStoreRevisionOfUnknownGame() {
+	// Arrange
+	t := suite.T()
+	unknown := &game.Game{Id: uuid.New(), Title: testGameTitle}
+
+	// Act
+	err := suite.repo.StoreRevision(
+		suite.ctx,
+		game.NewRevision(testGameID.String(), nil, unknown, time.Now()),
+	)
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrGameNotFound)
+}
+
+func (suite *PostgresRevisionStorerTestSuite) TestMediaOnlyRevisionsHoldIsUnattached() {
+	// Arrange
+	t := suite.T()
+	image := &game.Media{Id: uuid.New(), Kind: game.ImageMedia}
+	original := &game.Game{
+		Id:          uuid.New(),
+		Title:       testGameTitle,
+		Description: testGameDescription,
+		OwnerId:     testGameID.String(),
+		Questions: []game.Question{
+			&game.WordCloudQuestion{Title: "testQuestion", TimeLimit: 30, Media: image},
+		},
+	}
+	err := suite.gameRepo.StoreGame(suite.ctx, original)
+	assert.NoError(t, err)
+	err = suite.repo.StoreRevision(
+		suite.ctx,
+		game.NewRevision(original.OwnerId, nil, original, time.Now()),
+	)
+	assert.NoError(t, err)
+
+	// Act
+	err = suite.gameRepo.UpdateGameQuestions(suite.ctx, original.Id, []game.Question{})
+
+	// Assert
+	assert.NoError(t, err)
+
+	unreferenced, err := suite.gameRepo.FindUnreferencedMedia(suite.ctx, []uuid.UUID{image.Id})
+	assert.NoError(t, err)
+	assert.Empty(t, unreferenced)
+
+	unattached, err := suite.gameRepo.FindUnattachedMedia(suite.ctx, []uuid.UUID{image.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{image.Id}, unattached)
+}
+
+func (suite *PostgresRevisionStorerTestSuite) TestCreateGame() {
+	// Arrange
+	t := suite.T()
+	created := &game.Game{
+		Id:          uuid.New(),
+		Title:       testGameTitle,
+		Description: testGameDescription,
+		OwnerId:     testGameID.String(),
+	}
+	publishedAt := time.Now().UTC().Truncate(time.Millisecond)
+
+	// Act
+	err := suite.gameRepo.CreateGame(
+		suite.ctx,
+		created,
+		game.NewRevision(created.OwnerId, nil, created, publishedAt),
+		&publishedAt,
+	)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, game.PublishedStatus, created.Status)
+
+	revisions, err := suite.repo.FindRevisionsByGameId(suite.ctx, created.Id)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 1)
+	assert.Equal(t, 1, revisions[0].Number)
+
+	published, err := suite.gameRepo.FindPublishedGame(suite.ctx, created.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, testGameTitle, published.Title)
+	assert.True(t, publishedAt.Equal(*published.PublishedAt))
+}
+
+func (suite *PostgresRevisionStorerTestSuite) TestCreateGameAtOnce() {
+	// Arrange
+	t := suite.T()
+	created := &game.Game{
+		Id:          uuid.New(),
+		Title:       testGameTitle,
+		Description: testGameDescription,
+		OwnerId:     testGameID.String(),
+	}
+	unknown := &game.Game{Id: uuid.New(), Title: testGameTitle}
+
+	// Act
+	err := suite.gameRepo.CreateGame(
+		suite.ctx,
+		created,
+		game.NewRevision(created.OwnerId, nil, unknown, time.Now()),
+		nil,
+	)
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrGameNotFound)
+
+	_, err = suite.gameRepo.FindGameById(suite.ctx, created.Id)
+	assert.ErrorIs(t, err, ports.ErrGameNotFound)
+}
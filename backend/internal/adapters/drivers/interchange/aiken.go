@@ -0,0 +1,129 @@
+package interchange
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+// maxAikenChoices is how many choices letters can name
+const maxAikenChoices = 26
+
+var (
+	aikenChoicePattern = regexp.MustCompile(`^([A-Z])[.)]\s+(.*)$`)
+	aikenAnswerPattern = regexp.MustCompile(`^ANSWER:\s*([A-Z])\s*$`)
+)
+
+// encodeAiken writes the questions in Aiken, plain text multiple choice
+// questions with a single correct choice.
+func encodeAiken(questions []game.Question, w *warnings) ([]byte, error) {
+	var out bytes.Buffer
+
+	for i, q := range questions {
+		w.question = i + 1
+		warnMedia(q, w)
+
+		c, ok := exportChoices(q, w)
+		if !ok {
+			w.skip(string(q.Kind()), AikenFormat)
+			continue
+		}
+		if c.multiple {
+			w.add("multiple correct choices can't be represented, the question was left out")
+			continue
+		}
+		if len(c.choices) > maxAikenChoices {
+			w.add("more than %d choices can't be represented, the question was left out", maxAikenChoices)
+			continue
+		}
+
+		// true or false questions read better with true first
+		if c.trueFalse && strings.EqualFold(c.choices[1].source, "true") {
+			c.choices[0], c.choices[1] = c.choices[1], c.choices[0]
+		}
+
+		fmt.Fprintln(&out, plainText(c.title, w))
+		answer := 'A'
+		for j, ch := range c.choices {
+			letter := rune('A' + j)
+			fmt.Fprintf(&out, "%c. %s\n", letter, plainText(ch.text, w))
+			if ch.correct {
+				answer = letter
+			}
+		}
+		fmt.Fprintf(&out, "ANSWER: %c\n\n", answer)
+	}
+
+	return out.Bytes(), nil
+}
+
+// decodeAiken reads the questions of an Aiken file. Each is a line of text,
+// its choices one per line starting with a letter, and the letter of the
+// correct one after ANSWER:.
+func decodeAiken(data []byte, w *warnings) ([]game.Question, error) {
+	questions := make([]game.Question, 0)
+
+	var title []string
+	var choices []choice
+	started := false
+
+	reset := func() {
+		title, choices, started = nil, nil, false
+	}
+
+	content := strings.TrimPrefix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\ufeff")
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if !started {
+			started = true
+			w.question++
+		}
+
+		if match := aikenAnswerPattern.FindStringSubmatch(line); match != nil {
+			i := int(match[1][0] - 'A')
+			switch {
+			case len(title) == 0 || len(choices) < 2:
+				w.add("a question needs its text and at least two choices, the question was left out")
+			case i >= len(choices):
+				w.add("the answer %s isn't one of the choices, the question was left out", match[1])
+			default:
+				choices[i].correct = true
+				c := &choiceQuestion{
+					title:   text{strings.Join(title, " "), game.PlainText},
+					points:  DefaultPoints,
+					choices: choices,
+				}
+				questions = append(questions, c.question())
+			}
+			reset()
+			continue
+		}
+
+		if match := aikenChoicePattern.FindStringSubmatch(line); match != nil && len(title) > 0 {
+			if int(match[1][0]-'A') != len(choices) {
+				w.add("the choice %s is out of order", match[1])
+			}
+			choices = append(choices, choice{text: text{match[2], game.PlainText}})
+			continue
+		}
+
+		if len(choices) > 0 {
+			w.add("text after the choices isn't allowed, the line %q was left out", line)
+			continue
+		}
+		title = append(title, line)
+	}
+
+	if started {
+		w.add("the question has no ANSWER: line, it was left out")
+	}
+
+	return questions, nil
+}
@@ -0,0 +1,427 @@
+package interchange
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+// giftSpecial are the characters GIFT escapes with a backslash
+const giftSpecial = `~=#{}:`
+
+var giftEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`~`, `\~`,
+	`=`, `\=`,
+	`#`, `\#`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`:`, `\:`,
+	"\n", `\n`,
+)
+
+var giftFormats = map[game.TextFormat]string{
+	game.PlainText:         "plain",
+	game.MarkdownText:      "markdown",
+	game.MarkdownLatexText: "markdown",
+}
+
+// encodeGIFT writes the questions in GIFT, Moodle's format to write
+// questions by hand.
+func encodeGIFT(questions []game.Question, w *warnings) ([]byte, error) {
+	var out bytes.Buffer
+
+	for i, q := range questions {
+		w.question = i + 1
+		warnMedia(q, w)
+
+		body, ok := giftQuestion(q, w)
+		if !ok {
+			w.skip(string(q.Kind()), GIFTFormat)
+			continue
+		}
+
+		fmt.Fprintf(&out, "// question: %d\n%s\n\n", i+1, body)
+	}
+
+	return out.Bytes(), nil
+}
+
+func giftQuestion(q game.Question, w *warnings) (string, bool) {
+	if typed, ok := q.(*game.TypeAnswerQuestion); ok {
+		if len(typed.AcceptedAnswers) == 0 {
+			return "", false
+		}
+
+		warnTypeAnswer(typed, w, false)
+		answers := make([]string, len(typed.AcceptedAnswers))
+		for i, answer := range typed.AcceptedAnswers {
+			answers[i] = "=" + giftEscaper.Replace(answer)
+		}
+
+		return giftText(text{typed.Title, typed.Format}) + " {" + strings.Join(answers, " ") + "}", true
+	}
+
+	c, ok := exportChoices(q, w)
+	if !ok {
+		return "", false
+	}
+
+	title := giftText(c.title)
+	if c.trueFalse {
+		if strings.EqualFold(c.choices[0].source, "true") {
+			return title + " {TRUE}", true
+		}
+		return title + " {FALSE}", true
+	}
+
+	right, wrong := fractionsOf(c)
+	answers := make([]string, len(c.choices))
+	for i, ch := range c.choices {
+		data := giftEscaper.Replace(toMoodleLatex(ch.text))
+		switch {
+		case !c.multiple && ch.correct:
+			answers[i] = "\t=" + data
+		case !c.multiple:
+			answers[i] = "\t~" + data
+		case ch.correct:
+			answers[i] = "\t~%" + right + "%" + data
+		default:
+			answers[i] = "\t~%" + wrong + "%" + data
+		}
+	}
+
+	return title + " {\n" + strings.Join(answers, "\n") + "\n}", true
+}
+
+func giftText(t text) string {
+	format := giftFormats[t.format]
+	if format == "" {
+		format = "plain"
+	}
+
+	return "[" + format + "]" + giftEscaper.Replace(toMoodleLatex(t))
+}
+
+// decodeGIFT reads the questions of a GIFT file, separated by blank lines.
+func decodeGIFT(data []byte, w *warnings) ([]game.Question, error) {
+	questions := make([]game.Question, 0)
+
+	for _, block := range giftBlocks(string(data)) {
+		w.question++
+		q := parseGIFTQuestion(block, w)
+		if q != nil {
+			questions = append(questions, q)
+		}
+	}
+
+	return questions, nil
+}
+
+// giftBlocks splits a GIFT file into its questions, leaving out comments and
+// categories.
+func giftBlocks(data string) []string {
+	blocks := make([]string, 0)
+	current := make([]string, 0)
+
+	flush := func() {
+		if len(current) > 0 {
+			blocks = append(blocks, strings.Join(current, "\n"))
+			current = current[:0]
+		}
+	}
+
+	data = strings.TrimPrefix(strings.ReplaceAll(data, "\r\n", "\n"), "\ufeff")
+	for _, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"), strings.HasPrefix(trimmed, "$CATEGORY:"):
+			continue
+		default:
+			current = append(current, line)
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+func parseGIFTQuestion(block string, w *warnings) game.Question {
+	s := strings.TrimSpace(block)
+
+	// the name is only a label in the question bank
+	if strings.HasPrefix(s, "::") {
+		end := giftIndex(s[2:], "::")
+		if end >= 0 {
+			s = strings.TrimSpace(s[end+4:])
+		}
+	}
+
+	// questions without a format are in Moodle's own, plain text with line
+	// breaks kept
+	format := game.PlainText
+	if strings.HasPrefix(s, "[") {
+		if end := strings.IndexByte(s, ']'); end > 0 {
+			switch s[1:end] {
+			case "markdown":
+				format = game.MarkdownText
+			case "html":
+				format = ""
+			}
+			s = s[end+1:]
+		}
+	}
+
+	open := giftIndex(s, "{")
+	if open < 0 {
+		w.skip("description", GIFTFormat)
+		return nil
+	}
+	close := giftIndex(s[open:], "}")
+	if close < 0 {
+		w.add("the answers aren't closed by }, the question was left out")
+		return nil
+	}
+	close += open
+
+	before := strings.TrimSpace(s[:open])
+	after := strings.TrimSpace(s[close+1:])
+	source := before
+	if after != "" {
+		// missing word questions have their answers in the middle of the text
+		source = before + " _____ " + after
+	}
+	title := giftTextOf(source, format, w)
+
+	answers := strings.TrimSpace(s[open+1 : close])
+	if feedback := giftIndex(answers, "####"); feedback >= 0 {
+		w.add("feedback can't be represented, it was left out")
+		answers = strings.TrimSpace(answers[:feedback])
+	}
+
+	switch strings.ToUpper(giftStripFeedback(answers, w)) {
+	case "T", "TRUE":
+		return trueFalseQuestion(title, true)
+	case "F", "FALSE":
+		return trueFalseQuestion(title, false)
+	}
+
+	switch {
+	case answers == "":
+		w.skip("essay", GIFTFormat)
+		return nil
+	case strings.HasPrefix(answers, "#"):
+		w.skip("numerical", GIFTFormat)
+		return nil
+	case giftIndex(answers, "->") >= 0:
+		w.skip("matching", GIFTFormat)
+		return nil
+	}
+
+	return parseGIFTAnswers(title, answers, format, w)
+}
+
+func parseGIFTAnswers(title text, answers string, format game.TextFormat, w *warnings) game.Question {
+	type answer struct {
+		text
+		marker byte
+		// weight is the percentage of the grade the answer gives, -1 when
+		// not given
+		weight float64
+	}
+
+	parsed := make([]answer, 0)
+	full := false
+	feedback := false
+
+	for _, raw := range giftSplitAnswers(answers) {
+		a := answer{marker: raw[0], weight: -1}
+		raw = strings.TrimSpace(raw[1:])
+
+		if strings.HasPrefix(raw, "%") {
+			if end := strings.IndexByte(raw[1:], '%'); end >= 0 {
+				f, err := strconv.ParseFloat(raw[1:end+1], 64)
+				if err == nil {
+					a.weight = f
+				}
+				raw = strings.TrimSpace(raw[end+2:])
+			}
+		}
+
+		if i := giftIndex(raw, "#"); i >= 0 {
+			feedback = true
+			raw = strings.TrimSpace(raw[:i])
+		}
+
+		a.text = giftTextOf(raw, format, w)
+		full = full || a.marker == '=' || a.weight >= 100
+		parsed = append(parsed, a)
+	}
+
+	if feedback {
+		w.add("feedback can't be represented, it was left out")
+	}
+
+	c := &choiceQuestion{title: title, points: DefaultPoints}
+	typed := make([]string, 0)
+	partial, uneven := false, false
+	var weight float64
+
+	for _, a := range parsed {
+		// when an answer gives the whole grade, the ones giving part of it
+		// are partly right, otherwise they are the correct answers of a
+		// multiple answer question
+		correct := a.marker == '=' || a.weight >= 100 || !full && a.weight > 0
+		switch {
+		case full && a.weight > 0 && a.weight < 100:
+			partial = true
+		case !full && a.weight > 0:
+			c.multiple = true
+			uneven = uneven || weight != 0 && weight != a.weight
+			weight = a.weight
+		}
+
+		if correct {
+			typed = append(typed, game.VisibleText(a.format, a.source))
+		}
+		c.choices = append(c.choices, choice{a.text, correct})
+	}
+
+	if len(typed) == len(parsed) {
+		return &game.TypeAnswerQuestion{
+			Title:           title.source,
+			Format:          title.format,
+			Points:          DefaultPoints,
+			TimeLimit:       DefaultTimeLimit,
+			AcceptedAnswers: typed,
+		}
+	}
+
+	c.multiple = c.multiple || len(typed) > 1
+	if partial {
+		w.add("partial credit can't be represented, only the answers giving the whole grade are correct")
+	}
+	if uneven {
+		w.add("correct answers can't be worth different amounts, they are all worth the same")
+	}
+
+	return c.question()
+}
+
+// giftTextOf reads GIFT text, whose whitespace, line breaks included, is
+// collapsed unless escaped.
+func giftTextOf(source string, format game.TextFormat, w *warnings) text {
+	source = giftUnescape(strings.Join(strings.Fields(source), " "))
+
+	switch format {
+	case "":
+		return text{fromHTML(source, w), game.PlainText}
+	case game.MarkdownText:
+		return fromMoodleLatex(source)
+	default:
+		return text{source, format}
+	}
+}
+
+func trueFalseQuestion(title text, answer bool) game.Question {
+	right, wrong := "True", "False"
+	if !answer {
+		right, wrong = wrong, right
+	}
+
+	return &game.TrueFalseQuestion{
+		Title:            title.source,
+		Format:           title.format,
+		Points:           DefaultPoints,
+		TimeLimit:        DefaultTimeLimit,
+		TrueAlternative:  right,
+		FalseAlternative: wrong,
+	}
+}
+
+// giftStripFeedback drops the feedback of a true or false answer.
+func giftStripFeedback(answers string, w *warnings) string {
+	i := giftIndex(answers, "#")
+	if i < 0 {
+		return answers
+	}
+
+	head := strings.TrimSpace(answers[:i])
+	switch strings.ToUpper(head) {
+	case "T", "TRUE", "F", "FALSE":
+		w.add("feedback can't be represented, it was left out")
+		return head
+	}
+
+	return answers
+}
+
+// giftSplitAnswers splits the answers at every = or ~ that isn't escaped.
+func giftSplitAnswers(answers string) []string {
+	split := make([]string, 0)
+	start := -1
+
+	for i := 0; i < len(answers); i++ {
+		switch answers[i] {
+		case '\\':
+			i++
+		case '=', '~':
+			if start >= 0 {
+				split = append(split, answers[start:i])
+			}
+			start = i
+		}
+	}
+	if start >= 0 {
+		split = append(split, answers[start:])
+	}
+
+	return split
+}
+
+// giftIndex returns the index of the first occurrence of sep in s that isn't
+// escaped, or -1.
+func giftIndex(s string, sep string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func giftUnescape(s string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			out.WriteByte(s[i])
+			continue
+		}
+
+		next := s[i+1]
+		switch {
+		case next == '\\':
+			out.WriteByte('\\')
+		case next == 'n':
+			out.WriteByte('\n')
+		case strings.IndexByte(giftSpecial, next) >= 0:
+			out.WriteByte(next)
+		default:
+			out.WriteString(s[i : i+2])
+		}
+		i++
+	}
+
+	return out.String()
+}
@@ -0,0 +1,434 @@
+// Package interchange converts questions to and from the formats other
+// e-learning tools, such as Moodle, write question banks in. Multiple choice,
+// true or false and short answer questions are converted, anything a format
+// can't represent is reported as a warning rather than silently dropped.
+package interchange
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+type Format string
+
+const (
+	GIFTFormat      Format = "gift"
+	AikenFormat     Format = "aiken"
+	MoodleXMLFormat Format = "moodle_xml"
+)
+
+const (
+	// DefaultPoints and DefaultTimeLimit are given to imported questions, the
+	// formats don't have them
+	DefaultPoints    game.Points    = 1
+	DefaultTimeLimit game.TimeLimit = 30
+)
+
+var (
+	ErrUnknownFormat = errors.New("Unknown question format")
+	ErrInvalidFile   = errors.New("Invalid question file")
+)
+
+// Warning is something of a question that couldn't be converted.
+type Warning struct {
+	// Question is the number, from 1, of the question in the game or file
+	// converted
+	Question int
+	Message  string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("question %d: %s", w.Question, w.Message)
+}
+
+type converter struct {
+	// extension and contentType describe the files written in the format
+	extension   string
+	contentType string
+	encode      func(questions []game.Question, w *warnings) ([]byte, error)
+	decode      func(data []byte, w *warnings) ([]game.Question, error)
+}
+
+var converters = map[Format]converter{
+	GIFTFormat: {
+		extension:   "gift.txt",
+		contentType: "text/plain; charset=utf-8",
+		encode:      encodeGIFT,
+		decode:      decodeGIFT,
+	},
+	AikenFormat: {
+		extension:   "aiken.txt",
+		contentType: "text/plain; charset=utf-8",
+		encode:      encodeAiken,
+		decode:      decodeAiken,
+	},
+	MoodleXMLFormat: {
+		extension:   "xml",
+		contentType: "application/xml",
+		encode:      encodeMoodleXML,
+		decode:      decodeMoodleXML,
+	},
+}
+
+// Formats lists the formats questions can be converted to and from.
+func Formats() []Format {
+	return []Format{GIFTFormat, AikenFormat, MoodleXMLFormat}
+}
+
+// Extension returns the extension of files written in the format.
+func Extension(format Format) (string, error) {
+	c, ok := converters[format]
+	if !ok {
+		return "", ErrUnknownFormat
+	}
+
+	return c.extension, nil
+}
+
+// ContentType returns the content type of files written in the format.
+func ContentType(format Format) (string, error) {
+	c, ok := converters[format]
+	if !ok {
+		return "", ErrUnknownFormat
+	}
+
+	return c.contentType, nil
+}
+
+// Export writes the questions in the format. Questions the format can't
+// represent are left out, with a warning.
+func Export(format Format, questions []game.Question) ([]byte, []Warning, error) {
+	c, ok := converters[format]
+	if !ok {
+		return nil, nil, ErrUnknownFormat
+	}
+
+	w := &warnings{list: make([]Warning, 0)}
+	data, err := c.encode(questions, w)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, w.list, nil
+}
+
+// Import reads the questions written in the format. They get the default
+// points and time limit unless the format has them, and aren't validated.
+func Import(format Format, data []byte) ([]game.Question, []Warning, error) {
+	c, ok := converters[format]
+	if !ok {
+		return nil, nil, ErrUnknownFormat
+	}
+
+	w := &warnings{list: make([]Warning, 0)}
+	questions, err := c.decode(data, w)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return questions, w.list, nil
+}
+
+type warnings struct {
+	list []Warning
+	// question is the number of the question being converted
+	question int
+}
+
+func (w *warnings) add(format string, args ...any) {
+	w.list = append(w.list, Warning{
+		Question: w.question,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// skip warns that a question can't be converted at all.
+func (w *warnings) skip(kind string, format Format) {
+	w.add("%s questions can't be converted to or from %s, the question was left out", kind, format)
+}
+
+// text is some formatted text of a question.
+type text struct {
+	source string
+	format game.TextFormat
+}
+
+// choice is an alternative of a multiple choice question.
+type choice struct {
+	text
+	correct bool
+}
+
+// choiceQuestion is a multiple choice or true or false question, as formats
+// see them.
+type choiceQuestion struct {
+	title   text
+	points  game.Points
+	choices []choice
+	// multiple tells whether many choices may be correct
+	multiple bool
+	// trueFalse tells whether the choices are literally true and false
+	trueFalse bool
+}
+
+// exportChoices reads questions answered by picking one or many of their
+// choices, false for other kinds.
+func exportChoices(q game.Question, w *warnings) (*choiceQuestion, bool) {
+	p := game.Present(q)
+	if p.Mode == "" || !game.IsGraded(q) {
+		return nil, false
+	}
+
+	var format game.TextFormat
+	if rendered, ok := q.(game.RenderedQuestion); ok {
+		format = rendered.GetFormat()
+	}
+
+	c := &choiceQuestion{
+		title:   text{q.GetTitle(), format},
+		points:  q.GetPoints(),
+		choices: make([]choice, len(p.Choices)),
+	}
+
+	correct := 0
+	for i, ch := range p.Choices {
+		c.choices[i] = choice{text{ch.Text, ch.Format}, ch.Correct}
+		if ch.Correct {
+			correct++
+		}
+	}
+
+	c.multiple = correct > 1
+	if c.multiple && p.Credit != game.PenaltyCredit {
+		w.add("%s credit can't be represented, wrong choices take points away instead", p.Credit)
+	}
+
+	if q.Kind() == game.TrueFalseKind && len(p.Choices) == 2 {
+		c.trueFalse = isTrueFalse(
+			game.VisibleText(p.Choices[0].Format, p.Choices[0].Text),
+			game.VisibleText(p.Choices[1].Format, p.Choices[1].Text),
+		)
+	}
+
+	return c, true
+}
+
+// question turns choices read from a format into a question. Questions with
+// two choices, one of them correct, are true or false questions.
+func (c *choiceQuestion) question() game.Question {
+	correct := make([]choice, 0)
+	wrong := make([]choice, 0)
+	for _, ch := range c.choices {
+		if ch.correct {
+			correct = append(correct, ch)
+		} else {
+			wrong = append(wrong, ch)
+		}
+	}
+
+	if len(c.choices) == 2 && len(correct) == 1 {
+		return &game.TrueFalseQuestion{
+			Title:            c.title.source,
+			Format:           c.title.format,
+			Points:           c.points,
+			TimeLimit:        DefaultTimeLimit,
+			TrueAlternative:  correct[0].in(c.title.format),
+			FalseAlternative: wrong[0].in(c.title.format),
+		}
+	}
+
+	q := &game.QuizQuestion{
+		Title:        c.title.source,
+		Format:       c.title.format,
+		Points:       c.points,
+		TimeLimit:    DefaultTimeLimit,
+		Mode:         game.SingleChoiceQuizMode,
+		Alternatives: make([]game.Alternative, len(c.choices)),
+	}
+	if c.multiple {
+		q.Mode = game.MultiSelectQuizMode
+		q.Credit = game.PenaltyCredit
+	}
+
+	for i, ch := range c.choices {
+		q.Alternatives[i] = game.Alternative{
+			Data:      ch.source,
+			Format:    ch.format,
+			IsCorrect: ch.correct,
+		}
+	}
+
+	return q
+}
+
+func isTrueFalse(right string, wrong string) bool {
+	right, wrong = strings.ToLower(right), strings.ToLower(wrong)
+	return right == "true" && wrong == "false" || right == "false" && wrong == "true"
+}
+
+// warnMedia warns about the media of a question, none of the formats can
+// embed them.
+func warnMedia(q game.Question, w *warnings) {
+	if len(game.AttachedMedia(q)) > 0 {
+		w.add("media can't be exported, they were left out")
+	}
+}
+
+// warnTypeAnswer warns about the ways of matching typed answers that only
+// accepted answers, ignoring case, can't represent.
+func warnTypeAnswer(q *game.TypeAnswerQuestion, w *warnings, caseSensitive bool) {
+	if q.CaseSensitive && !caseSensitive {
+		w.add("answers can't be case sensitive, they were made case insensitive")
+	}
+	if q.AccentSensitive {
+		w.add("answers can't be accent insensitive, they were made accent sensitive")
+	}
+	if q.Tolerance > 0 {
+		w.add("typos can't be tolerated, the tolerance was left out")
+	}
+	if q.Pattern != "" {
+		w.add("answers can't be matched by a pattern, the pattern was left out")
+	}
+}
+
+// fractionsOf returns the fractions, in percent, of the grade correct and
+// wrong choices of multiple answer questions give. Every wrong choice takes
+// away what a correct one gives.
+func fractionsOf(c *choiceQuestion) (string, string) {
+	correct := 0
+	for _, ch := range c.choices {
+		if ch.correct {
+			correct++
+		}
+	}
+	if correct == 0 {
+		correct = 1
+	}
+
+	fraction := formatFraction(100 / float64(correct))
+	return fraction, "-" + fraction
+}
+
+func formatFraction(f float64) string {
+	s := fmt.Sprintf("%.5f", f)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+var (
+	htmlTagPattern      = regexp.MustCompile(`<[^>]*>`)
+	htmlBreakPattern    = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>`)
+	trivialHTMLPattern  = regexp.MustCompile(`(?i)</?(p|div|span)(\s[^>]*)?>|<br\s*/?>`)
+	moodleLatexPattern  = regexp.MustCompile(`\\\((.+?)\\\)|\$\$(.+?)\$\$`)
+	moodleLatexEscapers = strings.NewReplacer(`$`, `\$`)
+)
+
+// fromHTML turns HTML into plain text, warning when more than paragraphs and
+// line breaks are lost.
+func fromHTML(source string, w *warnings) string {
+	if htmlTagPattern.MatchString(trivialHTMLPattern.ReplaceAllString(source, "")) {
+		w.add("HTML formatting can't be represented, it was removed")
+	}
+
+	out := htmlBreakPattern.ReplaceAllString(source, "\n")
+	out = html.UnescapeString(htmlTagPattern.ReplaceAllString(out, ""))
+
+	lines := strings.Split(out, "\n")
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+
+	return strings.Join(kept, "\n")
+}
+
+// toMoodleLatex writes the inline LaTeX of the text the way Moodle's MathJax
+// filter expects it, \( and \) instead of dollar signs.
+func toMoodleLatex(t text) string {
+	if t.format != game.MarkdownLatexText {
+		return t.source
+	}
+
+	var out strings.Builder
+	s := t.source
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			out.WriteString(s[i:min(i+2, len(s))])
+			i++
+		case '$':
+			end := strings.IndexByte(s[i+1:], '$')
+			if end < 0 {
+				out.WriteString(s[i:])
+				return out.String()
+			}
+			out.WriteString(`\(` + s[i+1:i+1+end] + `\)`)
+			i += end + 1
+		default:
+			out.WriteByte(s[i])
+		}
+	}
+
+	return out.String()
+}
+
+// fromMoodleLatex reads the LaTeX between \( and \) or $$ of Markdown text
+// from Moodle back into dollar signs.
+func fromMoodleLatex(source string) text {
+	if !moodleLatexPattern.MatchString(source) {
+		return text{source, game.MarkdownText}
+	}
+
+	parts := moodleLatexPattern.Split(source, -1)
+	matches := moodleLatexPattern.FindAllStringSubmatch(source, -1)
+
+	var out strings.Builder
+	for i, part := range parts {
+		out.WriteString(moodleLatexEscapers.Replace(part))
+		if i < len(matches) {
+			out.WriteString("$" + matches[i][1] + matches[i][2] + "$")
+		}
+	}
+
+	return text{out.String(), game.MarkdownLatexText}
+}
+
+// in returns the source of the text written in the given format, only what
+// readers see of it when it is written in another one.
+func (t text) in(format game.TextFormat) string {
+	if formatOrPlain(t.format) == formatOrPlain(format) {
+		return t.source
+	}
+
+	return game.VisibleText(t.format, t.source)
+}
+
+func formatOrPlain(format game.TextFormat) game.TextFormat {
+	if format == "" {
+		return game.PlainText
+	}
+
+	return format
+}
+
+// plainText writes the text the way readers see it, for formats without any
+// formatting.
+func plainText(t text, w *warnings) string {
+	if t.format != "" && t.format != game.PlainText {
+		w.add("%s formatting can't be represented, it was removed", t.format)
+	}
+
+	visible := game.VisibleText(t.format, t.source)
+	if strings.Contains(visible, "\n") {
+		w.add("line breaks can't be represented, they were replaced by spaces")
+	}
+
+	return strings.Join(strings.Fields(visible), " ")
+}
@@ -0,0 +1,254 @@
+package interchange_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taldoflemis/brain.test/internal/adapters/drivers/interchange"
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+func sampleQuestions() []game.Question {
+	return []game.Question{
+		&game.QuizQuestion{
+			Title:     "What is 2 + 2?",
+			Format:    game.PlainText,
+			Points:    1,
+			TimeLimit: interchange.DefaultTimeLimit,
+			Mode:      game.SingleChoiceQuizMode,
+			Alternatives: []game.Alternative{
+				{Data: "3", Format: game.PlainText},
+				{Data: "4", Format: game.PlainText, IsCorrect: true},
+				{Data: "5: maybe {not}", Format: game.PlainText},
+			},
+		},
+		&game.QuizQuestion{
+			Title:     "Which are **primes**?",
+			Format:    game.MarkdownText,
+			Points:    1,
+			TimeLimit: interchange.DefaultTimeLimit,
+			Mode:      game.MultiSelectQuizMode,
+			Credit:    game.PenaltyCredit,
+			Alternatives: []game.Alternative{
+				{Data: "2", Format: game.MarkdownText, IsCorrect: true},
+				{Data: "3", Format: game.MarkdownText, IsCorrect: true},
+				{Data: "4", Format: game.MarkdownText},
+			},
+		},
+		&game.TrueFalseQuestion{
+			Title:            "The earth is flat",
+			Format:           game.PlainText,
+			Points:           1,
+			TimeLimit:        interchange.DefaultTimeLimit,
+			TrueAlternative:  "False",
+			FalseAlternative: "True",
+		},
+		&game.TypeAnswerQuestion{
+			Title:           "Who wrote Hamlet?",
+			Format:          game.PlainText,
+			Points:          1,
+			TimeLimit:       interchange.DefaultTimeLimit,
+			AcceptedAnswers: []string{"Shakespeare", "William Shakespeare"},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	questions := sampleQuestions()
+
+	table := []struct {
+		testDescription string
+		format          interchange.Format
+		expected        []game.Question
+		warnings        []interchange.Warning
+	}{
+		{
+			testDescription: "gift",
+			format:          interchange.GIFTFormat,
+			expected:        questions,
+			warnings:        []interchange.Warning{},
+		},
+		{
+			testDescription: "moodle xml",
+			format:          interchange.MoodleXMLFormat,
+			expected:        questions,
+			warnings:        []interchange.Warning{},
+		},
+		{
+			testDescription: "aiken leaves out multiple answer and short answer questions",
+			format:          interchange.AikenFormat,
+			expected:        []game.Question{questions[0], questions[2]},
+			warnings: []interchange.Warning{
+				{Question: 2, Message: "multiple correct choices can't be represented, the question was left out"},
+				{Question: 4, Message: "type_answer questions can't be converted to or from aiken, the question was left out"},
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Act
+			data, warnings, err := interchange.Export(tt.format, questions)
+			assert.NoError(t, err)
+			imported, importWarnings, err := interchange.Import(tt.format, data)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.warnings, warnings)
+			assert.Empty(t, importWarnings)
+			assert.Equal(t, tt.expected, imported)
+		})
+	}
+}
+
+func TestRoundTripFormattedTrueFalse(t *testing.T) {
+	// Arrange
+	question := &game.TrueFalseQuestion{
+		Title:            "Which is **water**?",
+		Format:           game.MarkdownText,
+		Points:           1,
+		TimeLimit:        interchange.DefaultTimeLimit,
+		TrueAlternative:  "H~2~O",
+		FalseAlternative: "CO~2~",
+	}
+
+	for _, format := range []interchange.Format{interchange.GIFTFormat, interchange.MoodleXMLFormat} {
+		t.Run(string(format), func(t *testing.T) {
+			// Act
+			data, warnings, err := interchange.Export(format, []game.Question{question})
+			assert.NoError(t, err)
+			imported, importWarnings, err := interchange.Import(format, data)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Empty(t, warnings)
+			assert.Empty(t, importWarnings)
+			assert.Equal(t, []game.Question{question}, imported)
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	table := []struct {
+		testDescription string
+		format          interchange.Format
+		data            string
+		expected        []game.Question
+		warnings        []interchange.Warning
+	}{
+		{
+			testDescription: "gift with names, comments and what can't be represented",
+			format:          interchange.GIFTFormat,
+			data: `// a comment
+$CATEGORY: $course$/Math
+
+::Sum:: What is 1 + 1? {
+	=2 # right
+	~3
+}
+
+Write an essay {}
+
+::Age:: How old? {#42}`,
+			expected: []game.Question{
+				&game.TrueFalseQuestion{
+					Title:            "What is 1 + 1?",
+					Format:           game.PlainText,
+					Points:           interchange.DefaultPoints,
+					TimeLimit:        interchange.DefaultTimeLimit,
+					TrueAlternative:  "2",
+					FalseAlternative: "3",
+				},
+			},
+			warnings: []interchange.Warning{
+				{Question: 1, Message: "feedback can't be represented, it was left out"},
+				{Question: 2, Message: "essay questions can't be converted to or from gift, the question was left out"},
+				{Question: 3, Message: "numerical questions can't be converted to or from gift, the question was left out"},
+			},
+		},
+		{
+			testDescription: "aiken with a missing answer",
+			format:          interchange.AikenFormat,
+			data:            "Is this a question?\r\nA) Yes\r\nB) No\r\nC) Maybe\r\nANSWER: C\r\n\r\nUnanswered\r\nA. a\r\nB. b\r\n",
+			expected: []game.Question{
+				&game.QuizQuestion{
+					Title:     "Is this a question?",
+					Format:    game.PlainText,
+					Points:    interchange.DefaultPoints,
+					TimeLimit: interchange.DefaultTimeLimit,
+					Mode:      game.SingleChoiceQuizMode,
+					Alternatives: []game.Alternative{
+						{Data: "Yes", Format: game.PlainText},
+						{Data: "No", Format: game.PlainText},
+						{Data: "Maybe", Format: game.PlainText, IsCorrect: true},
+					},
+				},
+			},
+			warnings: []interchange.Warning{
+				{Question: 2, Message: "the question has no ANSWER: line, it was left out"},
+			},
+		},
+		{
+			testDescription: "moodle xml with html, grades and unsupported types",
+			format:          interchange.MoodleXMLFormat,
+			data: `<?xml version="1.0" encoding="UTF-8"?>
+<quiz>
+  <question type="category">
+    <category><text>$course$/Math</text></category>
+  </question>
+  <question type="shortanswer">
+    <name><text>Capital</text></name>
+    <questiontext format="html"><text><![CDATA[<p>Capital of <b>France</b>?</p>]]></text></questiontext>
+    <defaultgrade>5</defaultgrade>
+    <usecase>1</usecase>
+    <answer fraction="100"><text>Paris</text></answer>
+    <answer fraction="50"><text>paris</text></answer>
+  </question>
+  <question type="essay">
+    <questiontext format="html"><text>Tell us</text></questiontext>
+  </question>
+</quiz>`,
+			expected: []game.Question{
+				&game.TypeAnswerQuestion{
+					Title:           "Capital of France?",
+					Format:          game.PlainText,
+					Points:          2,
+					TimeLimit:       interchange.DefaultTimeLimit,
+					CaseSensitive:   true,
+					AcceptedAnswers: []string{"Paris"},
+				},
+			},
+			warnings: []interchange.Warning{
+				{Question: 1, Message: "HTML formatting can't be represented, it was removed"},
+				{Question: 1, Message: "questions are worth 1 or 2 points, the grade 5 became 2"},
+				{Question: 1, Message: `partial credit can't be represented, the answer "paris" was left out`},
+				{Question: 2, Message: "essay questions can't be converted to or from moodle_xml, the question was left out"},
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Act
+			imported, warnings, err := interchange.Import(tt.format, []byte(tt.data))
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, imported)
+			assert.Equal(t, tt.warnings, warnings)
+		})
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	// Act
+	_, _, exportErr := interchange.Export("qti", sampleQuestions())
+	_, _, importErr := interchange.Import("qti", []byte{})
+	_, _, invalidErr := interchange.Import(interchange.MoodleXMLFormat, []byte("<quiz>"))
+
+	// Assert
+	assert.ErrorIs(t, exportErr, interchange.ErrUnknownFormat)
+	assert.ErrorIs(t, importErr, interchange.ErrUnknownFormat)
+	assert.ErrorIs(t, invalidErr, interchange.ErrInvalidFile)
+}
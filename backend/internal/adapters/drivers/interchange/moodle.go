@@ -0,0 +1,365 @@
+package interchange
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+const (
+	// maxMoodleNameLength is how much of the title names a question
+	maxMoodleNameLength = 60
+	// minPoints and maxPoints are what a question may be worth
+	minPoints = 1
+	maxPoints = 2
+)
+
+var moodleFormats = map[game.TextFormat]string{
+	game.PlainText:         "plain_text",
+	game.MarkdownText:      "markdown",
+	game.MarkdownLatexText: "markdown",
+}
+
+type moodleQuiz struct {
+	XMLName   xml.Name         `xml:"quiz"`
+	Questions []moodleQuestion `xml:"question"`
+}
+
+type moodleText struct {
+	Format string `xml:"format,attr,omitempty"`
+	Text   string `xml:"text"`
+	// Files are embedded in the text
+	Files []struct{} `xml:"file"`
+}
+
+type moodleAnswer struct {
+	Fraction string      `xml:"fraction,attr"`
+	Format   string      `xml:"format,attr,omitempty"`
+	Text     string      `xml:"text"`
+	Feedback *moodleText `xml:"feedback"`
+}
+
+type moodleQuestion struct {
+	Type            string         `xml:"type,attr"`
+	Name            *moodleText    `xml:"name"`
+	QuestionText    *moodleText    `xml:"questiontext"`
+	GeneralFeedback *moodleText    `xml:"generalfeedback"`
+	DefaultGrade    string         `xml:"defaultgrade,omitempty"`
+	Single          string         `xml:"single,omitempty"`
+	ShuffleAnswers  string         `xml:"shuffleanswers,omitempty"`
+	UseCase         string         `xml:"usecase,omitempty"`
+	Answers         []moodleAnswer `xml:"answer"`
+}
+
+// encodeMoodleXML writes the questions in Moodle XML, the format Moodle
+// exports question banks in.
+func encodeMoodleXML(questions []game.Question, w *warnings) ([]byte, error) {
+	quiz := moodleQuiz{Questions: make([]moodleQuestion, 0, len(questions))}
+
+	for i, q := range questions {
+		w.question = i + 1
+		warnMedia(q, w)
+
+		mq, ok := moodleQuestionOf(q, w)
+		if !ok {
+			w.skip(string(q.Kind()), MoodleXMLFormat)
+			continue
+		}
+
+		quiz.Questions = append(quiz.Questions, *mq)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&out)
+	encoder.Indent("", "  ")
+	err := encoder.Encode(quiz)
+	if err != nil {
+		return nil, err
+	}
+	out.WriteString("\n")
+
+	return out.Bytes(), nil
+}
+
+func moodleQuestionOf(q game.Question, w *warnings) (*moodleQuestion, bool) {
+	header := func(kind string, title text) *moodleQuestion {
+		return &moodleQuestion{
+			Type:         kind,
+			Name:         &moodleText{Text: moodleName(title)},
+			QuestionText: moodleTextOf(title),
+			DefaultGrade: strconv.Itoa(int(q.GetPoints())),
+		}
+	}
+
+	if typed, ok := q.(*game.TypeAnswerQuestion); ok {
+		if len(typed.AcceptedAnswers) == 0 {
+			return nil, false
+		}
+
+		warnTypeAnswer(typed, w, true)
+		mq := header("shortanswer", text{typed.Title, typed.Format})
+		mq.UseCase = "0"
+		if typed.CaseSensitive {
+			mq.UseCase = "1"
+		}
+		for _, answer := range typed.AcceptedAnswers {
+			mq.Answers = append(mq.Answers, moodleAnswer{
+				Fraction: "100",
+				Format:   "plain_text",
+				Text:     answer,
+			})
+		}
+
+		return mq, true
+	}
+
+	c, ok := exportChoices(q, w)
+	if !ok {
+		return nil, false
+	}
+
+	if c.trueFalse {
+		mq := header("truefalse", c.title)
+		right := strings.EqualFold(c.choices[0].source, "true")
+		mq.Answers = []moodleAnswer{
+			{Fraction: moodleFraction(right), Format: "moodle_auto_format", Text: "true"},
+			{Fraction: moodleFraction(!right), Format: "moodle_auto_format", Text: "false"},
+		}
+
+		return mq, true
+	}
+
+	mq := header("multichoice", c.title)
+	mq.Single = strconv.FormatBool(!c.multiple)
+	mq.ShuffleAnswers = "true"
+
+	right, wrong := fractionsOf(c)
+	for _, ch := range c.choices {
+		fraction := "0"
+		switch {
+		case !c.multiple && ch.correct:
+			fraction = "100"
+		case c.multiple && ch.correct:
+			fraction = right
+		case c.multiple:
+			fraction = wrong
+		}
+
+		t := moodleTextOf(ch.text)
+		mq.Answers = append(mq.Answers, moodleAnswer{
+			Fraction: fraction,
+			Format:   t.Format,
+			Text:     t.Text,
+		})
+	}
+
+	return mq, true
+}
+
+func moodleTextOf(t text) *moodleText {
+	format := moodleFormats[t.format]
+	if format == "" {
+		format = "plain_text"
+	}
+
+	return &moodleText{Format: format, Text: toMoodleLatex(t)}
+}
+
+// moodleName shortens the title into the name of the question in the bank.
+func moodleName(t text) string {
+	name := strings.Join(strings.Fields(game.VisibleText(t.format, t.source)), " ")
+	if utf8.RuneCountInString(name) <= maxMoodleNameLength {
+		return name
+	}
+
+	return string([]rune(name)[:maxMoodleNameLength-3]) + "..."
+}
+
+func moodleFraction(correct bool) string {
+	if correct {
+		return "100"
+	}
+
+	return "0"
+}
+
+// decodeMoodleXML reads the questions of a Moodle XML file. Categories only
+// organize the bank, they are skipped.
+func decodeMoodleXML(data []byte, w *warnings) ([]game.Question, error) {
+	quiz := &moodleQuiz{}
+	err := xml.Unmarshal(data, quiz)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFile, err)
+	}
+
+	questions := make([]game.Question, 0)
+	for _, mq := range quiz.Questions {
+		if mq.Type == "category" {
+			continue
+		}
+
+		w.question++
+		q := parseMoodleQuestion(&mq, w)
+		if q != nil {
+			questions = append(questions, q)
+		}
+	}
+
+	return questions, nil
+}
+
+func parseMoodleQuestion(mq *moodleQuestion, w *warnings) game.Question {
+	if mq.QuestionText == nil {
+		w.add("the question has no text, it was left out")
+		return nil
+	}
+	title := moodleTextFrom(mq.QuestionText, w)
+	points := moodlePoints(mq.DefaultGrade, w)
+
+	if mq.GeneralFeedback != nil && strings.TrimSpace(mq.GeneralFeedback.Text) != "" {
+		w.add("feedback can't be represented, it was left out")
+	} else if hasMoodleFeedback(mq.Answers) {
+		w.add("feedback can't be represented, it was left out")
+	}
+
+	switch mq.Type {
+	case "multichoice":
+		return parseMoodleChoices(mq, title, points, w)
+	case "truefalse":
+		right := true
+		for _, answer := range mq.Answers {
+			if moodleAnswerFraction(answer) >= 100 {
+				right = strings.EqualFold(strings.TrimSpace(answer.Text), "true")
+			}
+		}
+
+		q := trueFalseQuestion(title, right).(*game.TrueFalseQuestion)
+		q.Points = points
+		return q
+	case "shortanswer":
+		q := &game.TypeAnswerQuestion{
+			Title:         title.source,
+			Format:        title.format,
+			Points:        points,
+			TimeLimit:     DefaultTimeLimit,
+			CaseSensitive: mq.UseCase == "1",
+		}
+		for _, answer := range mq.Answers {
+			fraction := moodleAnswerFraction(answer)
+			if fraction <= 0 {
+				continue
+			}
+			if fraction < 100 {
+				w.add("partial credit can't be represented, the answer %q was left out", answer.Text)
+				continue
+			}
+			q.AcceptedAnswers = append(q.AcceptedAnswers, strings.TrimSpace(answer.Text))
+		}
+
+		return q
+	default:
+		w.skip(mq.Type, MoodleXMLFormat)
+		return nil
+	}
+}
+
+func parseMoodleChoices(mq *moodleQuestion, title text, points game.Points, w *warnings) game.Question {
+	c := &choiceQuestion{
+		title:    title,
+		points:   points,
+		multiple: mq.Single == "false" || mq.Single == "0",
+	}
+
+	partial, uneven := false, false
+	var weight float64
+
+	for _, answer := range mq.Answers {
+		fraction := moodleAnswerFraction(answer)
+		correct := fraction >= 100 || c.multiple && fraction > 0
+		switch {
+		case !c.multiple && fraction > 0 && fraction < 100:
+			partial = true
+		case c.multiple && fraction > 0:
+			uneven = uneven || weight != 0 && math.Abs(weight-fraction) > 0.01
+			weight = fraction
+		}
+
+		t := moodleTextFrom(&moodleText{Format: answer.Format, Text: answer.Text}, w)
+		c.choices = append(c.choices, choice{t, correct})
+	}
+
+	if partial {
+		w.add("partial credit can't be represented, only the answers giving the whole grade are correct")
+	}
+	if uneven {
+		w.add("correct answers can't be worth different amounts, they are all worth the same")
+	}
+
+	return c.question()
+}
+
+// moodleTextFrom reads text in one of Moodle's formats, HTML being turned
+// into plain text.
+func moodleTextFrom(t *moodleText, w *warnings) text {
+	if len(t.Files) > 0 {
+		w.add("embedded files can't be imported, they were left out")
+	}
+
+	source := strings.TrimSpace(t.Text)
+	switch t.Format {
+	case "markdown":
+		return fromMoodleLatex(source)
+	case "plain_text", "moodle_auto_format":
+		return text{source, game.PlainText}
+	default:
+		return text{fromHTML(source, w), game.PlainText}
+	}
+}
+
+func moodleAnswerFraction(answer moodleAnswer) float64 {
+	f, err := strconv.ParseFloat(answer.Fraction, 64)
+	if err != nil {
+		return 0
+	}
+
+	return f
+}
+
+func hasMoodleFeedback(answers []moodleAnswer) bool {
+	for _, answer := range answers {
+		if answer.Feedback != nil && strings.TrimSpace(answer.Feedback.Text) != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// moodlePoints reads the default grade of a question as its points, which
+// are whole numbers from minPoints to maxPoints.
+func moodlePoints(grade string, w *warnings) game.Points {
+	if grade == "" {
+		return DefaultPoints
+	}
+
+	f, err := strconv.ParseFloat(grade, 64)
+	if err != nil {
+		w.add("the grade %q isn't a number of points, the default was used", grade)
+		return DefaultPoints
+	}
+
+	points := math.Max(minPoints, math.Min(maxPoints, math.Round(f)))
+	if points != f {
+		w.add("questions are worth %d or %d points, the grade %s became %.0f", minPoints, maxPoints, grade, points)
+	}
+
+	return game.Points(points)
+}
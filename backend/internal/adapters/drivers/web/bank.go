@@ -0,0 +1,525 @@
+package web
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+// SaveBankQuestionRequest
+//
+//	@Description	A question to save to the bank, with the same kind and data as the questions of a game
+type SaveBankQuestionRequest struct {
+	CreateQuestionRequest
+	// tags to find the question by, e.g. photosynthesis, ignoring case
+	Tags []string `json:"tags" validate:"lte=20,dive,required,lte=40"`
+}
+
+func (r *SaveBankQuestionRequest) toSaveBankQuestionRequest(
+	validationService *services.ValidationService,
+) (*services.SaveBankQuestionRequest, error) {
+	err := validationService.Validate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := parseQuestions(validationService, []CreateQuestionRequest{r.CreateQuestionRequest}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &services.SaveBankQuestionRequest{
+		Question: questions[0],
+		Tags:     r.Tags,
+	}, nil
+}
+
+// SaveGameToBankRequest
+//
+//	@Description	Request to save every question of a Game to the bank
+type SaveGameToBankRequest struct {
+	// tags given to every saved question
+	Tags []string `json:"tags" validate:"lte=20,dive,required,lte=40"`
+}
+
+// BankSelectionRequest
+//
+//	@Description	Questions of the bank, either by id or picked at random among the ones matching a search
+type BankSelectionRequest struct {
+	// ids of the questions, in the order they are added
+	QuestionIds []string `json:"question_ids" validate:"lte=50,dive,uuid"`
+	// text the title of the questions picked at random must contain
+	Search string `json:"search"       validate:"lte=120"`
+	// tags every question picked at random must have
+	Tags []string `json:"tags"         validate:"lte=20,dive,required,lte=40"`
+	// kinds the questions picked at random may be of, any when empty
+	Kinds []string `json:"kinds"        validate:"lte=20"`
+	// category the questions picked at random must be of
+	Category string `json:"category"     validate:"lte=60"`
+	// easy, medium or hard, the difficulty of the questions picked at random
+	Difficulty string `json:"difficulty"   validate:"omitempty,oneof=easy medium hard"`
+	// how many questions to pick at random when no ids are given
+	Count int `json:"count"        validate:"required_without=QuestionIds,gte=0,lte=50"`
+}
+
+func (r *BankSelectionRequest) ToBankSelection() services.BankSelection {
+	ids := make([]uuid.UUID, len(r.QuestionIds))
+	for i, id := range r.QuestionIds {
+		// Malformed ids are rejected when validating the request
+		ids[i], _ = uuid.Parse(id)
+	}
+
+	kinds := make([]game.Kind, len(r.Kinds))
+	for i, kind := range r.Kinds {
+		kinds[i] = game.Kind(kind)
+	}
+
+	return services.BankSelection{
+		QuestionIds: ids,
+		Search:      r.Search,
+		Tags:        r.Tags,
+		Kinds:       kinds,
+		Category:    r.Category,
+		Difficulty:  game.Difficulty(r.Difficulty),
+		Count:       r.Count,
+	}
+}
+
+// CreateGameFromBankRequest
+//
+//	@Description	Request to create a Game out of copies of questions of the bank
+type CreateGameFromBankRequest struct {
+	// the title of a game
+	Title string `json:"title"       validate:"required"`
+	// the description of a game
+	Description string `json:"description" validate:"omitempty"`
+	// questions of the game
+	Selection BankSelectionRequest `json:"selection"   validate:"required"`
+	// save the game as an incomplete draft instead of publishing it
+	Draft bool `json:"draft"`
+}
+
+// BankQuestionResponse
+//
+//	@Description	A question of the bank
+type BankQuestionResponse struct {
+	// id of the question in the bank
+	Id string `json:"id"`
+	// kind of the question
+	Kind string `json:"kind"`
+	// the question, as in games
+	Data game.Question `json:"data" swaggertype:"object"`
+	// tags, lowercase and sorted
+	Tags      []string  `json:"tags"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newBankQuestionResponse(q *game.BankQuestion) BankQuestionResponse {
+	return BankQuestionResponse{
+		Id:        q.Id.String(),
+		Kind:      string(q.Question.Kind()),
+		Data:      q.Question,
+		Tags:      q.Tags,
+		CreatedAt: q.CreatedAt,
+		UpdatedAt: q.UpdatedAt,
+	}
+}
+
+// BankQuestionsResponse
+//
+//	@Description	A page of the questions of the bank
+type BankQuestionsResponse struct {
+	// questions in the page, newest first
+	Questions []BankQuestionResponse `json:"questions"`
+	// the page, starting at 1
+	Page int `json:"page"`
+	// the maximum amount of questions in a page
+	PageSize int `json:"page_size"`
+	// amount of questions matching the search across all pages
+	Total int `json:"total"`
+}
+
+type bankHandler struct {
+	jwtMiddleware     fiber.Handler
+	validationService *services.ValidationService
+	bankService       *services.BankService
+}
+
+func NewBankHandler(
+	jwtMiddleware fiber.Handler,
+	validationService *services.ValidationService,
+	bankService *services.BankService,
+) *bankHandler {
+	return &bankHandler{
+		jwtMiddleware:     jwtMiddleware,
+		validationService: validationService,
+		bankService:       bankService,
+	}
+}
+
+func (h *bankHandler) RegisterRoutes(router fiber.Router) {
+	bankApi := router.Group("/bank")
+
+	bankApi.Use(h.jwtMiddleware)
+	bankApi.Post("/", h.SaveQuestion)
+	bankApi.Get("/", h.SearchQuestions)
+	bankApi.Post("/game", h.CreateGameFromBank)
+	bankApi.Post("/game/:gameId", h.AddToGame)
+	bankApi.Post("/from-game/:gameId", h.SaveGameQuestions)
+	bankApi.Get("/:questionId", h.GetQuestion)
+	bankApi.Put("/:questionId", h.UpdateQuestion)
+	bankApi.Delete("/:questionId", h.DeleteQuestion)
+}
+
+// SaveQuestion godoc
+//
+//	@Summary	Save a question to the bank
+//	@Tags		Bank
+//	@Accept		json
+//	@Produce	json
+//	@Param		req	body		SaveBankQuestionRequest	true	"Question"
+//	@Success	201	{object}	BankQuestionResponse
+//	@Failure	400	{string}	string
+//	@Failure	401	{string}	string
+//	@Failure	422	{object}	ValidationErrorResponse
+//	@Router		/bank/ [post]
+func (h *bankHandler) SaveQuestion(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	req := new(SaveBankQuestionRequest)
+	err := c.BodyParser(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	question, err := req.toSaveBankQuestionRequest(h.validationService)
+	if err != nil {
+		return handleBankError(c, err)
+	}
+
+	saved, err := h.bankService.SaveQuestion(c.Context(), userId, question)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newBankQuestionResponse(saved))
+}
+
+// SearchQuestions godoc
+//
+//	@Summary	Search the questions of the bank
+//	@Tags		Bank
+//	@Produce	json
+//	@Param		search		query		string		false	"Text the title must contain"
+//	@Param		tag			query		[]string	false	"Tags the questions must all have"	collectionFormat(multi)
+//	@Param		kind		query		[]string	false	"Kinds the questions may be of"		collectionFormat(multi)
+//	@Param		category	query		string		false	"Category of the questions"
+//	@Param		difficulty	query		string		false	"easy, medium or hard"
+//	@Param		page		query		int			false	"Page, starting at 1"
+//	@Param		page_size	query		int			false	"Questions per page, at most 50"
+//	@Success	200			{object}	BankQuestionsResponse
+//	@Failure	401			{string}	string
+//	@Failure	422			{object}	ValidationErrorResponse
+//	@Router		/bank/ [get]
+func (h *bankHandler) SearchQuestions(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	kinds := make([]game.Kind, 0)
+	for _, kind := range queryList(c, "kind") {
+		kinds = append(kinds, game.Kind(kind))
+	}
+
+	page, err := h.bankService.SearchQuestions(c.Context(), userId, &services.SearchBankRequest{
+		Search:     c.Query("search"),
+		Tags:       queryList(c, "tag"),
+		Kinds:      kinds,
+		Category:   c.Query("category"),
+		Difficulty: game.Difficulty(c.Query("difficulty")),
+		Page:       c.QueryInt("page"),
+		PageSize:   c.QueryInt("page_size"),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := BankQuestionsResponse{
+		Questions: make([]BankQuestionResponse, len(page.Questions)),
+		Page:      page.Page,
+		PageSize:  page.PageSize,
+		Total:     page.Total,
+	}
+	for i, q := range page.Questions {
+		resp.Questions[i] = newBankQuestionResponse(q)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetQuestion godoc
+//
+//	@Summary	Get a question of the bank
+//	@Tags		Bank
+//	@Produce	json
+//	@Param		questionId	path		string	true	"Question id"
+//	@Success	200			{object}	BankQuestionResponse
+//	@Failure	400			{string}	string
+//	@Failure	401			{string}	string
+//	@Failure	403			{string}	string
+//	@Failure	404			{string}	string
+//	@Router		/bank/{questionId} [get]
+func (h *bankHandler) GetQuestion(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	questionId, err := uuid.Parse(c.Params("questionId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	found, err := h.bankService.GetQuestion(c.Context(), userId, questionId)
+	if err != nil {
+		return handleBankError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newBankQuestionResponse(found))
+}
+
+// UpdateQuestion godoc
+//
+//	@Summary	Replace a question of the bank
+//	@Tags		Bank
+//	@Accept		json
+//	@Produce	json
+//	@Param		questionId	path		string					true	"Question id"
+//	@Param		req			body		SaveBankQuestionRequest	true	"Question"
+//	@Success	200			{object}	BankQuestionResponse
+//	@Failure	400			{string}	string
+//	@Failure	401			{string}	string
+//	@Failure	403			{string}	string
+//	@Failure	404			{string}	string
+//	@Failure	422			{object}	ValidationErrorResponse
+//	@Router		/bank/{questionId} [put]
+func (h *bankHandler) UpdateQuestion(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	questionId, err := uuid.Parse(c.Params("questionId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	req := new(SaveBankQuestionRequest)
+	err = c.BodyParser(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	question, err := req.toSaveBankQuestionRequest(h.validationService)
+	if err != nil {
+		return handleBankError(c, err)
+	}
+
+	updated, err := h.bankService.UpdateQuestion(c.Context(), userId, questionId, question)
+	if err != nil {
+		return handleBankError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newBankQuestionResponse(updated))
+}
+
+// DeleteQuestion godoc
+//
+//	@Summary	Delete a question of the bank
+//	@Tags		Bank
+//	@Param		questionId	path	string	true	"Question id"
+//	@Success	204
+//	@Failure	400	{string}	string
+//	@Failure	401	{string}	string
+//	@Failure	403	{string}	string
+//	@Failure	404	{string}	string
+//	@Router		/bank/{questionId} [delete]
+func (h *bankHandler) DeleteQuestion(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	questionId, err := uuid.Parse(c.Params("questionId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	err = h.bankService.DeleteQuestion(c.Context(), userId, questionId)
+	if err != nil {
+		return handleBankError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SaveGameQuestions godoc
+//
+//	@Summary	Save every question of a Game to the bank
+//	@Tags		Bank
+//	@Accept		json
+//	@Produce	json
+//	@Param		gameId	path		string					true	"Game id"
+//	@Param		req		body		SaveGameToBankRequest	false	"Tags of the saved questions"
+//	@Success	201		{array}		BankQuestionResponse
+//	@Failure	400		{string}	string
+//	@Failure	401		{string}	string
+//	@Failure	403		{string}	string
+//	@Failure	404		{string}	string
+//	@Failure	422		{object}	ValidationErrorResponse
+//	@Router		/bank/from-game/{gameId} [post]
+func (h *bankHandler) SaveGameQuestions(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	req := new(SaveGameToBankRequest)
+	if len(c.Body()) > 0 {
+		err = c.BodyParser(req)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+	}
+
+	err = h.validationService.Validate(req)
+	if err != nil {
+		return err
+	}
+
+	saved, err := h.bankService.SaveGameQuestions(c.Context(), userId, gameId, req.Tags)
+	if err != nil {
+		return handleBankError(c, err)
+	}
+
+	resp := make([]BankQuestionResponse, len(saved))
+	for i, q := range saved {
+		resp[i] = newBankQuestionResponse(q)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// CreateGameFromBank godoc
+//
+//	@Summary		Create a Game from questions of the bank
+//	@Description	Creates a Game out of copies of questions of the bank, either given by id or picked at random, e.g. 10 random questions tagged photosynthesis
+//	@Tags			Bank
+//	@Accept			json
+//	@Produce		json
+//	@Param			req	body	CreateGameFromBankRequest	true	"Game and its questions"
+//	@Success		201
+//	@Failure		400	{string}	string
+//	@Failure		401	{string}	string
+//	@Failure		403	{string}	string
+//	@Failure		404	{string}	string
+//	@Failure		422	{object}	ValidationErrorResponse
+//	@Router			/bank/game [post]
+func (h *bankHandler) CreateGameFromBank(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	req := new(CreateGameFromBankRequest)
+	err := c.BodyParser(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	err = h.validationService.Validate(req)
+	if err != nil {
+		return err
+	}
+
+	created, err := h.bankService.CreateGameFromBank(c.Context(), userId, &services.CreateGameFromBankRequest{
+		Title:       req.Title,
+		Description: req.Description,
+		Selection:   req.Selection.ToBankSelection(),
+		Draft:       req.Draft,
+	})
+	if err != nil {
+		return handleBankError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"game": created,
+	})
+}
+
+// AddToGame godoc
+//
+//	@Summary		Add questions of the bank to a Game
+//	@Description	Appends copies of questions of the bank to a Game, later changes to the bank don't affect them
+//	@Tags			Bank
+//	@Accept			json
+//	@Produce		json
+//	@Param			gameId	path	string					true	"Game id"
+//	@Param			req		body	BankSelectionRequest	true	"Questions to add"
+//	@Success		200
+//	@Failure		400	{string}	string
+//	@Failure		401	{string}	string
+//	@Failure		403	{string}	string
+//	@Failure		404	{string}	string
+//	@Failure		422	{object}	ValidationErrorResponse
+//	@Router			/bank/game/{gameId} [post]
+func (h *bankHandler) AddToGame(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	req := new(BankSelectionRequest)
+	err = c.BodyParser(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	err = h.validationService.Validate(req)
+	if err != nil {
+		return err
+	}
+
+	selection := req.ToBankSelection()
+	updated, err := h.bankService.AddToGame(c.Context(), userId, gameId, &selection)
+	if err != nil {
+		return handleBankError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"game": updated,
+	})
+}
+
+func handleBankError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, ports.ErrBankQuestionNotFound) || errors.Is(err, ports.ErrGameNotFound) {
+		return c.Status(fiber.StatusNotFound).SendString(err.Error())
+	}
+	if errors.Is(err, game.ErrUnknownQuestionKind) {
+		return c.SendStatus(fiber.StatusBadRequest)
+	}
+	return err
+}
+
+// queryList reads a query parameter given many times, or once with its
+// values separated by commas.
+func queryList(c *fiber.Ctx, key string) []string {
+	values := make([]string, 0)
+	for _, raw := range c.Context().QueryArgs().PeekMulti(key) {
+		for _, value := range strings.Split(string(raw), ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
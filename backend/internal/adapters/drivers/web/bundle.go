@@ -0,0 +1,374 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+
+	"github.com/taldoflemis/brain.test/internal/adapters/drivers/interchange"
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+)
+
+const (
+	// bundleFormat tells exports of games apart from any other document
+	bundleFormat = "brain.test/game"
+	// bundleVersion is bumped whenever exports change in a way older
+	// instances can't import
+	bundleVersion = 1
+
+	yamlContentType = "application/yaml"
+
+	importPath = gamePath + "/import"
+	// maxImportSize lets a bundle embed as much media as an export may, in
+	// base64, along the game itself
+	maxImportSize = int(services.MaxBundledMediaSize)/3*4 + fiber.DefaultBodyLimit
+)
+
+// GameBundle
+//
+//	@Description	A Game exported to be backed up or moved to another instance
+type GameBundle struct {
+	// always brain.test/game
+	Format string `json:"format"      validate:"required,eq=brain.test/game"`
+	// version of the bundle format
+	Version int `json:"version"     validate:"required,eq=1"`
+	// when the game was exported
+	ExportedAt time.Time `json:"exported_at"`
+	// the game, without its questions
+	Game GameBundleInfo `json:"game"        validate:"required"`
+	// questions of the game in order
+	Questions []GameBundleQuestion `json:"questions"   validate:"omitempty,dive"`
+	// content of the media the questions reference, when exported along
+	Media []GameBundleMedia `json:"media"       validate:"omitempty,dive"`
+}
+
+// GameBundleInfo
+//
+//	@Description	The info of an exported Game
+type GameBundleInfo struct {
+	MetadataRequest
+	// the title of a game
+	Title string `json:"title"       validate:"required"`
+	// the description of a game
+	Description string `json:"description" validate:"omitempty"`
+	// how answers are scored, the default policy is used when omitted
+	Scoring *ScoringPolicyRequest `json:"scoring"     validate:"omitempty"`
+	// one of private, unlisted or public, defaults to private
+	Visibility string `json:"visibility"  validate:"omitempty,oneof=private unlisted public"`
+	// drafts are imported as drafts, published games are published again
+	Status string `json:"status"      validate:"omitempty,oneof=draft published"`
+}
+
+// GameBundleQuestion
+//
+//	@Description	An exported question, its data has every field of its kind
+type GameBundleQuestion struct {
+	// kind
+	Kind game.Kind `json:"kind" validate:"required" swaggertype:"string"`
+	// data
+	Data json.RawMessage `json:"data" validate:"required" swaggertype:"object"`
+}
+
+// GameBundleMedia
+//
+//	@Description	An exported media, uploaded again on import
+type GameBundleMedia struct {
+	// the id questions of the bundle reference the media by
+	Id string `json:"id"           validate:"required,uuid"`
+	// image, audio or video
+	Kind string `json:"kind"         validate:"omitempty,oneof=image audio video"`
+	// the content type of the file
+	ContentType string `json:"content_type" validate:"omitempty"`
+	// size of the file in bytes
+	Size int64 `json:"size"         validate:"gte=0"`
+	// the file encoded in base64
+	Content []byte `json:"content"      validate:"required" swaggertype:"string" format:"base64"`
+}
+
+// ExportGame godoc
+//
+//	@Summary		Export a Game
+//	@Description	Exports a Game with all its questions, to be imported back later or into another instance
+//	@Tags			Game
+//	@Produce		json
+//	@Produce		application/yaml
+//	@Produce		plain
+//	@Produce		xml
+//	@Param			gameId			path		string	true	"Game id"
+//	@Param			format			query		string	false	"json or yaml, defaults to json, or gift, aiken or moodle_xml to only export the questions"
+//	@Param			include_media	query		bool	false	"Embed the content of the media the questions reference"
+//	@Success		200				{object}	GameBundle
+//	@Failure		400				{string}	string
+//	@Failure		401				{string}	string
+//	@Failure		403				{string}	string
+//	@Failure		404				{string}	string
+//	@Failure		422				{object}	ValidationErrorResponse
+//	@Router			/game/{gameId}/export [get]
+func (h *gameHandler) ExportGame(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "yaml" {
+		if _, err := interchange.Extension(interchange.Format(format)); err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString("format must be json, yaml, gift, aiken or moodle_xml")
+		}
+	}
+
+	// other formats can't embed media
+	includeMedia := c.QueryBool("include_media") && (format == "json" || format == "yaml")
+
+	exported, err := h.gameService.ExportGame(c.Context(), userId, gameId, includeMedia)
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
+	if format != "json" && format != "yaml" {
+		return sendQuestions(c, interchange.Format(format), exported.Game)
+	}
+
+	bundle, err := newGameBundle(exported)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	contentType := fiber.MIMEApplicationJSON
+	if format == "yaml" {
+		body, err = jsonToYAML(body)
+		if err != nil {
+			return err
+		}
+		contentType = yamlContentType
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, gameId, format))
+
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// ImportGame godoc
+//
+//	@Summary		Import a Game
+//	@Description	Creates a new Game from an export, sent as JSON or as YAML with a YAML content type
+//	@Tags			Game
+//	@Accept			json
+//	@Accept			application/yaml
+//	@Produce		json
+//	@Param			req	body	GameBundle	true	"Exported Game"
+//	@Success		201
+//	@Failure		400	{string}	string
+//	@Failure		401	{string}	string
+//	@Failure		413	{string}	string
+//	@Failure		422	{object}	ValidationErrorResponse
+//	@Router			/game/import [post]
+func (h *gameHandler) ImportGame(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	if streamTooLarge(c, maxImportSize) {
+		return c.Status(fiber.StatusRequestEntityTooLarge).SendString(fiber.ErrRequestEntityTooLarge.Message)
+	}
+
+	body := c.Body()
+	if isYAML(c.Get(fiber.HeaderContentType)) {
+		var err error
+		body, err = yamlToJSON(body)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+	}
+
+	bundle := new(GameBundle)
+	err := json.Unmarshal(body, bundle)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	err = h.validationService.Validate(bundle)
+	if err != nil {
+		return err
+	}
+
+	req, err := bundle.toImportGameRequest()
+	if err != nil {
+		if errors.Is(err, game.ErrUnknownQuestionKind) {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	imported, err := h.gameService.ImportGame(c.Context(), userId, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"game": imported,
+	})
+}
+
+func newGameBundle(exported *services.ExportedGame) (*GameBundle, error) {
+	g := exported.Game
+
+	bundle := &GameBundle{
+		Format:     bundleFormat,
+		Version:    bundleVersion,
+		ExportedAt: time.Now().UTC(),
+		Game: GameBundleInfo{
+			Title:       g.Title,
+			Description: g.Description,
+			Visibility:  string(g.VisibilityOrDefault()),
+			Status:      string(g.StatusOrDefault()),
+			MetadataRequest: MetadataRequest{
+				Tags:       g.Tags,
+				Category:   g.Category,
+				Difficulty: string(g.Difficulty),
+			},
+		},
+		Questions: make([]GameBundleQuestion, len(g.Questions)),
+		Media:     make([]GameBundleMedia, len(exported.Media)),
+	}
+
+	if g.Scoring != nil {
+		bundle.Game.Scoring = &ScoringPolicyRequest{
+			BasePoints:     g.Scoring.BasePoints,
+			SpeedWeight:    g.Scoring.SpeedWeight,
+			StreakBonus:    g.Scoring.StreakBonus,
+			MaxStreakBonus: g.Scoring.MaxStreakBonus,
+		}
+	}
+
+	for i, q := range g.Questions {
+		data, err := json.Marshal(q)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Questions[i] = GameBundleQuestion{
+			Kind: q.Kind(),
+			Data: data,
+		}
+	}
+
+	for i, m := range exported.Media {
+		bundle.Media[i] = GameBundleMedia{
+			Id:          m.File.Id.String(),
+			Kind:        string(m.File.Kind),
+			ContentType: m.File.ContentType,
+			Size:        m.File.Size,
+			Content:     m.Content,
+		}
+	}
+
+	return bundle, nil
+}
+
+func (b *GameBundle) toImportGameRequest() (*services.ImportGameRequest, error) {
+	questions := make([]game.Question, len(b.Questions))
+	for i, q := range b.Questions {
+		question, err := game.DecodeQuestion(q.Kind, q.Data)
+		if err != nil {
+			return nil, err
+		}
+		questions[i] = question
+	}
+
+	media := make([]services.ImportedMedia, len(b.Media))
+	for i, m := range b.Media {
+		media[i] = services.ImportedMedia{
+			Id:      uuid.MustParse(m.Id),
+			Content: m.Content,
+		}
+	}
+
+	return &services.ImportGameRequest{
+		Game: &game.Game{
+			Title:       b.Game.Title,
+			Description: b.Game.Description,
+			Questions:   questions,
+			Scoring:     b.Game.Scoring.ToScoringPolicy(),
+			Visibility:  game.Visibility(b.Game.Visibility),
+			Status:      game.Status(b.Game.Status),
+			Metadata:    b.Game.ToMetadata(),
+		},
+		Media: media,
+	}, nil
+}
+
+func isYAML(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch mediaType {
+	case yamlContentType, "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	default:
+		return false
+	}
+}
+
+// yamlToJSON converts YAML to JSON so that bundles are decoded the same way,
+// whatever they were written in.
+func yamlToJSON(body []byte) ([]byte, error) {
+	var document any
+	err := yaml.Unmarshal(body, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(document)
+}
+
+func jsonToYAML(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document any
+	err := decoder.Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(yamlNumbers(document))
+}
+
+// yamlNumbers turns the numbers of a JSON document into integers when they
+// are, rather than floats written in scientific notation.
+func yamlNumbers(document any) any {
+	switch v := document.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = yamlNumbers(value)
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = yamlNumbers(value)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		n, _ := v.Float64()
+		return n
+	}
+
+	return document
+}
@@ -3,6 +3,7 @@ package web
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -12,33 +13,135 @@ import (
 	"github.com/taldoflemis/brain.test/internal/ports"
 )
 
-type QuestionKind string
-
-const (
-	QuizQuestionKind      = "quiz"
-	TrueFalseQuestionKind = "true_false"
-)
-
-var (
-	ErrUnknownQuestionKind = errors.New("Unknown question kind")
-)
+const gamePath = "/game"
 
 type Question interface {
 	LoadFromMap(data map[string]any) error
 	ToQuestion() game.Question
 }
 
+// questionRequests are the requests kinds are created from, see
+// registerQuestionRequest. Kinds without one are decoded straight into their
+// question, see documentQuestionRequest.
+var questionRequests = make(map[game.Kind]func() Question)
+
+// registerQuestionRequest makes the request of a kind known, every kind is
+// registered from the init below. Registering a kind twice panics.
+func registerQuestionRequest(kind game.Kind, newRequest func() Question) {
+	if _, ok := questionRequests[kind]; ok {
+		panic(fmt.Sprintf("web: request of question kind %q registered twice", kind))
+	}
+
+	questionRequests[kind] = newRequest
+}
+
+func init() {
+	registerQuestionRequest(game.QuizKind, func() Question { return new(CreateQuizQuestionRequest) })
+	registerQuestionRequest(game.TrueFalseKind, func() Question { return new(CreateTrueFalseQuestionRequest) })
+	registerQuestionRequest(game.TypeAnswerKind, func() Question { return new(CreateTypeAnswerQuestionRequest) })
+	registerQuestionRequest(game.OrderingKind, func() Question { return new(CreateOrderingQuestionRequest) })
+	registerQuestionRequest(game.SliderKind, func() Question { return new(CreateSliderQuestionRequest) })
+	registerQuestionRequest(game.PollKind, func() Question { return new(CreatePollQuestionRequest) })
+	registerQuestionRequest(game.WordCloudKind, func() Question { return new(CreateWordCloudQuestionRequest) })
+}
+
+// documentQuestionRequest creates a question of a kind without a request of
+// its own, the data using the field names of the question itself.
+type documentQuestionRequest struct {
+	question game.Question
+}
+
+func newDocumentQuestionRequest(spec *game.KindSpec) *documentQuestionRequest {
+	return &documentQuestionRequest{question: spec.New(game.Header{})}
+}
+
+func (r *documentQuestionRequest) LoadFromMap(data map[string]any) error {
+	jsonString, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(jsonString, r.question)
+	return err
+}
+
+func (r *documentQuestionRequest) ToQuestion() game.Question {
+	return r.question
+}
+
+// MediaRequest
+//
+//	@Description	An uploaded image, audio or video shown along a question or alternative
+type MediaRequest struct {
+	// id of the uploaded media
+	Id string `json:"id"    validate:"required,uuid"`
+	// image, audio or video
+	Kind string `json:"kind"  validate:"required,oneof=image audio video"`
+	// seconds into an audio or video where it starts playing
+	Start float64 `json:"start" validate:"gte=0"`
+	// seconds into an audio or video where it stops playing, 0 plays it until the end
+	End float64 `json:"end"   validate:"omitempty,gtfield=Start"`
+}
+
+func (r *MediaRequest) ToMedia() *game.Media {
+	if r == nil {
+		return nil
+	}
+
+	// Malformed ids are rejected when validating the request, drafts keep
+	// them as the nil id which is never uploaded
+	id, _ := uuid.Parse(r.Id)
+
+	return &game.Media{
+		Id:    id,
+		Kind:  game.MediaKind(r.Kind),
+		Start: r.Start,
+		End:   r.End,
+	}
+}
+
+// MetadataRequest
+//
+//	@Description	Tags, category and difficulty to find a game or question by
+type MetadataRequest struct {
+	// free-form tags, e.g. photosynthesis, ignoring case
+	Tags []string `json:"tags"       validate:"lte=20,dive,required,lte=40"`
+	// the subject, e.g. biology, ignoring case
+	Category string `json:"category"   validate:"lte=60"`
+	// easy, medium or hard
+	Difficulty string `json:"difficulty" validate:"omitempty,oneof=easy medium hard"`
+}
+
+func (r *MetadataRequest) ToMetadata() game.Metadata {
+	return game.Metadata{
+		Tags:       r.Tags,
+		Category:   r.Category,
+		Difficulty: game.Difficulty(r.Difficulty),
+	}
+}
+
 // CreateQuizQuestionRequest
 //
 //	@Description	Request to create a Quiz Question
 type CreateQuizQuestionRequest struct {
-	Title        string `json:"title"        validate:"required"`
-	Points       int    `json:"points"       validate:"required"`
-	TimeLimit    int    `json:"time_limit"   validate:"required"`
+	MetadataRequest
+	Title     string `json:"title"        validate:"required"`
+	Points    int    `json:"points"       validate:"required"`
+	TimeLimit int    `json:"time_limit"   validate:"required"`
+	// single or multiple, defaults to multiple
+	Mode string `json:"mode"         validate:"omitempty,oneof=single multiple"`
+	// all_or_nothing, proportional or penalty, defaults to all_or_nothing
+	Credit       string `json:"credit"       validate:"omitempty,oneof=all_or_nothing proportional penalty"`
 	Alternatives []struct {
-		Data      string `json:"data" validate:"required"`
-		IsCorrect bool   `json:"is_correct" validate:"required"`
+		Data      string        `json:"data" validate:"required"`
+		IsCorrect bool          `json:"is_correct" validate:"required"`
+		Media     *MediaRequest `json:"media" validate:"omitempty"`
+		// plain, markdown or markdown_latex, defaults to plain
+		Format string `json:"format" validate:"omitempty,oneof=plain markdown markdown_latex"`
 	} `json:"alternatives" validate:"required,dive,required"`
+	// image, audio or video shown along the question
+	Media *MediaRequest `json:"media" validate:"omitempty"`
+	// how the title is written, plain, markdown or markdown_latex, defaults to plain
+	Format string `json:"format" validate:"omitempty,oneof=plain markdown markdown_latex"`
 }
 
 func (r *CreateQuizQuestionRequest) LoadFromMap(data map[string]any) error {
@@ -56,13 +159,20 @@ func (r *CreateQuizQuestionRequest) ToQuestion() game.Question {
 		alternatives[i] = game.Alternative{
 			Data:      a.Data,
 			IsCorrect: a.IsCorrect,
+			Media:     a.Media.ToMedia(),
+			Format:    game.TextFormat(a.Format),
 		}
 	}
 
 	return &game.QuizQuestion{
 		Title:        r.Title,
+		Metadata:     r.ToMetadata(),
 		Points:       game.Points(r.Points),
 		TimeLimit:    game.TimeLimit(r.TimeLimit),
+		Media:        r.Media.ToMedia(),
+		Format:       game.TextFormat(r.Format),
+		Mode:         game.QuizMode(r.Mode),
+		Credit:       game.CreditPolicy(r.Credit),
 		Alternatives: alternatives,
 	}
 }
@@ -71,11 +181,16 @@ func (r *CreateQuizQuestionRequest) ToQuestion() game.Question {
 //
 //	@Description	Request to create a True False Question
 type CreateTrueFalseQuestionRequest struct {
+	MetadataRequest
 	Title            string `json:"title"             validate:"required"`
 	Points           int    `json:"points"            validate:"required"`
 	TimeLimit        int    `json:"time_limit"        validate:"required"`
 	TrueAlternative  string `json:"true_alternative"  validate:"required"`
 	FalseAlternative string `json:"false_alternative" validate:"required"`
+	// image, audio or video shown along the question
+	Media *MediaRequest `json:"media" validate:"omitempty"`
+	// how the title and alternatives are written, plain, markdown or markdown_latex, defaults to plain
+	Format string `json:"format" validate:"omitempty,oneof=plain markdown markdown_latex"`
 }
 
 func (r *CreateTrueFalseQuestionRequest) LoadFromMap(data map[string]any) error {
@@ -90,33 +205,355 @@ func (r *CreateTrueFalseQuestionRequest) LoadFromMap(data map[string]any) error
 func (r *CreateTrueFalseQuestionRequest) ToQuestion() game.Question {
 	return &game.TrueFalseQuestion{
 		Title:            r.Title,
+		Metadata:         r.ToMetadata(),
 		Points:           game.Points(r.Points),
 		TimeLimit:        game.TimeLimit(r.TimeLimit),
+		Media:            r.Media.ToMedia(),
+		Format:           game.TextFormat(r.Format),
 		TrueAlternative:  r.TrueAlternative,
 		FalseAlternative: r.FalseAlternative,
 	}
 }
 
+// CreateTypeAnswerQuestionRequest
+//
+//	@Description	Request to create a question answered by typing free text
+type CreateTypeAnswerQuestionRequest struct {
+	MetadataRequest
+	Title     string `json:"title"            validate:"required"`
+	Points    int    `json:"points"           validate:"required"`
+	TimeLimit int    `json:"time_limit"       validate:"required"`
+	// answers that are accepted, required unless a pattern is given
+	AcceptedAnswers []string `json:"accepted_answers" validate:"required_without=Pattern,dive,required"`
+	// whether letter case must match, defaults to false
+	CaseSensitive bool `json:"case_sensitive"`
+	// whether accents must match, defaults to false
+	AccentSensitive bool `json:"accent_sensitive"`
+	// how many typos, in Levenshtein distance, are forgiven
+	Tolerance int `json:"tolerance"        validate:"gte=0"`
+	// regular expression the whole answer may match instead
+	Pattern string `json:"pattern"`
+	// image, audio or video shown along the question
+	Media *MediaRequest `json:"media" validate:"omitempty"`
+	// how the title is written, plain, markdown or markdown_latex, defaults to plain
+	Format string `json:"format" validate:"omitempty,oneof=plain markdown markdown_latex"`
+}
+
+func (r *CreateTypeAnswerQuestionRequest) LoadFromMap(data map[string]any) error {
+	jsonString, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(jsonString, &r)
+	return err
+}
+
+func (r *CreateTypeAnswerQuestionRequest) ToQuestion() game.Question {
+	return &game.TypeAnswerQuestion{
+		Title:           r.Title,
+		Metadata:        r.ToMetadata(),
+		Points:          game.Points(r.Points),
+		TimeLimit:       game.TimeLimit(r.TimeLimit),
+		Media:           r.Media.ToMedia(),
+		Format:          game.TextFormat(r.Format),
+		AcceptedAnswers: r.AcceptedAnswers,
+		CaseSensitive:   r.CaseSensitive,
+		AccentSensitive: r.AccentSensitive,
+		Tolerance:       r.Tolerance,
+		Pattern:         r.Pattern,
+	}
+}
+
+// CreateOrderingQuestionRequest
+//
+//	@Description	Request to create a question answered by putting items in order
+type CreateOrderingQuestionRequest struct {
+	MetadataRequest
+	Title     string `json:"title"           validate:"required"`
+	Points    int    `json:"points"          validate:"required"`
+	TimeLimit int    `json:"time_limit"      validate:"required"`
+	// between 2 and 6 items, in the right order
+	Items []string `json:"items"           validate:"required,min=2,max=6,dive,required"`
+	// reward every item placed right instead of only the whole sequence
+	PartialScoring bool `json:"partial_scoring"`
+	// image, audio or video shown along the question
+	Media *MediaRequest `json:"media" validate:"omitempty"`
+	// how the title and items are written, plain, markdown or markdown_latex, defaults to plain
+	Format string `json:"format" validate:"omitempty,oneof=plain markdown markdown_latex"`
+}
+
+func (r *CreateOrderingQuestionRequest) LoadFromMap(data map[string]any) error {
+	jsonString, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(jsonString, &r)
+	return err
+}
+
+func (r *CreateOrderingQuestionRequest) ToQuestion() game.Question {
+	return &game.OrderingQuestion{
+		Title:          r.Title,
+		Metadata:       r.ToMetadata(),
+		Points:         game.Points(r.Points),
+		TimeLimit:      game.TimeLimit(r.TimeLimit),
+		Media:          r.Media.ToMedia(),
+		Format:         game.TextFormat(r.Format),
+		Items:          r.Items,
+		PartialScoring: r.PartialScoring,
+	}
+}
+
+// CreateSliderQuestionRequest
+//
+//	@Description	Request to create a question answered by picking a number on a slider
+type CreateSliderQuestionRequest struct {
+	MetadataRequest
+	Title     string  `json:"title"                validate:"required"`
+	Points    int     `json:"points"               validate:"required"`
+	TimeLimit int     `json:"time_limit"           validate:"required"`
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"                  validate:"gtfield=Min"`
+	Step      float64 `json:"step"                 validate:"gt=0"`
+	// the right answer
+	Target float64 `json:"target"`
+	// how far from the target an answer may be and still be right
+	Tolerance float64 `json:"tolerance"            validate:"gte=0"`
+	// reward answers outside of the tolerance by how close they are
+	ProportionalScoring bool `json:"proportional_scoring"`
+	// image, audio or video shown along the question
+	Media *MediaRequest `json:"media" validate:"omitempty"`
+	// how the title is written, plain, markdown or markdown_latex, defaults to plain
+	Format string `json:"format" validate:"omitempty,oneof=plain markdown markdown_latex"`
+}
+
+func (r *CreateSliderQuestionRequest) LoadFromMap(data map[string]any) error {
+	jsonString, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(jsonString, &r)
+	return err
+}
+
+func (r *CreateSliderQuestionRequest) ToQuestion() game.Question {
+	return &game.SliderQuestion{
+		Title:               r.Title,
+		Metadata:            r.ToMetadata(),
+		Points:              game.Points(r.Points),
+		TimeLimit:           game.TimeLimit(r.TimeLimit),
+		Media:               r.Media.ToMedia(),
+		Format:              game.TextFormat(r.Format),
+		Min:                 r.Min,
+		Max:                 r.Max,
+		Step:                r.Step,
+		Target:              r.Target,
+		Tolerance:           r.Tolerance,
+		ProportionalScoring: r.ProportionalScoring,
+	}
+}
+
+// CreatePollQuestionRequest
+//
+//	@Description	Request to create an ungraded question where players pick one option
+type CreatePollQuestionRequest struct {
+	MetadataRequest
+	Title     string `json:"title"      validate:"required"`
+	TimeLimit int    `json:"time_limit" validate:"required"`
+	// between 2 and 6 options, none of them right
+	Options []string `json:"options"    validate:"required,min=2,max=6,dive,required"`
+	// image, audio or video shown along the question
+	Media *MediaRequest `json:"media" validate:"omitempty"`
+	// how the title and options are written, plain, markdown or markdown_latex, defaults to plain
+	Format string `json:"format" validate:"omitempty,oneof=plain markdown markdown_latex"`
+}
+
+func (r *CreatePollQuestionRequest) LoadFromMap(data map[string]any) error {
+	jsonString, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(jsonString, &r)
+	return err
+}
+
+func (r *CreatePollQuestionRequest) ToQuestion() game.Question {
+	return &game.PollQuestion{
+		Title:     r.Title,
+		Metadata:  r.ToMetadata(),
+		TimeLimit: game.TimeLimit(r.TimeLimit),
+		Media:     r.Media.ToMedia(),
+		Format:    game.TextFormat(r.Format),
+		Options:   r.Options,
+	}
+}
+
+// CreateWordCloudQuestionRequest
+//
+//	@Description	Request to create an ungraded question answered with a few words
+type CreateWordCloudQuestionRequest struct {
+	MetadataRequest
+	Title     string `json:"title"      validate:"required"`
+	TimeLimit int    `json:"time_limit" validate:"required"`
+	// image, audio or video shown along the question
+	Media *MediaRequest `json:"media" validate:"omitempty"`
+	// how the title is written, plain, markdown or markdown_latex, defaults to plain
+	Format string `json:"format" validate:"omitempty,oneof=plain markdown markdown_latex"`
+}
+
+func (r *CreateWordCloudQuestionRequest) LoadFromMap(data map[string]any) error {
+	jsonString, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(jsonString, &r)
+	return err
+}
+
+func (r *CreateWordCloudQuestionRequest) ToQuestion() game.Question {
+	return &game.WordCloudQuestion{
+		Title:     r.Title,
+		Metadata:  r.ToMetadata(),
+		TimeLimit: game.TimeLimit(r.TimeLimit),
+		Media:     r.Media.ToMedia(),
+		Format:    game.TextFormat(r.Format),
+	}
+}
+
 // CreateQuestionRequest
 //
 //	@Description	a way to create questions dynamically
 type CreateQuestionRequest struct {
 	// kind
-	Kind QuestionKind `json:"kind" validate:"required"`
+	Kind game.Kind `json:"kind" validate:"required" swaggertype:"string"`
 	// data
 	Data map[string]any `json:"data" validate:"required"`
 }
 
+// ScoringPolicyRequest
+//
+//	@Description	How answers to a game are turned into points
+type ScoringPolicyRequest struct {
+	// points of a correct answer to a question worth 1 point
+	BasePoints int `json:"base_points"      validate:"gte=0,lte=10000"`
+	// percentage of the base points that depends on the answer speed
+	SpeedWeight int `json:"speed_weight"     validate:"gte=0,lte=100"`
+	// bonus for every consecutive correct answer after the first
+	StreakBonus int `json:"streak_bonus"     validate:"gte=0,lte=1000"`
+	// cap of the streak bonus awarded for a single answer
+	MaxStreakBonus int `json:"max_streak_bonus" validate:"gte=0,lte=10000"`
+}
+
+func (r *ScoringPolicyRequest) ToScoringPolicy() *game.ScoringPolicy {
+	if r == nil {
+		return nil
+	}
+
+	return &game.ScoringPolicy{
+		BasePoints:     r.BasePoints,
+		SpeedWeight:    r.SpeedWeight,
+		StreakBonus:    r.StreakBonus,
+		MaxStreakBonus: r.MaxStreakBonus,
+	}
+}
+
 // CreateGameRequest
 //
 //	@Description	Request to create a Game
 type CreateGameRequest struct {
+	MetadataRequest
 	// the title of a game
 	Title string `json:"title"       validate:"required"`
 	// the description of a game
 	Description string `json:"description" validate:"omitempty"`
-	// questions of the game
-	Questions []CreateQuestionRequest `json:"questions"   validate:"required,dive,required"`
+	// questions of the game, drafts may have none
+	Questions []CreateQuestionRequest `json:"questions"   validate:"required_unless=Draft true,omitempty,dive,required"`
+	// how answers are scored, the default policy is used when omitted
+	Scoring *ScoringPolicyRequest `json:"scoring"     validate:"omitempty"`
+	// one of private, unlisted or public, defaults to private
+	Visibility string `json:"visibility"  validate:"omitempty,oneof=private unlisted public"`
+	// save the game as an incomplete draft instead of publishing it
+	Draft bool `json:"draft"`
+}
+
+// PatchGameRequest
+//
+//	@Description	Request to change part of a Game, omitted fields are kept
+type PatchGameRequest struct {
+	// the title of a game
+	Title *string `json:"title"       validate:"omitempty"`
+	// the description of a game
+	Description *string `json:"description" validate:"omitempty"`
+	// questions of the game, replacing all the current ones
+	Questions []CreateQuestionRequest `json:"questions"   validate:"omitempty,dive,required"`
+	// how answers are scored
+	Scoring *ScoringPolicyRequest `json:"scoring"     validate:"omitempty"`
+	// one of private, unlisted or public
+	Visibility *string `json:"visibility"  validate:"omitempty,oneof=private unlisted public"`
+	// free-form tags, replacing all the current ones
+	Tags *[]string `json:"tags"        validate:"omitempty,lte=20,dive,required,lte=40"`
+	// the subject, e.g. biology
+	Category *string `json:"category"    validate:"omitempty,lte=60"`
+	// easy, medium or hard
+	Difficulty *string `json:"difficulty"  validate:"omitempty,oneof=easy medium hard"`
+}
+
+// PublicGameResponse
+//
+//	@Description	A game of the public catalogue
+type PublicGameResponse struct {
+	// the id of the game
+	Id string `json:"id"`
+	// the title of the game
+	Title string `json:"title"`
+	// the description of the game
+	Description string `json:"description"`
+	// amount of questions in the game
+	QuestionCount int `json:"question_count"`
+	// amount of times the game was played until the end
+	PlayCount int `json:"play_count"`
+	// tags of the game, lowercase and sorted
+	Tags []string `json:"tags"`
+	// the subject of the game
+	Category string `json:"category"`
+	// easy, medium or hard, empty when unset
+	Difficulty string `json:"difficulty"`
+}
+
+// PublicGamesResponse
+//
+//	@Description	A page of the public catalogue
+type PublicGamesResponse struct {
+	// games in the page
+	Games []PublicGameResponse `json:"games"`
+	// the page, starting at 1
+	Page int `json:"page"`
+	// the maximum amount of games in a page
+	PageSize int `json:"page_size"`
+	// amount of games matching the search across all pages
+	Total int `json:"total"`
+}
+
+// GamesResponse
+//
+//	@Description	A page of the games of the user
+type GamesResponse struct {
+	// games in the page
+	Games []*game.Game `json:"games" swaggertype:"array,object"`
+	// amount of games matching the search and filters across all pages
+	Total int `json:"total"`
+	// cursor of the next page, empty on the last one
+	Next string `json:"next"`
+	// cursor of the previous page, empty on the first one
+	Prev string `json:"prev"`
+}
+
+// TagSuggestionsResponse
+//
+//	@Description	Tags already used by the user
+type TagSuggestionsResponse struct {
+	// at most 10 tags, the most used first
+	Tags []string `json:"tags"`
 }
 
 type gameHandler struct {
@@ -138,12 +575,28 @@ func NewGameHandler(
 }
 
 func (h *gameHandler) RegisterRoutes(router fiber.Router) {
-	gameApi := router.Group("/game")
+	gameApi := router.Group(gamePath)
+
+	gameApi.Get("/public", h.BrowsePublicGames)
+	gameApi.Get("/public/:gameId", h.GetSharedGame)
 
 	gameApi.Use(h.jwtMiddleware)
 	gameApi.Post("/", h.CreateGame)
+	gameApi.Post("/import", h.ImportGame)
+	gameApi.Post("/import/spreadsheet", h.ImportSpreadsheet)
+	gameApi.Post("/import/:format", h.ImportQuestions)
 	gameApi.Get("/", h.GetGamesByUserId)
+	gameApi.Get("/tags", h.SuggestTags)
 	gameApi.Get("/:gameId", h.GetGamesById)
+	gameApi.Put("/:gameId", h.UpdateGame)
+	gameApi.Patch("/:gameId", h.PatchGame)
+	gameApi.Delete("/:gameId", h.DeleteGame)
+	gameApi.Post("/:gameId/copy", h.CopyGame)
+	gameApi.Get("/:gameId/export", h.ExportGame)
+	gameApi.Post("/:gameId/publish", h.PublishGame)
+	gameApi.Get("/:gameId/revisions", h.GetRevisions)
+	gameApi.Get("/:gameId/revisions/:number", h.GetRevision)
+	gameApi.Post("/:gameId/revisions/:number/restore", h.RestoreRevision)
 }
 
 //	GetGameByUserId godoc
@@ -151,55 +604,272 @@ func (h *gameHandler) RegisterRoutes(router fiber.Router) {
 // @Summary	Get games by user id
 // @Tags		Game
 // @Accept		json
-// @Success	200
-// @Failure	400		{string}	string
-// @Failure	401		{string}	string
-// @Failure	422		{object}	ValidationErrorResponse
+// @Param		summary		query		bool		false	"Skip loading the questions of each game"
+// @Param		search		query		string		false	"Text the title must contain"
+// @Param		tag			query		[]string	false	"Tags the games must all have"	collectionFormat(multi)
+// @Param		category	query		string		false	"Category of the games"
+// @Param		difficulty	query		string		false	"easy, medium or hard"
+// @Param		sort		query		string		false	"created, updated or title, defaults to updated"
+// @Param		order		query		string		false	"asc or desc, defaults to desc for times and asc for titles"
+// @Param		after		query		string		false	"Cursor of the page before the one wanted"
+// @Param		before		query		string		false	"Cursor of the page after the one wanted"
+// @Param		page_size	query		int			false	"Games per page, at most 50"
+// @Success	200			{object}	GamesResponse
+// @Failure	400			{string}	string
+// @Failure	401			{string}	string
+// @Failure	422			{object}	ValidationErrorResponse
 // @Router		/game/	[get]
 func (h *gameHandler) GetGamesByUserId(c *fiber.Ctx) error {
 	userId := extractTokenFromContext(c)
 
-	games, err := h.gameService.GetGamesByUserId(c.Context(), userId)
+	page, err := h.gameService.GetGamesByUserId(c.Context(), userId, &services.GetGamesRequest{
+		Filter:      queryMetadataFilter(c),
+		Search:      c.Query("search"),
+		Sort:        ports.GameSort(c.Query("sort")),
+		Order:       c.Query("order"),
+		After:       c.Query("after"),
+		Before:      c.Query("before"),
+		PageSize:    c.QueryInt("page_size"),
+		SummaryOnly: c.QueryBool("summary"),
+	})
 
 	if err != nil {
 		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"games": games,
+	return c.Status(fiber.StatusOK).JSON(GamesResponse{
+		Games: page.Games,
+		Total: page.Total,
+		Next:  page.Next,
+		Prev:  page.Prev,
 	})
 }
 
-//	GetGameById godoc
+// SuggestTags godoc
 //
-// @Summary	Get games by id
-// @Tags		Game
-// @Accept		json
-// @Success	200
-// @Failure	401			{string}	string
-// @Failure	404			{string}	string
-// @Router		/game/:id	[get]
+//	@Summary		Autocomplete a tag
+//	@Description	Suggests tags already used on the games, questions or bank of the user, the most used first
+//	@Tags			Game
+//	@Produce		json
+//	@Param			prefix	query		string	false	"Start of the tag, ignoring case"
+//	@Success		200		{object}	TagSuggestionsResponse
+//	@Failure		401		{string}	string
+//	@Router			/game/tags [get]
+func (h *gameHandler) SuggestTags(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	tags, err := h.gameService.SuggestTags(c.Context(), userId, c.Query("prefix"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(TagSuggestionsResponse{Tags: tags})
+}
+
+// queryMetadataFilter reads the tag, category and difficulty query
+// parameters listings are filtered by.
+func queryMetadataFilter(c *fiber.Ctx) services.MetadataFilterRequest {
+	return services.MetadataFilterRequest{
+		Tags:       queryList(c, "tag"),
+		Category:   c.Query("category"),
+		Difficulty: game.Difficulty(c.Query("difficulty")),
+	}
+}
+
+// GetGameById godoc
+//
+//	@Summary	Get games by id
+//	@Tags		Game
+//	@Accept		json
+//	@Success	200
+//	@Failure	401			{string}	string
+//	@Failure	403			{string}	string
+//	@Failure	404			{string}	string
+//	@Router		/game/:id	[get]
 func (h *gameHandler) GetGamesById(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
 	gameId, err := uuid.Parse(c.Params("gameId"))
 
 	if err != nil {
-		return err
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
 	}
 
-	game, err := h.gameService.GetGameById(c.Context(), gameId)
+	game, err := h.gameService.GetGameById(c.Context(), userId, gameId)
 
 	if err != nil {
-		if errors.Is(err, ports.ErrGameNotFound) {
-			return c.Status(fiber.StatusNotFound).SendString(err.Error())
-		}
+		return h.handleGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"game": game,
+	})
+}
+
+// BrowsePublicGames godoc
+//
+//	@Summary	List the public catalogue of games
+//	@Tags		Game
+//	@Produce	json
+//	@Param		search		query		string		false	"Text the title or description must contain"
+//	@Param		tag			query		[]string	false	"Tags the games must all have"	collectionFormat(multi)
+//	@Param		category	query		string		false	"Category of the games"
+//	@Param		difficulty	query		string		false	"easy, medium or hard"
+//	@Param		sort		query		string		false	"recent or plays, defaults to recent"
+//	@Param		page		query		int			false	"Page, starting at 1"
+//	@Param		page_size	query		int			false	"Games per page, at most 50"
+//	@Success	200			{object}	PublicGamesResponse
+//	@Failure	422			{object}	ValidationErrorResponse
+//	@Router		/game/public [get]
+func (h *gameHandler) BrowsePublicGames(c *fiber.Ctx) error {
+	page, err := h.gameService.BrowsePublicGames(c.Context(), &services.BrowsePublicGamesRequest{
+		Search:   c.Query("search"),
+		Filter:   queryMetadataFilter(c),
+		Sort:     ports.GameSort(c.Query("sort")),
+		Page:     c.QueryInt("page"),
+		PageSize: c.QueryInt("page_size"),
+	})
+	if err != nil {
 		return err
 	}
 
+	games := make([]PublicGameResponse, len(page.Games))
+	for i, listing := range page.Games {
+		games[i] = PublicGameResponse{
+			Id:            listing.Game.Id.String(),
+			Title:         listing.Game.Title,
+			Description:   listing.Game.Description,
+			QuestionCount: listing.QuestionCount,
+			PlayCount:     listing.PlayCount,
+			Tags:          listing.Game.Tags,
+			Category:      listing.Game.Category,
+			Difficulty:    string(listing.Game.Difficulty),
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(PublicGamesResponse{
+		Games:    games,
+		Page:     page.Page,
+		PageSize: page.PageSize,
+		Total:    page.Total,
+	})
+}
+
+// GetSharedGame godoc
+//
+//	@Summary	Get an unlisted or public game
+//	@Tags		Game
+//	@Produce	json
+//	@Param		gameId	path	string	true	"Game id"
+//	@Success	200
+//	@Failure	400	{string}	string
+//	@Failure	404	{string}	string
+//	@Router		/game/public/{gameId} [get]
+func (h *gameHandler) GetSharedGame(c *fiber.Ctx) error {
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	game, err := h.gameService.GetSharedGame(c.Context(), gameId)
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"game": game,
 	})
 }
 
+// CopyGame godoc
+//
+//	@Summary	Copy a Game into a new one owned by the caller
+//	@Tags		Game
+//	@Produce	json
+//	@Param		gameId	path	string	true	"Game id"
+//	@Success	201
+//	@Failure	400	{string}	string
+//	@Failure	401	{string}	string
+//	@Failure	403	{string}	string
+//	@Failure	404	{string}	string
+//	@Router		/game/{gameId}/copy [post]
+func (h *gameHandler) CopyGame(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	fork, err := h.gameService.CopyGame(c.Context(), userId, gameId)
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"game": fork,
+	})
+}
+
+// PublishGame godoc
+//
+//	@Summary		Publish the current version of a Game
+//	@Description	Validates the game and freezes it as the version sessions play
+//	@Tags			Game
+//	@Produce		json
+//	@Param			gameId	path	string	true	"Game id"
+//	@Success		200
+//	@Failure		400	{string}	string
+//	@Failure		401	{string}	string
+//	@Failure		403	{string}	string
+//	@Failure		404	{string}	string
+//	@Failure		422	{object}	ValidationErrorResponse
+//	@Router			/game/{gameId}/publish [post]
+func (h *gameHandler) PublishGame(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	published, err := h.gameService.PublishGame(c.Context(), userId, gameId)
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"game": published,
+	})
+}
+
+// DeleteGame godoc
+//
+//	@Summary	Delete a Game
+//	@Tags		Game
+//	@Param		gameId	path	string	true	"Game id"
+//	@Success	204
+//	@Failure	400	{string}	string
+//	@Failure	401	{string}	string
+//	@Failure	403	{string}	string
+//	@Failure	404	{string}	string
+//	@Router		/game/{gameId} [delete]
+func (h *gameHandler) DeleteGame(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	err = h.gameService.DeleteGame(c.Context(), userId, gameId)
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
 // CreateGame godoc
 //
 //	@Summary	Create a Game
@@ -225,19 +895,28 @@ func (h *gameHandler) CreateGame(c *fiber.Ctx) error {
 		return err
 	}
 
-	questions, err := h.parseQuestions(req.Questions)
+	questions, err := parseQuestions(h.validationService, req.Questions, !req.Draft)
 	if err != nil {
-		if errors.Is(err, ErrUnknownQuestionKind) {
+		if errors.Is(err, game.ErrUnknownQuestionKind) {
 			return c.SendStatus(fiber.StatusBadRequest)
 		}
 		return err
 	}
 
-	err = h.gameService.CreateNewGame(c.Context(), userId, &game.Game{
+	newGame := &game.Game{
 		Title:       req.Title,
 		Description: req.Description,
 		Questions:   questions,
-	})
+		Scoring:     req.Scoring.ToScoringPolicy(),
+		Visibility:  game.Visibility(req.Visibility),
+		Metadata:    req.ToMetadata(),
+	}
+
+	if req.Draft {
+		err = h.gameService.CreateDraftGame(c.Context(), userId, newGame)
+	} else {
+		err = h.gameService.CreateNewGame(c.Context(), userId, newGame)
+	}
 	if err != nil {
 		return err
 	}
@@ -245,34 +924,184 @@ func (h *gameHandler) CreateGame(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusCreated)
 }
 
-func (h *gameHandler) parseQuestions(qs []CreateQuestionRequest) ([]game.Question, error) {
-	questions := make([]game.Question, 0)
+// UpdateGame godoc
+//
+//	@Summary	Replace a Game
+//	@Tags		Game
+//	@Accept		json
+//	@Produce	json
+//	@Param		gameId	path	string				true	"Game id"
+//	@Param		req		body	CreateGameRequest	true	"Update Game Request"
+//	@Success	200
+//	@Failure	400	{string}	string
+//	@Failure	401	{string}	string
+//	@Failure	403	{string}	string
+//	@Failure	404	{string}	string
+//	@Failure	422	{object}	ValidationErrorResponse
+//	@Router		/game/{gameId} [put]
+func (h *gameHandler) UpdateGame(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
 
-	for _, q := range qs {
-		var candidate game.Question
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
 
-		var temp Question
+	req := new(CreateGameRequest)
+	err = c.BodyParser(req)
+	if err != nil {
+		return err
+	}
+
+	err = h.validationService.Validate(req)
+	if err != nil {
+		return err
+	}
 
-		switch q.Kind {
-		case QuizQuestionKind:
-			temp = new(CreateQuizQuestionRequest)
-		case TrueFalseQuestionKind:
-			temp = new(CreateTrueFalseQuestionRequest)
-		default:
-			return nil, ErrUnknownQuestionKind
+	questions, err := parseQuestions(h.validationService, req.Questions, false)
+	if err != nil {
+		if errors.Is(err, game.ErrUnknownQuestionKind) {
+			return c.SendStatus(fiber.StatusBadRequest)
 		}
+		return err
+	}
 
-		err := temp.LoadFromMap(q.Data)
+	updated, err := h.gameService.UpdateGame(c.Context(), userId, gameId, &game.Game{
+		Title:       req.Title,
+		Description: req.Description,
+		Questions:   questions,
+		Scoring:     req.Scoring.ToScoringPolicy(),
+		Visibility:  game.Visibility(req.Visibility),
+		Metadata:    req.ToMetadata(),
+	})
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"game": updated,
+	})
+}
+
+// PatchGame godoc
+//
+//	@Summary	Change part of a Game
+//	@Tags		Game
+//	@Accept		json
+//	@Produce	json
+//	@Param		gameId	path	string				true	"Game id"
+//	@Param		req		body	PatchGameRequest	true	"Patch Game Request"
+//	@Success	200
+//	@Failure	400	{string}	string
+//	@Failure	401	{string}	string
+//	@Failure	403	{string}	string
+//	@Failure	404	{string}	string
+//	@Failure	422	{object}	ValidationErrorResponse
+//	@Router		/game/{gameId} [patch]
+func (h *gameHandler) PatchGame(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	req := new(PatchGameRequest)
+	err = c.BodyParser(req)
+	if err != nil {
+		return err
+	}
+
+	err = h.validationService.Validate(req)
+	if err != nil {
+		return err
+	}
+
+	var visibility *game.Visibility
+	if req.Visibility != nil {
+		v := game.Visibility(*req.Visibility)
+		visibility = &v
+	}
+	var difficulty *game.Difficulty
+	if req.Difficulty != nil {
+		d := game.Difficulty(*req.Difficulty)
+		difficulty = &d
+	}
+	patch := &services.PatchGameRequest{
+		UpdateGameInfoRequest: services.UpdateGameInfoRequest{
+			Title:       req.Title,
+			Description: req.Description,
+			Scoring:     req.Scoring.ToScoringPolicy(),
+			Visibility:  visibility,
+			Tags:        req.Tags,
+			Category:    req.Category,
+			Difficulty:  difficulty,
+		},
+	}
+
+	if req.Questions != nil {
+		questions, err := parseQuestions(h.validationService, req.Questions, false)
 		if err != nil {
-			return nil, ErrUnknownQuestionKind
+			if errors.Is(err, game.ErrUnknownQuestionKind) {
+				return c.SendStatus(fiber.StatusBadRequest)
+			}
+			return err
 		}
-		err = h.validationService.Validate(temp)
+		patch.Questions = &questions
+	}
+
+	updated, err := h.gameService.PatchGame(c.Context(), userId, gameId, patch)
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"game": updated,
+	})
+}
+
+func (h *gameHandler) handleGameError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, ports.ErrGameNotFound) || errors.Is(err, ports.ErrRevisionNotFound) {
+		return c.Status(fiber.StatusNotFound).SendString(err.Error())
+	}
+	return err
+}
+
+// parseQuestions turns the requests into questions of their kind. Questions of
+// drafts aren't validated here, the service checks the rules a draft must meet
+// and every other one when it gets published.
+func parseQuestions(
+	validationService *services.ValidationService,
+	qs []CreateQuestionRequest,
+	validate bool,
+) ([]game.Question, error) {
+	questions := make([]game.Question, 0)
+
+	for _, q := range qs {
+		spec, err := game.LookupKind(q.Kind)
 		if err != nil {
 			return nil, err
 		}
-		candidate = temp.ToQuestion()
 
-		questions = append(questions, candidate)
+		var temp Question
+		if newRequest, ok := questionRequests[spec.Kind]; ok {
+			temp = newRequest()
+		} else {
+			temp = newDocumentQuestionRequest(spec)
+		}
+
+		err = temp.LoadFromMap(q.Data)
+		if err != nil {
+			return nil, game.ErrUnknownQuestionKind
+		}
+		if validate {
+			err = validationService.Validate(temp)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		questions = append(questions, temp.ToQuestion())
 	}
 
 	return questions, nil
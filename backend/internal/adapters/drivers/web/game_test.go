@@ -1,23 +1,31 @@
 package web_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"fmt"
+	"html"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gavv/httpexpect/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	testcontainers "github.com/testcontainers/testcontainers-go/modules/postgres"
 
 	"github.com/taldoflemis/brain.test/internal/adapters/driven/auth"
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/media"
 	"github.com/taldoflemis/brain.test/internal/adapters/driven/postgres"
 	"github.com/taldoflemis/brain.test/internal/adapters/drivers/web"
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
 	"github.com/taldoflemis/brain.test/internal/core/services"
 	"github.com/taldoflemis/brain.test/internal/ports"
 	testshelpers "github.com/taldoflemis/brain.test/test/helpers"
@@ -72,12 +80,31 @@ func (suite *GameHandlerTestSuite) SetupSuite() {
 	})
 
 	gameStorer := postgres.NewPostgresGameStorer(pool)
+	revisionStorer := postgres.NewPostgresRevisionStorer(pool)
 	validationService := services.NewValidationService()
-	gameService := services.NewGameService(logger, validationService, gameStorer)
+	mediaStorer, err := media.NewLocalMediaStorer(suite.T().TempDir())
+	if err != nil {
+		log.Fatal(err)
+	}
+	gameService := services.NewGameService(
+		logger,
+		validationService,
+		gameStorer,
+		revisionStorer,
+		mediaStorer,
+	)
 
 	jwtMiddleware, idp := newJWTMiddleware(logger, pool)
 	gameHandler := web.NewGameHandler(jwtMiddleware, validationService, gameService)
 	gameHandler.RegisterRoutes(app)
+	bankService := services.NewBankService(
+		logger,
+		validationService,
+		postgres.NewPostgresBankStorer(pool),
+		gameService,
+	)
+	bankHandler := web.NewBankHandler(jwtMiddleware, validationService, bankService)
+	bankHandler.RegisterRoutes(app)
 
 	suite.app = app
 	suite.pgContainer = pgContainer
@@ -99,7 +126,7 @@ func (suite *GameHandlerTestSuite) SetupSuite() {
 }
 
 func (suite *GameHandlerTestSuite) TearDownTest() {
-	_, err := suite.pool.Exec(suite.ctx, "TRUNCATE TABLE games CASCADE")
+	_, err := suite.pool.Exec(suite.ctx, "TRUNCATE TABLE games, bank_questions CASCADE")
 	if err != nil {
 		log.Fatalf("error truncating games table: %s", err)
 	}
@@ -289,3 +316,760 @@ func (s *GameHandlerTestSuite) TestCreateGameWithInvalidRequest() {
 		})
 	}
 }
+
+func (s *GameHandlerTestSuite) createMockedGame(ownerId string) *game.Game {
+	mockedGame := &game.Game{
+		Title:       "title",
+		Description: "description",
+		Questions: []game.Question{
+			&game.TrueFalseQuestion{
+				Title:            "title true false",
+				Points:           1,
+				TimeLimit:        30,
+				TrueAlternative:  "true here",
+				FalseAlternative: "false here",
+			},
+		},
+	}
+
+	err := s.svc.CreateNewGame(s.ctx, ownerId, mockedGame)
+	if err != nil {
+		log.Fatalf("error creating game: %s", err)
+	}
+
+	return mockedGame
+}
+
+func (s *GameHandlerTestSuite) TestUpdateGame() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	mockedGame := s.createMockedGame(testUserId)
+
+	req := map[string]any{
+		"title":       "new title",
+		"description": "new description",
+		"questions": []map[string]any{
+			{
+				"kind": "true_false",
+				"data": map[string]any{
+					"title":             "new title true false",
+					"points":            2,
+					"time_limit":        20,
+					"true_alternative":  "true here",
+					"false_alternative": "false here",
+				},
+			},
+		},
+	}
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	resp := e.PUT(route + mockedGame.Id.String()).WithHeaders(headers).WithJSON(req).Expect()
+
+	// Assert
+	resp.Status(http.StatusOK)
+	resp.JSON().Object().Value("game").Object().Value("title").IsEqual("new title")
+}
+
+func (s *GameHandlerTestSuite) TestPatchGame() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	mockedGame := s.createMockedGame(testUserId)
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	resp := e.PATCH(route + mockedGame.Id.String()).
+		WithHeaders(headers).
+		WithJSON(map[string]any{"title": "patched title"}).
+		Expect()
+
+	// Assert
+	resp.Status(http.StatusOK)
+	obj := resp.JSON().Object().Value("game").Object()
+	obj.Value("title").IsEqual("patched title")
+	obj.Value("description").IsEqual(mockedGame.Description)
+}
+
+func (s *GameHandlerTestSuite) TestPatchGameWithBadInput() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	someoneElsesGame := s.createMockedGame(uuid.NewString())
+
+	table := []struct {
+		desc   string
+		gameId string
+		status int
+	}{
+		{
+			desc:   "game owned by someone else",
+			gameId: someoneElsesGame.Id.String(),
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "unknown game",
+			gameId: uuid.NewString(),
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "invalid game id",
+			gameId: "tubias",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			resp := e.PATCH(route + tt.gameId).
+				WithHeaders(headers).
+				WithJSON(map[string]any{"title": "patched title"}).
+				Expect()
+
+			// Assert
+			resp.Status(tt.status)
+		})
+	}
+}
+
+func (s *GameHandlerTestSuite) TestGetGameByIdOfAnotherUser() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	someoneElsesGame := s.createMockedGame(uuid.NewString())
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	resp := e.GET(route + someoneElsesGame.Id.String()).WithHeaders(headers).Expect()
+
+	// Assert
+	resp.Status(http.StatusForbidden)
+}
+
+func (s *GameHandlerTestSuite) TestDeleteGame() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	mockedGame := s.createMockedGame(testUserId)
+	someoneElsesGame := s.createMockedGame(uuid.NewString())
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	forbidden := e.DELETE(route + someoneElsesGame.Id.String()).WithHeaders(headers).Expect()
+	deleted := e.DELETE(route + mockedGame.Id.String()).WithHeaders(headers).Expect()
+	missing := e.GET(route + mockedGame.Id.String()).WithHeaders(headers).Expect()
+
+	// Assert
+	forbidden.Status(http.StatusForbidden)
+	deleted.Status(http.StatusNoContent)
+	missing.Status(http.StatusNotFound)
+}
+
+func (s *GameHandlerTestSuite) TestBrowsePublicGames() {
+	// Arrange
+	t := s.T()
+	public := &game.Game{
+		Title:       "public title",
+		Description: "description",
+		Visibility:  game.PublicVisibility,
+		Questions: []game.Question{
+			&game.TrueFalseQuestion{
+				Title:            "title true false",
+				Points:           1,
+				TimeLimit:        30,
+				TrueAlternative:  "true here",
+				FalseAlternative: "false here",
+			},
+		},
+	}
+	err := s.svc.CreateNewGame(s.ctx, testUserId, public)
+	assert.NoError(t, err)
+	private := s.createMockedGame(testUserId)
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	resp := e.GET(route+"public").WithQuery("search", "public").Expect()
+	shared := e.GET(route + "public/" + public.Id.String()).Expect()
+	hidden := e.GET(route + "public/" + private.Id.String()).Expect()
+
+	// Assert
+	resp.Status(http.StatusOK)
+	obj := resp.JSON().Object()
+	obj.Value("total").IsEqual(1)
+	obj.Value("page").IsEqual(1)
+	games := obj.Value("games").Array()
+	games.Length().IsEqual(1)
+	games.Value(0).Object().Value("id").IsEqual(public.Id.String())
+	games.Value(0).Object().Value("question_count").IsEqual(1)
+	shared.Status(http.StatusOK)
+	hidden.Status(http.StatusNotFound)
+}
+
+func (s *GameHandlerTestSuite) TestGameMetadata() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	s.createMockedGame(testUserId)
+
+	req := map[string]any{
+		"title":       "plants",
+		"description": "description",
+		"tags":        []string{"Photosynthesis", "plants"},
+		"category":    "Biology",
+		"difficulty":  "easy",
+		"visibility":  "public",
+		"questions": []map[string]any{
+			{
+				"kind": "true_false",
+				"data": map[string]any{
+					"title":             "title true false",
+					"points":            1,
+					"time_limit":        30,
+					"true_alternative":  "true here",
+					"false_alternative": "false here",
+					"tags":              []string{"chlorophyll"},
+					"difficulty":        "hard",
+				},
+			},
+		},
+	}
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	created := e.POST(route).WithHeaders(headers).WithJSON(req).Expect()
+	filtered := e.GET(route).WithHeaders(headers).
+		WithQuery("tag", "photosynthesis").
+		WithQuery("category", "biology").
+		Expect()
+	public := e.GET(route+"public").WithQuery("difficulty", "easy").Expect()
+	tags := e.GET(route+"tags").WithHeaders(headers).WithQuery("prefix", "P").Expect()
+	bad := e.GET(route).WithHeaders(headers).WithQuery("difficulty", "impossible").Expect()
+
+	// Assert
+	created.Status(http.StatusCreated)
+	obj := created.JSON().Object().Value("game").Object()
+	obj.Value("tags").Array().ConsistsOf("photosynthesis", "plants")
+	obj.Value("category").IsEqual("biology")
+	question := obj.Value("questions").Array().Value(0).Object()
+	question.Value("tags").Array().ConsistsOf("chlorophyll")
+	question.Value("difficulty").IsEqual("hard")
+
+	filtered.Status(http.StatusOK)
+	games := filtered.JSON().Object().Value("games").Array()
+	games.Length().IsEqual(1)
+	games.Value(0).Object().Value("title").IsEqual("plants")
+
+	public.Status(http.StatusOK)
+	public.JSON().Object().Value("games").Array().Value(0).Object().Value("difficulty").IsEqual("easy")
+
+	tags.Status(http.StatusOK)
+	tags.JSON().Object().Value("tags").Array().ConsistsOf("photosynthesis", "plants")
+
+	bad.Status(http.StatusUnprocessableEntity)
+}
+
+func (s *GameHandlerTestSuite) TestGetGamesPages() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	older := s.createMockedGame(testUserId)
+	newer := s.createMockedGame(testUserId)
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	first := e.GET(route).WithHeaders(headers).
+		WithQuery("sort", "created").
+		WithQuery("page_size", 1).
+		Expect()
+	next := first.JSON().Object().Value("next").String().Raw()
+	second := e.GET(route).WithHeaders(headers).
+		WithQuery("sort", "created").
+		WithQuery("page_size", 1).
+		WithQuery("after", next).
+		Expect()
+	both := e.GET(route).WithHeaders(headers).
+		WithQuery("after", next).
+		WithQuery("before", next).
+		Expect()
+
+	// Assert
+	first.Status(http.StatusOK)
+	obj := first.JSON().Object()
+	obj.Value("total").IsEqual(2)
+	obj.Value("prev").IsEqual("")
+	obj.Value("games").Array().Value(0).Object().Value("id").IsEqual(newer.Id.String())
+
+	second.Status(http.StatusOK)
+	obj = second.JSON().Object()
+	obj.Value("next").IsEqual("")
+	obj.Value("prev").String().NotEmpty()
+	obj.Value("games").Array().Value(0).Object().Value("id").IsEqual(older.Id.String())
+
+	both.Status(http.StatusUnprocessableEntity)
+}
+
+func (s *GameHandlerTestSuite) TestCopyGame() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	mockedGame := s.createMockedGame(testUserId)
+	someoneElsesGame := s.createMockedGame(uuid.NewString())
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	resp := e.POST(route + mockedGame.Id.String() + "/copy").WithHeaders(headers).Expect()
+	forbidden := e.POST(route + someoneElsesGame.Id.String() + "/copy").
+		WithHeaders(headers).
+		Expect()
+
+	// Assert
+	resp.Status(http.StatusCreated)
+	obj := resp.JSON().Object().Value("game").Object()
+	obj.Value("id").NotEqual(mockedGame.Id.String())
+	obj.Value("forked_from").IsEqual(mockedGame.Id.String())
+	forbidden.Status(http.StatusForbidden)
+}
+
+func (s *GameHandlerTestSuite) TestCreateDraftAndPublish() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	created := e.POST(route).
+		WithHeaders(headers).
+		WithJSON(map[string]any{"title": "draft title", "draft": true}).
+		Expect()
+	games := e.GET(route).WithHeaders(headers).Expect().JSON().Object().Value("games").Array()
+	draftId := games.Value(0).Object().Value("id").String().Raw()
+	incomplete := e.POST(route + draftId + "/publish").WithHeaders(headers).Expect()
+	e.PATCH(route + draftId).
+		WithHeaders(headers).
+		WithJSON(map[string]any{
+			"description": "description",
+			"questions": []map[string]any{
+				{
+					"kind": "true_false",
+					"data": map[string]any{
+						"title":             "title true false",
+						"points":            1,
+						"time_limit":        30,
+						"true_alternative":  "true here",
+						"false_alternative": "false here",
+					},
+				},
+			},
+		}).
+		Expect().
+		Status(http.StatusOK)
+	published := e.POST(route + draftId + "/publish").WithHeaders(headers).Expect()
+
+	// Assert
+	created.Status(http.StatusCreated)
+	games.Length().IsEqual(1)
+	incomplete.Status(http.StatusUnprocessableEntity)
+	published.Status(http.StatusOK)
+	published.JSON().Object().Value("game").Object().Value("status").IsEqual("published")
+}
+
+func (s *GameHandlerTestSuite) TestRevisions() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	mockedGame := s.createMockedGame(testUserId)
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+	revisionsRoute := route + mockedGame.Id.String() + "/revisions/"
+
+	// Act
+	e.PATCH(route + mockedGame.Id.String()).
+		WithHeaders(headers).
+		WithJSON(map[string]any{"title": "broken title"}).
+		Expect().
+		Status(http.StatusOK)
+	revisions := e.GET(revisionsRoute).WithHeaders(headers).Expect()
+	first := e.GET(revisionsRoute + "1").WithHeaders(headers).Expect()
+	missing := e.GET(revisionsRoute + "42").WithHeaders(headers).Expect()
+	restored := e.POST(revisionsRoute + "1/restore").WithHeaders(headers).Expect()
+
+	// Assert
+	revisions.Status(http.StatusOK)
+	revisions.JSON().Object().Value("revisions").Array().Length().IsEqual(2)
+	first.Status(http.StatusOK)
+	first.JSON().Object().Value("game").Object().Value("title").IsEqual(mockedGame.Title)
+	missing.Status(http.StatusNotFound)
+	restored.Status(http.StatusOK)
+	restored.JSON().Object().Value("game").Object().Value("title").IsEqual(mockedGame.Title)
+}
+
+func (s *GameHandlerTestSuite) TestExportAndImportGame() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	mockedGame := s.createMockedGame(testUserId)
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	exported := e.GET(route + mockedGame.Id.String() + "/export").
+		WithHeaders(headers).
+		Expect()
+	exportedYAML := e.GET(route+mockedGame.Id.String()+"/export").
+		WithHeaders(headers).
+		WithQuery("format", "yaml").
+		Expect()
+
+	imported := e.POST(route+"import").
+		WithHeaders(headers).
+		WithBytes([]byte(exported.Body().Raw())).
+		WithHeader("Content-Type", "application/json").
+		Expect()
+	importedYAML := e.POST(route+"import").
+		WithHeaders(headers).
+		WithBytes([]byte(exportedYAML.Body().Raw())).
+		WithHeader("Content-Type", "application/yaml").
+		Expect()
+	badVersion := e.POST(route + "import").
+		WithHeaders(headers).
+		WithJSON(map[string]any{
+			"format":    "brain.test/game",
+			"version":   2,
+			"game":      map[string]any{"title": "title"},
+			"questions": []any{},
+		}).
+		Expect()
+
+	// Assert
+	exported.Status(http.StatusOK)
+	exported.Header("Content-Disposition").Contains("attachment")
+	bundle := exported.JSON().Object()
+	bundle.Value("format").IsEqual("brain.test/game")
+	bundle.Value("version").IsEqual(1)
+	bundle.Value("questions").Array().Value(0).Object().Value("kind").IsEqual("true_false")
+
+	exportedYAML.Status(http.StatusOK)
+	exportedYAML.Header("Content-Type").IsEqual("application/yaml")
+
+	for _, resp := range []*httpexpect.Response{imported, importedYAML} {
+		resp.Status(http.StatusCreated)
+		obj := resp.JSON().Object().Value("game").Object()
+		obj.Value("id").NotEqual(mockedGame.Id.String())
+		obj.Value("title").IsEqual(mockedGame.Title)
+		obj.Value("questions").Array().Length().IsEqual(1)
+	}
+
+	badVersion.Status(http.StatusUnprocessableEntity)
+}
+
+// newXLSX writes a workbook with a single worksheet holding the rows, its
+// strings shared as spreadsheets do.
+func newXLSX(t *testing.T, rows [][]string) []byte {
+	var sharedStrings, sheetData strings.Builder
+	count := 0
+	for i, row := range rows {
+		fmt.Fprintf(&sheetData, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			fmt.Fprintf(&sheetData, `<c r="%c%d" t="s"><v>%d</v></c>`, 'A'+j, i+1, count)
+			fmt.Fprintf(&sharedStrings, `<si><t>%s</t></si>`, html.EscapeString(cell))
+			count++
+		}
+		sheetData.WriteString(`</row>`)
+	}
+
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+			`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Quiz" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			sharedStrings.String() + `</sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<sheetData>` + sheetData.String() + `</sheetData></worksheet>`,
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func (s *GameHandlerTestSuite) TestImportSpreadsheet() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+
+	rows := [][]string{
+		{"question", "kind", "time_limit", "points", "alternative_1", "alternative_2", "alternative_3", "correct"},
+		{"2 + 2?", "quiz", "30", "1", "3", "4", "5", "2"},
+		{"The sky is blue", "true_false", "10", "1", "", "", "", "true"},
+	}
+	var csv strings.Builder
+	for _, row := range rows {
+		csv.WriteString(strings.Join(row, ",") + "\n")
+	}
+	invalidCSV := "question,time_limit,points\n2 + 2?,2,1\n"
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+	upload := func(name string, content []byte, dryRun bool) *httpexpect.Response {
+		return e.POST(route+"import/spreadsheet").
+			WithHeaders(headers).
+			WithQuery("dry_run", dryRun).
+			WithMultipart().
+			WithFileBytes("file", name, content).
+			WithFormField("title", "imported").
+			Expect()
+	}
+
+	// Act
+	fromCSV := upload("quiz.csv", []byte(csv.String()), false)
+	fromXLSX := upload("quiz.xlsx", newXLSX(t, rows), false)
+	dryRun := upload("quiz.csv", []byte(csv.String()), true)
+	invalid := upload("quiz.csv", []byte(invalidCSV), false)
+
+	// Assert
+	for _, resp := range []*httpexpect.Response{fromCSV, fromXLSX} {
+		resp.Status(http.StatusCreated)
+		obj := resp.JSON().Object()
+		obj.Value("questions").IsEqual(2)
+		obj.Value("errors").Array().IsEmpty()
+		obj.Value("game").Object().Value("title").IsEqual("imported")
+	}
+
+	dryRun.Status(http.StatusOK)
+	dryRun.JSON().Object().NotContainsKey("game")
+
+	invalid.Status(http.StatusUnprocessableEntity)
+	errs := invalid.JSON().Object().Value("errors").Array()
+	errs.Value(0).Object().Value("cell").IsEqual("B2")
+	errs.Value(1).Object().Value("column").IsEqual("alternative_1")
+
+	page, err := s.svc.GetGamesByUserId(s.ctx, testUserId, &services.GetGamesRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+}
+
+func (s *GameHandlerTestSuite) TestExportAndImportQuestions() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	mockedGame := s.createMockedGame(testUserId)
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+	upload := func(format string, content []byte) *httpexpect.Response {
+		return e.POST(route+"import/"+format).
+			WithHeaders(headers).
+			WithMultipart().
+			WithFileBytes("file", "bank.txt", content).
+			WithFormField("title", "imported").
+			Expect()
+	}
+
+	// Act
+	exported := e.GET(route+mockedGame.Id.String()+"/export").
+		WithHeaders(headers).
+		WithQuery("format", "gift").
+		Expect()
+	imported := upload("gift", []byte(exported.Body().Raw()))
+	withWarnings := upload("gift", []byte("Is it? {T}\n\nWrite an essay {}\n"))
+	unknown := upload("qti", []byte("<assessmentItem/>"))
+
+	// Assert
+	exported.Status(http.StatusOK)
+	exported.Header("Content-Type").HasPrefix("text/plain")
+	exported.Header("Content-Disposition").Contains(".gift.txt")
+
+	imported.Status(http.StatusCreated)
+	obj := imported.JSON().Object()
+	obj.Value("warnings").Array().IsEmpty()
+	game := obj.Value("game").Object()
+	game.Value("title").IsEqual("imported")
+	game.Value("questions").Array().Length().IsEqual(len(mockedGame.Questions))
+
+	withWarnings.Status(http.StatusCreated)
+	withWarnings.JSON().Object().Value("warnings").Array().Value(0).String().HasPrefix("question 2: essay")
+
+	unknown.Status(http.StatusBadRequest)
+}
+
+func (s *GameHandlerTestSuite) TestQuestionBank() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	mockedGame := s.createMockedGame(testUserId)
+	trueFalse := func(title string, tags ...string) map[string]any {
+		return map[string]any{
+			"kind": "true_false",
+			"data": map[string]any{
+				"title":             title,
+				"points":            1,
+				"time_limit":        30,
+				"true_alternative":  "yes",
+				"false_alternative": "no",
+			},
+			"tags": tags,
+		}
+	}
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	saved := e.POST("/bank/").
+		WithHeaders(headers).
+		WithJSON(trueFalse("Plants need light", "Photosynthesis")).
+		Expect()
+	e.POST("/bank/").
+		WithHeaders(headers).
+		WithJSON(trueFalse("Plants need water", "photosynthesis")).
+		Expect().
+		Status(http.StatusCreated)
+	incomplete := e.POST("/bank/").
+		WithHeaders(headers).
+		WithJSON(map[string]any{"kind": "true_false", "data": map[string]any{"title": "no alternatives"}}).
+		Expect()
+	fromGame := e.POST("/bank/from-game/" + mockedGame.Id.String()).
+		WithHeaders(headers).
+		WithJSON(map[string]any{"tags": []string{"misc"}}).
+		Expect()
+	page := e.GET("/bank/").
+		WithHeaders(headers).
+		WithQuery("tag", "photosynthesis").
+		WithQuery("page_size", 1).
+		Expect()
+	created := e.POST("/bank/game").
+		WithHeaders(headers).
+		WithJSON(map[string]any{
+			"title":     "photosynthesis",
+			"selection": map[string]any{"tags": []string{"photosynthesis"}, "count": 10},
+		}).
+		Expect()
+	questionId := saved.JSON().Object().Value("id").String().Raw()
+	added := e.POST("/bank/game/" + mockedGame.Id.String()).
+		WithHeaders(headers).
+		WithJSON(map[string]any{"question_ids": []string{questionId}}).
+		Expect()
+	deleted := e.DELETE("/bank/" + questionId).WithHeaders(headers).Expect()
+	missing := e.GET("/bank/" + questionId).WithHeaders(headers).Expect()
+
+	// Assert
+	saved.Status(http.StatusCreated)
+	saved.JSON().Object().Value("tags").IsEqual([]string{"photosynthesis"})
+	incomplete.Status(http.StatusUnprocessableEntity)
+	fromGame.Status(http.StatusCreated)
+	fromGame.JSON().Array().Length().IsEqual(1)
+	page.Status(http.StatusOK)
+	page.JSON().Object().Value("questions").Array().Length().IsEqual(1)
+	page.JSON().Object().Value("total").IsEqual(2)
+	created.Status(http.StatusCreated)
+	created.JSON().Object().Value("game").Object().Value("questions").Array().Length().IsEqual(2)
+	added.Status(http.StatusOK)
+	added.JSON().Object().Value("game").Object().Value("questions").Array().Length().IsEqual(2)
+	deleted.Status(http.StatusNoContent)
+	missing.Status(http.StatusNotFound)
+}
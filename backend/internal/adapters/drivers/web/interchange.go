@@ -0,0 +1,115 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/taldoflemis/brain.test/internal/adapters/drivers/interchange"
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+// QuestionsImportResponse
+//
+//	@Description	A Game created from questions written in another tool's format
+type QuestionsImportResponse struct {
+	// the created game
+	Game any `json:"game" swaggertype:"object"`
+	// what couldn't be converted, e.g. "question 3: feedback can't be represented, it was left out"
+	Warnings []string `json:"warnings"`
+}
+
+// ImportQuestions godoc
+//
+//	@Summary		Import a Game from GIFT, Aiken or Moodle XML
+//	@Description	Creates a Game from the multiple choice, true or false and short answer questions of a question bank. Whatever the format has that can't be represented is reported in warnings. Imported questions are worth 1 point and last 30 seconds unless the format says otherwise.
+//	@Tags			Game
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			format		path		string	true	"gift, aiken or moodle_xml"
+//	@Param			file		formData	file	true	"Question bank"
+//	@Param			title		formData	string	true	"Title of the game"
+//	@Param			description	formData	string	false	"Description of the game"
+//	@Param			draft		formData	bool	false	"Create the game as a draft, to be completed before publishing"
+//	@Success		201			{object}	QuestionsImportResponse
+//	@Failure		400			{string}	string
+//	@Failure		401			{string}	string
+//	@Failure		422			{object}	ValidationErrorResponse
+//	@Router			/game/import/{format} [post]
+func (h *gameHandler) ImportQuestions(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+	format := interchange.Format(c.Params("format"))
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	questions, warnings, err := interchange.Import(format, content)
+	if err != nil {
+		if errors.Is(err, interchange.ErrUnknownFormat) || errors.Is(err, interchange.ErrInvalidFile) {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+		return err
+	}
+
+	newGame := &game.Game{
+		Title:       c.FormValue("title"),
+		Description: c.FormValue("description"),
+		Questions:   questions,
+	}
+
+	if c.FormValue("draft") == "true" {
+		err = h.gameService.CreateDraftGame(c.Context(), userId, newGame)
+	} else {
+		err = h.gameService.CreateNewGame(c.Context(), userId, newGame)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp := QuestionsImportResponse{
+		Game:     newGame,
+		Warnings: make([]string, len(warnings)),
+	}
+	for i, w := range warnings {
+		resp.Warnings[i] = w.String()
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// sendQuestions writes the questions of a game in one of the formats of
+// other tools, reporting what couldn't be converted in Warning headers.
+func sendQuestions(c *fiber.Ctx, format interchange.Format, g *game.Game) error {
+	body, warnings, err := interchange.Export(format, g.Questions)
+	if err != nil {
+		return err
+	}
+
+	extension, _ := interchange.Extension(format)
+	contentType, _ := interchange.ContentType(format)
+
+	for _, w := range warnings {
+		c.Append(fiber.HeaderWarning, fmt.Sprintf(`299 - "%s"`, strings.ReplaceAll(w.String(), `"`, `\"`)))
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, g.Id, extension))
+
+	return c.Status(fiber.StatusOK).Send(body)
+}
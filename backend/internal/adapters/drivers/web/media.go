@@ -0,0 +1,129 @@
+package web
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/services"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+const (
+	mediaPath = "/media"
+	// maxUploadSize lets the biggest media be uploaded, along the rest of the
+	// multipart form
+	maxUploadSize = int(services.MaxVideoSize) + 1<<20
+)
+
+// MediaResponse
+//
+//	@Description	An uploaded image, audio or video questions may reference
+type MediaResponse struct {
+	// the id to reference the media by
+	Id string `json:"id"`
+	// image, audio or video
+	Kind string `json:"kind"`
+	// the content type detected from the file
+	ContentType string `json:"content_type"`
+	// size of the file in bytes
+	Size int64 `json:"size"`
+}
+
+type mediaHandler struct {
+	jwtMiddleware fiber.Handler
+	mediaService  *services.MediaService
+}
+
+func NewMediaHandler(
+	jwtMiddleware fiber.Handler,
+	mediaService *services.MediaService,
+) *mediaHandler {
+	return &mediaHandler{
+		jwtMiddleware: jwtMiddleware,
+		mediaService:  mediaService,
+	}
+}
+
+func (h *mediaHandler) RegisterRoutes(router fiber.Router) {
+	mediaApi := router.Group(mediaPath)
+
+	mediaApi.Get("/:mediaId", h.GetMedia)
+
+	mediaApi.Use(h.jwtMiddleware)
+	mediaApi.Post("/", h.UploadMedia)
+}
+
+// UploadMedia godoc
+//
+//	@Summary	Upload an image, audio or video to show along questions
+//	@Tags		Media
+//	@Accept		multipart/form-data
+//	@Produce	json
+//	@Param		file	formData	file	true	"PNG, JPEG, GIF or WebP image up to 5MB, MP3 or WAV audio up to 10MB, MP4 or WebM video up to 50MB"
+//	@Success	201		{object}	MediaResponse
+//	@Failure	400		{string}	string
+//	@Failure	401		{string}	string
+//	@Failure	422		{object}	ValidationErrorResponse
+//	@Router		/media/ [post]
+func (h *mediaHandler) UploadMedia(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	if streamTooLarge(c, maxUploadSize) {
+		return c.Status(fiber.StatusRequestEntityTooLarge).SendString(fiber.ErrRequestEntityTooLarge.Message)
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	content, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	file, err := h.mediaService.UploadMedia(c.Context(), userId, header.Size, content)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(MediaResponse{
+		Id:          file.Id.String(),
+		Kind:        string(file.Kind),
+		ContentType: file.ContentType,
+		Size:        file.Size,
+	})
+}
+
+// GetMedia godoc
+//
+//	@Summary	Get the content of an uploaded media
+//	@Tags		Media
+//	@Param		mediaId	path	string	true	"Media id"
+//	@Success	200
+//	@Failure	400	{string}	string
+//	@Failure	404	{string}	string
+//	@Router		/media/{mediaId} [get]
+func (h *mediaHandler) GetMedia(c *fiber.Ctx) error {
+	mediaId, err := uuid.Parse(c.Params("mediaId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	file, content, err := h.mediaService.OpenMedia(c.Context(), mediaId)
+	if errors.Is(err, ports.ErrMediaNotFound) {
+		return c.Status(fiber.StatusNotFound).SendString(err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	// Media are never changed once uploaded, only deleted
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+
+	return c.Status(fiber.StatusOK).SendStream(content, int(file.Size))
+}
@@ -1,7 +1,10 @@
 package web
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
@@ -14,6 +17,10 @@ import (
 func NewJWTMiddleware(authManager ports.AuthenticationManager) fiber.Handler {
 	return jwtware.New(jwtware.Config{
 		KeyFunc: customKeyFunc(authManager),
+		// browsers can't set headers when opening a websocket, so the token
+		// may also come in the query string
+		TokenLookup: "header:Authorization,query:access_token",
+		AuthScheme:  "Bearer",
 	})
 }
 
@@ -35,6 +42,11 @@ type ValidationErrorResponse struct {
 
 func ErrorHandlerMiddleware(c *fiber.Ctx, err error) error {
 	if err != nil {
+		var forbidden *services.ForbiddenError
+		if errors.As(err, &forbidden) {
+			return c.Status(fiber.StatusForbidden).SendString(forbidden.Error())
+		}
+
 		validationErrors, ok := err.(*services.ValidationError)
 		if ok {
 			resp := convertValidationErrorsToResponse(validationErrors)
@@ -61,6 +73,46 @@ func convertValidationErrorsToResponse(
 	return error
 }
 
+// limitBody rejects requests with a body bigger than limit, except the ones
+// to the paths exempt reports, whose routes must limit their body themselves,
+// see streamTooLarge.
+func limitBody(limit int, exempt func(path string) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if exempt(strings.TrimSuffix(c.Path(), "/")) {
+			return c.Next()
+		}
+
+		// bodies within the limit of the app are already read, only streamed
+		// ones may be too big
+		if !c.Request().IsBodyStream() {
+			return c.Next()
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request().BodyStream(), int64(limit)+1))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+		}
+		if len(body) > limit {
+			return c.Status(fiber.StatusRequestEntityTooLarge).SendString(fiber.ErrRequestEntityTooLarge.Message)
+		}
+		c.Request().SetBody(body)
+
+		return c.Next()
+	}
+}
+
+// streamTooLarge tells whether a body bigger than the default limit, and so
+// streamed, declares more than limit bytes or doesn't declare its size at
+// all. Such bodies are only ever read up to the size they declare.
+func streamTooLarge(c *fiber.Ctx, limit int) bool {
+	if !c.Request().IsBodyStream() {
+		return false
+	}
+
+	length := c.Request().Header.ContentLength()
+	return length < 0 || length > limit
+}
+
 func extractTokenFromContext(c *fiber.Ctx) string {
 	user := c.Locals("user").(*jwt.Token)
 	id := user.Claims.(jwt.MapClaims)["sub"].(string)
@@ -0,0 +1,704 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/domain/session_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+type MessageType string
+
+// Server to client messages
+const (
+	JoinedMessage          MessageType = "joined"
+	PlayerJoinedMessage    MessageType = "player_joined"
+	QuestionStartedMessage MessageType = "question_started"
+	AnswerAcceptedMessage  MessageType = "answer_accepted"
+	TimeUpMessage          MessageType = "time_up"
+	LeaderboardMessage     MessageType = "leaderboard"
+	GameOverMessage        MessageType = "game_over"
+	ErrorMessage           MessageType = "error"
+)
+
+// Client to server messages
+const (
+	JoinMessage   MessageType = "join"
+	AnswerMessage MessageType = "answer"
+	NextMessage   MessageType = "next"
+	SkipMessage   MessageType = "skip"
+	EndMessage    MessageType = "end"
+)
+
+var (
+	ErrUnexpectedMessage = errors.New("Unexpected message")
+	ErrNotJoined         = errors.New("Join the session before sending other messages")
+)
+
+// Message
+//
+//	@Description	Envelope of every message exchanged over a session websocket
+type Message struct {
+	// the kind of message
+	Type MessageType `json:"type"`
+	// data of the message, its shape depends on the type
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// JoinPayload
+//
+//	@Description	Sent by players to enter a lobby, or to reconnect with a token
+type JoinPayload struct {
+	// nickname of a new player
+	Nickname string `json:"nickname,omitempty"`
+	// token of a player that already joined
+	Token string `json:"token,omitempty"`
+}
+
+// AnswerPayload
+//
+//	@Description	Sent by players to answer the running question
+type AnswerPayload struct {
+	// index of the question being answered
+	QuestionIndex int `json:"question_index"`
+	// index or indices of the chosen alternatives for quiz questions, the
+	// chosen alternative text for true or false questions, the typed text for
+	// type answer questions, the items in the chosen order for ordering
+	// questions, the picked number for slider questions, the index of the
+	// chosen option for poll questions, a few words for word cloud questions
+	Answer json.RawMessage `json:"answer"`
+}
+
+// JoinedPayload
+//
+//	@Description	Confirms to a player that it is in the session
+type JoinedPayload struct {
+	PlayerId string `json:"player_id"`
+	Nickname string `json:"nickname"`
+	Token    string `json:"token"`
+}
+
+// PlayerJoinedPayload
+//
+//	@Description	Tells everyone in the lobby that a player arrived
+type PlayerJoinedPayload struct {
+	PlayerId string           `json:"player_id"`
+	Nickname string           `json:"nickname"`
+	Players  []PlayerResponse `json:"players"`
+}
+
+// QuestionStartedPayload
+//
+//	@Description	A question that is now accepting answers
+type QuestionStartedPayload struct {
+	QuestionIndex  int       `json:"question_index"`
+	TotalQuestions int       `json:"total_questions"`
+	Kind           game.Kind `json:"kind"`
+	Title          string    `json:"title"`
+	Points         int       `json:"points"`
+	TimeLimit      int       `json:"time_limit"`
+	Alternatives   []string  `json:"alternatives"`
+	// the title as sanitized HTML, following the format
+	RenderedTitle string `json:"rendered_title"`
+	// plain, markdown or markdown_latex, formulas being in math spans
+	Format string `json:"format"`
+	// single or multiple for questions answered by picking alternatives
+	Mode string `json:"mode,omitempty"`
+	// range of the answers to slider questions
+	Slider *SliderPayload `json:"slider,omitempty"`
+	// image, audio or video shown along the question
+	Media *MediaPayload `json:"media,omitempty"`
+	// alternatives as sanitized HTML, in the same order
+	RenderedAlternatives []string `json:"rendered_alternatives,omitempty"`
+	// media of each alternative, null for the ones without
+	AlternativesMedia []*MediaPayload `json:"alternatives_media,omitempty"`
+	Deadline          time.Time       `json:"deadline"`
+}
+
+// MediaPayload
+//
+//	@Description	Media to fetch from /media/{id} and show along a question
+type MediaPayload struct {
+	Id    string  `json:"id"`
+	Kind  string  `json:"kind"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+func newMediaPayload(media *game.Media) *MediaPayload {
+	if media == nil {
+		return nil
+	}
+
+	return &MediaPayload{
+		Id:    media.Id.String(),
+		Kind:  string(media.Kind),
+		Start: media.Start,
+		End:   media.End,
+	}
+}
+
+// SliderPayload
+//
+//	@Description	Range a slider question is answered within
+type SliderPayload struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Step float64 `json:"step"`
+}
+
+// AnswerAcceptedPayload
+//
+//	@Description	Acknowledges an answer, hosts also get how many players answered
+type AnswerAcceptedPayload struct {
+	QuestionIndex int `json:"question_index"`
+	Answers       int `json:"answers"`
+	Players       int `json:"players"`
+}
+
+// TimeUpPayload
+//
+//	@Description	The question stopped accepting answers, players get their own result
+type TimeUpPayload struct {
+	QuestionIndex int  `json:"question_index"`
+	Answers       int  `json:"answers"`
+	Answered      bool `json:"answered"`
+	Correct       bool `json:"correct"`
+	Points        int  `json:"points"`
+	Score         int  `json:"score"`
+	// how the answers were spread, only for ungraded questions such as polls
+	Distribution []TallyPayload `json:"distribution,omitempty"`
+}
+
+// TallyPayload
+//
+//	@Description	How many players gave an answer to an ungraded question
+type TallyPayload struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// LeaderboardEntry
+//
+//	@Description	Position of a player in the leaderboard
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	PlayerId string `json:"player_id"`
+	Nickname string `json:"nickname"`
+	Score    int    `json:"score"`
+}
+
+// LeaderboardPayload
+//
+//	@Description	Players ranked by score
+type LeaderboardPayload struct {
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// ErrorPayload
+//
+//	@Description	Something went wrong handling the last message
+type ErrorPayload struct {
+	Message string `json:"message"`
+}
+
+type wsClient struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (c *wsClient) send(kind MessageType, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.conn.WriteJSON(Message{Type: kind, Payload: data})
+}
+
+type sessionRoom struct {
+	hosts   map[*wsClient]struct{}
+	players map[uuid.UUID]*wsClient
+}
+
+// SessionHub keeps track of the websockets connected to each session and
+// delivers the session events to them.
+type SessionHub struct {
+	mu    sync.RWMutex
+	rooms map[string]*sessionRoom
+}
+
+func NewSessionHub() *SessionHub {
+	return &SessionHub{
+		rooms: make(map[string]*sessionRoom),
+	}
+}
+
+func (h *SessionHub) room(pin string) *sessionRoom {
+	r, ok := h.rooms[pin]
+	if !ok {
+		r = &sessionRoom{
+			hosts:   make(map[*wsClient]struct{}),
+			players: make(map[uuid.UUID]*wsClient),
+		}
+		h.rooms[pin] = r
+	}
+
+	return r
+}
+
+func (h *SessionHub) addHost(pin string, client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.room(pin).hosts[client] = struct{}{}
+}
+
+func (h *SessionHub) addPlayer(pin string, playerId uuid.UUID, client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.room(pin).players[playerId] = client
+}
+
+func (h *SessionHub) remove(pin string, client *wsClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r, ok := h.rooms[pin]
+	if !ok {
+		return
+	}
+
+	delete(r.hosts, client)
+	for id, c := range r.players {
+		if c == client {
+			delete(r.players, id)
+		}
+	}
+
+	if len(r.hosts) == 0 && len(r.players) == 0 {
+		delete(h.rooms, pin)
+	}
+}
+
+// clients returns a copy of the connections of a session, so that messages
+// can be written without holding the hub lock.
+func (h *SessionHub) clients(pin string) ([]*wsClient, map[uuid.UUID]*wsClient) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	r, ok := h.rooms[pin]
+	if !ok {
+		return nil, nil
+	}
+
+	hosts := make([]*wsClient, 0, len(r.hosts))
+	for c := range r.hosts {
+		hosts = append(hosts, c)
+	}
+
+	players := make(map[uuid.UUID]*wsClient, len(r.players))
+	for id, c := range r.players {
+		players[id] = c
+	}
+
+	return hosts, players
+}
+
+func (h *SessionHub) Publish(event session.Event) {
+	sess := event.Session
+	hosts, players := h.clients(sess.Pin)
+
+	broadcast := func(kind MessageType, payload any) {
+		for _, c := range hosts {
+			_ = c.send(kind, payload)
+		}
+		for _, c := range players {
+			_ = c.send(kind, payload)
+		}
+	}
+
+	switch event.Kind {
+	case session.PlayerJoinedEvent:
+		player := sess.FindPlayer(event.PlayerId)
+		broadcast(PlayerJoinedMessage, PlayerJoinedPayload{
+			PlayerId: player.Id.String(),
+			Nickname: player.Nickname,
+			Players:  newSessionResponse(sess).Players,
+		})
+
+	case session.QuestionStartedEvent:
+		broadcast(QuestionStartedMessage, newQuestionStartedPayload(sess))
+
+	case session.AnswerAcceptedEvent:
+		payload := AnswerAcceptedPayload{
+			QuestionIndex: event.Answer.Question,
+			Answers:       len(sess.AnswersTo(event.Answer.Question)),
+			Players:       len(sess.Players),
+		}
+		for _, c := range hosts {
+			_ = c.send(AnswerAcceptedMessage, payload)
+		}
+		if c, ok := players[event.PlayerId]; ok {
+			_ = c.send(AnswerAcceptedMessage, payload)
+		}
+
+	case session.TimeUpEvent:
+		answers := len(sess.AnswersTo(sess.CurrentQuestion))
+		distribution := newDistributionPayload(sess)
+		for _, c := range hosts {
+			_ = c.send(TimeUpMessage, TimeUpPayload{
+				QuestionIndex: sess.CurrentQuestion,
+				Answers:       answers,
+				Distribution:  distribution,
+			})
+		}
+		for id, c := range players {
+			payload := TimeUpPayload{
+				QuestionIndex: sess.CurrentQuestion,
+				Answers:       answers,
+				Distribution:  distribution,
+			}
+			if player := sess.FindPlayer(id); player != nil {
+				payload.Score = player.Score
+			}
+			if answer := sess.FindAnswer(id, sess.CurrentQuestion); answer != nil {
+				payload.Answered = true
+				payload.Correct = answer.Correct
+				payload.Points = answer.Points
+			}
+			_ = c.send(TimeUpMessage, payload)
+		}
+
+	case session.LeaderboardEvent:
+		broadcast(LeaderboardMessage, newLeaderboardPayload(sess))
+
+	case session.GameOverEvent:
+		broadcast(GameOverMessage, newLeaderboardPayload(sess))
+		for _, c := range hosts {
+			_ = c.conn.Close()
+		}
+		for _, c := range players {
+			_ = c.conn.Close()
+		}
+	}
+}
+
+func newLeaderboardPayload(sess *session.Session) LeaderboardPayload {
+	ranking := sess.Leaderboard()
+	entries := make([]LeaderboardEntry, len(ranking))
+	for i, p := range ranking {
+		entries[i] = LeaderboardEntry{
+			Rank:     i + 1,
+			PlayerId: p.Id.String(),
+			Nickname: p.Nickname,
+			Score:    p.Score,
+		}
+	}
+
+	return LeaderboardPayload{Entries: entries}
+}
+
+// newDistributionPayload tallies the answers to the current question, it is
+// nil for graded questions.
+func newDistributionPayload(sess *session.Session) []TallyPayload {
+	tallies := sess.Distribution(sess.CurrentQuestion)
+	if tallies == nil {
+		return nil
+	}
+
+	distribution := make([]TallyPayload, len(tallies))
+	for i, t := range tallies {
+		distribution[i] = TallyPayload{Value: t.Value, Count: t.Count}
+	}
+
+	return distribution
+}
+
+// newQuestionStartedPayload describes the current question. Kinds without
+// anything to show besides their title are sent without alternatives.
+func newQuestionStartedPayload(sess *session.Session) QuestionStartedPayload {
+	q := sess.Question()
+	payload := QuestionStartedPayload{
+		QuestionIndex:  sess.CurrentQuestion,
+		TotalQuestions: len(sess.Game.Questions),
+		Kind:           q.Kind(),
+		Title:          q.GetTitle(),
+		Points:         int(q.GetPoints()),
+		TimeLimit:      int(q.GetTimeLimit()),
+		Alternatives:   []string{},
+		Media:          newMediaPayload(q.GetMedia()),
+		RenderedTitle:  game.RenderText(game.PlainText, q.GetTitle()),
+		Format:         string(game.PlainText),
+		Deadline:       sess.Deadline(),
+	}
+
+	// Questions saved before their text had a format have no render, they
+	// are plain text
+	if rendered, ok := q.(game.RenderedQuestion); ok && rendered.GetRenderedTitle() != "" {
+		payload.RenderedTitle = rendered.GetRenderedTitle()
+		if rendered.GetFormat() != "" {
+			payload.Format = string(rendered.GetFormat())
+		}
+	}
+
+	presentation := game.Present(q)
+	payload.Mode = string(presentation.Mode)
+	if presentation.Range != nil {
+		payload.Slider = &SliderPayload{
+			Min:  presentation.Range.Min,
+			Max:  presentation.Range.Max,
+			Step: presentation.Range.Step,
+		}
+	}
+
+	choices := presentation.Choices
+	if presentation.Shuffle {
+		choices = slices.Clone(choices)
+		rand.Shuffle(len(choices), func(i, j int) {
+			choices[i], choices[j] = choices[j], choices[i]
+		})
+	}
+
+	if len(choices) > 0 {
+		payload.Alternatives = make([]string, len(choices))
+		payload.RenderedAlternatives = make([]string, len(choices))
+		for i, c := range choices {
+			payload.Alternatives[i] = c.Text
+			payload.RenderedAlternatives[i] = c.RenderedOrDefault()
+		}
+	}
+
+	if slices.ContainsFunc(choices, func(c game.Choice) bool { return c.Media != nil }) {
+		payload.AlternativesMedia = make([]*MediaPayload, len(choices))
+		for i, c := range choices {
+			payload.AlternativesMedia[i] = newMediaPayload(c.Media)
+		}
+	}
+
+	return payload
+}
+
+// decodeAnswer turns the raw answer sent by a player into the value the
+// question expects in IsCorrect, or in Tally for ungraded questions.
+func decodeAnswer(q game.Question, raw json.RawMessage) (any, error) {
+	spec, err := game.LookupKind(q.Kind())
+	if err != nil {
+		return nil, err
+	}
+
+	return spec.DecodeAnswer(q, raw)
+}
+
+func requireWebSocketUpgrade(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return fiber.ErrUpgradeRequired
+	}
+
+	return c.Next()
+}
+
+// AuthorizeHost makes sure the user opening the host websocket is the host of
+// the session before upgrading the connection.
+func (h *sessionHandler) AuthorizeHost(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	_, err := h.sessionService.GetHostedSession(c.Context(), userId, c.Params("pin"))
+	if err != nil {
+		return h.handleHostError(c, err)
+	}
+
+	c.Locals("hostId", userId)
+	return c.Next()
+}
+
+// HostSocket godoc
+//
+//	@Summary		Host websocket of a session
+//	@Description	Receives every session message and accepts the next, skip and end controls
+//	@Tags			Session
+//	@Param			pin				path	string	true	"Session pin"
+//	@Param			access_token	query	string	false	"JWT when the Authorization header can't be set"
+//	@Success		101
+//	@Failure		401	{string}	string
+//	@Failure		403	{string}	string
+//	@Failure		404	{string}	string
+//	@Failure		426	{string}	string
+//	@Router			/session/{pin}/host [get]
+func (h *sessionHandler) HostSocket(conn *websocket.Conn) {
+	ctx := context.Background()
+	pin := conn.Params("pin")
+	hostId := conn.Locals("hostId").(string)
+	client := &wsClient{conn: conn}
+
+	h.hub.addHost(pin, client)
+	defer h.hub.remove(pin, client)
+
+	for {
+		var msg Message
+		err := conn.ReadJSON(&msg)
+		if err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case NextMessage:
+			_, err = h.sessionService.NextQuestion(ctx, hostId, pin)
+		case SkipMessage:
+			var sess *session.Session
+			sess, err = h.sessionService.GetHostedSession(ctx, hostId, pin)
+			if err == nil {
+				err = h.sessionService.TimeUp(ctx, pin, sess.CurrentQuestion)
+			}
+		case EndMessage:
+			err = h.sessionService.CloseSession(ctx, hostId, pin)
+		default:
+			err = ErrUnexpectedMessage
+		}
+
+		if errors.Is(err, ports.ErrSessionNotFound) {
+			return
+		}
+		if err != nil {
+			_ = client.send(ErrorMessage, newErrorPayload(err))
+		}
+	}
+}
+
+// PlaySocket godoc
+//
+//	@Summary		Player websocket of a session
+//	@Description	Players send a join message first, then answer messages
+//	@Tags			Session
+//	@Param			pin		path	string	true	"Session pin"
+//	@Param			token	query	string	false	"Token of a player that already joined"
+//	@Success		101
+//	@Failure		426	{string}	string
+//	@Router			/session/{pin}/play [get]
+func (h *sessionHandler) PlaySocket(conn *websocket.Conn) {
+	ctx := context.Background()
+	pin := conn.Params("pin")
+	client := &wsClient{conn: conn}
+	var player *session.Player
+
+	defer h.hub.remove(pin, client)
+
+	join := func(payload JoinPayload) error {
+		var err error
+		if payload.Token != "" {
+			player, err = h.sessionService.AuthenticatePlayer(ctx, pin, payload.Token)
+		} else {
+			player, err = h.sessionService.JoinSession(
+				ctx,
+				pin,
+				&services.JoinSessionRequest{Nickname: payload.Nickname},
+			)
+		}
+		if err != nil {
+			return err
+		}
+
+		h.hub.addPlayer(pin, player.Id, client)
+		return client.send(JoinedMessage, JoinedPayload{
+			PlayerId: player.Id.String(),
+			Nickname: player.Nickname,
+			Token:    player.Token,
+		})
+	}
+
+	if token := conn.Query("token"); token != "" {
+		err := join(JoinPayload{Token: token})
+		if err != nil {
+			_ = client.send(ErrorMessage, newErrorPayload(err))
+			return
+		}
+	}
+
+	for {
+		var msg Message
+		err := conn.ReadJSON(&msg)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case msg.Type == JoinMessage && player == nil:
+			var payload JoinPayload
+			err = json.Unmarshal(msg.Payload, &payload)
+			if err == nil {
+				err = join(payload)
+			}
+		case msg.Type == AnswerMessage && player != nil:
+			err = h.answer(ctx, pin, player.Id, msg.Payload)
+		case player == nil:
+			err = ErrNotJoined
+		default:
+			err = ErrUnexpectedMessage
+		}
+
+		if errors.Is(err, ports.ErrSessionNotFound) {
+			_ = client.send(ErrorMessage, newErrorPayload(err))
+			return
+		}
+		if err != nil {
+			_ = client.send(ErrorMessage, newErrorPayload(err))
+		}
+	}
+}
+
+func (h *sessionHandler) answer(
+	ctx context.Context,
+	pin string,
+	playerId uuid.UUID,
+	raw json.RawMessage,
+) error {
+	var payload AnswerPayload
+	err := json.Unmarshal(raw, &payload)
+	if err != nil {
+		return game.ErrInvalidAnswer
+	}
+
+	sess, err := h.sessionService.GetSession(ctx, pin)
+	if err != nil {
+		return err
+	}
+
+	if sess.State != session.QuestionState || sess.CurrentQuestion != payload.QuestionIndex {
+		return ports.ErrQuestionNotRunning
+	}
+
+	value, err := decodeAnswer(sess.Question(), payload.Answer)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.sessionService.SubmitAnswer(ctx, pin, playerId, payload.QuestionIndex, value)
+	return err
+}
+
+func newErrorPayload(err error) ErrorPayload {
+	var validationErrors *services.ValidationError
+	if errors.As(err, &validationErrors) {
+		return ErrorPayload{
+			Message: convertValidationErrorsToResponse(validationErrors).Errors[0],
+		}
+	}
+
+	return ErrorPayload{Message: err.Error()}
+}
@@ -0,0 +1,148 @@
+package web
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RevisionResponse
+//
+//	@Description	A recorded change to a Game
+type RevisionResponse struct {
+	// the number of the revision, starting at 1
+	Number int `json:"number"`
+	// who made the change
+	AuthorId string `json:"author_id"`
+	// when the change was made
+	CreatedAt time.Time `json:"created_at"`
+	// what changed since the previous revision
+	Summary []string `json:"summary"`
+}
+
+// RevisionsResponse
+//
+//	@Description	The history of a Game, newest first
+type RevisionsResponse struct {
+	Revisions []RevisionResponse `json:"revisions"`
+}
+
+// GetRevisions godoc
+//
+//	@Summary	List the revisions of a Game
+//	@Tags		Game
+//	@Produce	json
+//	@Param		gameId	path		string	true	"Game id"
+//	@Success	200		{object}	RevisionsResponse
+//	@Failure	400		{string}	string
+//	@Failure	401		{string}	string
+//	@Failure	403		{string}	string
+//	@Failure	404		{string}	string
+//	@Router		/game/{gameId}/revisions [get]
+func (h *gameHandler) GetRevisions(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	revisions, err := h.gameService.GetRevisions(c.Context(), userId, gameId)
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
+	resp := RevisionsResponse{
+		Revisions: make([]RevisionResponse, len(revisions)),
+	}
+	for i, revision := range revisions {
+		resp.Revisions[i] = RevisionResponse{
+			Number:    revision.Number,
+			AuthorId:  revision.AuthorId,
+			CreatedAt: revision.CreatedAt,
+			Summary:   revision.Summary,
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(resp)
+}
+
+// GetRevision godoc
+//
+//	@Summary	Get a Game as it was at a past revision
+//	@Tags		Game
+//	@Produce	json
+//	@Param		gameId	path	string	true	"Game id"
+//	@Param		number	path	int		true	"Revision number"
+//	@Success	200
+//	@Failure	400	{string}	string
+//	@Failure	401	{string}	string
+//	@Failure	403	{string}	string
+//	@Failure	404	{string}	string
+//	@Router		/game/{gameId}/revisions/{number} [get]
+func (h *gameHandler) GetRevision(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	number, err := c.ParamsInt("number")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	revision, err := h.gameService.GetRevision(c.Context(), userId, gameId, number)
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"revision": RevisionResponse{
+			Number:    revision.Number,
+			AuthorId:  revision.AuthorId,
+			CreatedAt: revision.CreatedAt,
+			Summary:   revision.Summary,
+		},
+		"game": revision.Game,
+	})
+}
+
+// RestoreRevision godoc
+//
+//	@Summary		Restore a past revision of a Game
+//	@Description	The restored version is a draft until the game is published again
+//	@Tags			Game
+//	@Produce		json
+//	@Param			gameId	path	string	true	"Game id"
+//	@Param			number	path	int		true	"Revision number"
+//	@Success		200
+//	@Failure		400	{string}	string
+//	@Failure		401	{string}	string
+//	@Failure		403	{string}	string
+//	@Failure		404	{string}	string
+//	@Router			/game/{gameId}/revisions/{number}/restore [post]
+func (h *gameHandler) RestoreRevision(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	gameId, err := uuid.Parse(c.Params("gameId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	number, err := c.ParamsInt("number")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	restored, err := h.gameService.RestoreRevision(c.Context(), userId, gameId, number)
+	if err != nil {
+		return h.handleGameError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"game": restored,
+	})
+}
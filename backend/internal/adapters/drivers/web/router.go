@@ -2,6 +2,7 @@ package web
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gofiber/contrib/fiberzap/v2"
 	"github.com/gofiber/fiber/v2"
@@ -42,9 +43,15 @@ func (r *Router) Serve() error {
 			AppName:               "brain.test v0.69420",
 			DisableStartupMessage: true,
 			ErrorHandler:          ErrorHandlerMiddleware,
+			// bodies bigger than the default limit are streamed rather than
+			// rejected, so that media can be uploaded and games imported along
+			// their media, limitBody rejects them anywhere else
+			StreamRequestBody:            true,
+			DisablePreParseMultipartForm: true,
 		},
 	)
 	app.Use(recover.New())
+	app.Use(limitBody(fiber.DefaultBodyLimit, r.limitsOwnBody))
 
 	app.Use(fiberzap.New(fiberzap.Config{
 		Logger: r.zapLogger,
@@ -85,3 +92,12 @@ func (r *Router) Serve() error {
 
 	return nil
 }
+
+// limitsOwnBody tells the paths whose routes limit the size of their body
+// themselves: media uploads, and imports of games which may embed media.
+func (r *Router) limitsOwnBody(path string) bool {
+	media := r.config.Prefix + mediaPath
+
+	return path == media || strings.HasPrefix(path, media+"/") ||
+		path == r.config.Prefix+importPath
+}
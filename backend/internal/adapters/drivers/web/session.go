@@ -0,0 +1,309 @@
+package web
+
+import (
+	"errors"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/session_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+// StartSessionRequest
+//
+//	@Description	Request to start a hosted session of a game
+type StartSessionRequest struct {
+	// the id of the game to be played
+	GameId string `json:"game_id" validate:"required,uuid"`
+}
+
+// StartSessionResponse
+//
+//	@Description	A freshly started session
+type StartSessionResponse struct {
+	// the pin players use to join the session
+	Pin string `json:"pin"`
+}
+
+// JoinSessionRequest
+//
+//	@Description	Request to join a session lobby
+type JoinSessionRequest struct {
+	// the nickname shown to the other players
+	Nickname string `json:"nickname" validate:"required"`
+}
+
+// JoinSessionResponse
+//
+//	@Description	The player created when joining a session
+type JoinSessionResponse struct {
+	// the player id
+	PlayerId string `json:"player_id"`
+	// the nickname of the player
+	Nickname string `json:"nickname"`
+	// token that authenticates the player on the session websocket
+	Token string `json:"token"`
+}
+
+// PlayerResponse
+//
+//	@Description	A player in a session
+type PlayerResponse struct {
+	// the player id
+	Id string `json:"id"`
+	// the nickname of the player
+	Nickname string `json:"nickname"`
+}
+
+// SessionResponse
+//
+//	@Description	The public state of a session
+type SessionResponse struct {
+	// the pin of the session
+	Pin string `json:"pin"`
+	// the id of the game being played
+	GameId string `json:"game_id"`
+	// the title of the game being played
+	GameTitle string `json:"game_title"`
+	// one of lobby, question, results or finished
+	State string `json:"state"`
+	// index of the current question, -1 when no question is running
+	CurrentQuestion int `json:"current_question"`
+	// amount of questions in the game
+	TotalQuestions int `json:"total_questions"`
+	// players in the session
+	Players []PlayerResponse `json:"players"`
+}
+
+type sessionHandler struct {
+	jwtMiddleware     fiber.Handler
+	validationService *services.ValidationService
+	sessionService    *services.SessionService
+	hub               *SessionHub
+}
+
+func NewSessionHandler(
+	jwtMiddleware fiber.Handler,
+	validationService *services.ValidationService,
+	sessionService *services.SessionService,
+	hub *SessionHub,
+) *sessionHandler {
+	return &sessionHandler{
+		jwtMiddleware:     jwtMiddleware,
+		validationService: validationService,
+		sessionService:    sessionService,
+		hub:               hub,
+	}
+}
+
+func (h *sessionHandler) RegisterRoutes(router fiber.Router) {
+	sessionApi := router.Group("/session")
+
+	sessionApi.Get("/:pin", h.GetSession)
+	sessionApi.Post("/:pin/join", h.JoinSession)
+	sessionApi.Get("/:pin/play", requireWebSocketUpgrade, websocket.New(h.PlaySocket))
+
+	sessionApi.Use(h.jwtMiddleware)
+	sessionApi.Get(
+		"/:pin/host",
+		requireWebSocketUpgrade,
+		h.AuthorizeHost,
+		websocket.New(h.HostSocket),
+	)
+	sessionApi.Post("/", h.StartSession)
+	sessionApi.Post("/:pin/next", h.NextQuestion)
+	sessionApi.Delete("/:pin", h.CloseSession)
+}
+
+// StartSession godoc
+//
+//	@Summary	Start a hosted session of a game
+//	@Tags		Session
+//	@Accept		json
+//	@Produce	json
+//	@Param		req	body		StartSessionRequest	true	"Start Session Request"
+//	@Success	201	{object}	StartSessionResponse
+//	@Failure	401	{string}	string
+//	@Failure	403	{string}	string
+//	@Failure	404	{string}	string
+//	@Failure	409	{string}	string
+//	@Failure	422	{object}	ValidationErrorResponse
+//	@Router		/session/ [post]
+func (h *sessionHandler) StartSession(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	req := new(StartSessionRequest)
+	err := c.BodyParser(req)
+	if err != nil {
+		return err
+	}
+
+	err = h.validationService.Validate(req)
+	if err != nil {
+		return err
+	}
+
+	sess, err := h.sessionService.StartSession(c.Context(), userId, uuid.MustParse(req.GameId))
+	if err != nil {
+		if errors.Is(err, ports.ErrGameNotFound) {
+			return c.Status(fiber.StatusNotFound).SendString(err.Error())
+		}
+		if errors.Is(err, ports.ErrGameNotPublished) {
+			return c.Status(fiber.StatusConflict).SendString(err.Error())
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(StartSessionResponse{
+		Pin: sess.Pin,
+	})
+}
+
+// JoinSession godoc
+//
+//	@Summary	Join a session lobby
+//	@Tags		Session
+//	@Accept		json
+//	@Produce	json
+//	@Param		pin	path		string				true	"Session pin"
+//	@Param		req	body		JoinSessionRequest	true	"Join Session Request"
+//	@Success	201	{object}	JoinSessionResponse
+//	@Failure	404	{string}	string
+//	@Failure	409	{string}	string
+//	@Failure	422	{object}	ValidationErrorResponse
+//	@Router		/session/{pin}/join [post]
+func (h *sessionHandler) JoinSession(c *fiber.Ctx) error {
+	req := new(JoinSessionRequest)
+	err := c.BodyParser(req)
+	if err != nil {
+		return err
+	}
+
+	err = h.validationService.Validate(req)
+	if err != nil {
+		return err
+	}
+
+	player, err := h.sessionService.JoinSession(
+		c.Context(),
+		c.Params("pin"),
+		&services.JoinSessionRequest{Nickname: req.Nickname},
+	)
+	if err != nil {
+		if errors.Is(err, ports.ErrSessionNotFound) {
+			return c.Status(fiber.StatusNotFound).SendString(err.Error())
+		}
+		if errors.Is(err, ports.ErrNicknameTaken) ||
+			errors.Is(err, ports.ErrSessionAlreadyStarted) {
+			return c.Status(fiber.StatusConflict).SendString(err.Error())
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(JoinSessionResponse{
+		PlayerId: player.Id.String(),
+		Nickname: player.Nickname,
+		Token:    player.Token,
+	})
+}
+
+// GetSession godoc
+//
+//	@Summary	Get the public state of a session
+//	@Tags		Session
+//	@Produce	json
+//	@Param		pin	path		string	true	"Session pin"
+//	@Success	200	{object}	SessionResponse
+//	@Failure	404	{string}	string
+//	@Router		/session/{pin} [get]
+func (h *sessionHandler) GetSession(c *fiber.Ctx) error {
+	sess, err := h.sessionService.GetSession(c.Context(), c.Params("pin"))
+	if err != nil {
+		if errors.Is(err, ports.ErrSessionNotFound) {
+			return c.Status(fiber.StatusNotFound).SendString(err.Error())
+		}
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newSessionResponse(sess))
+}
+
+// NextQuestion godoc
+//
+//	@Summary	Advance a session to its next question
+//	@Tags		Session
+//	@Produce	json
+//	@Param		pin	path		string	true	"Session pin"
+//	@Success	200	{object}	SessionResponse
+//	@Failure	401	{string}	string
+//	@Failure	403	{string}	string
+//	@Failure	404	{string}	string
+//	@Failure	409	{string}	string
+//	@Router		/session/{pin}/next [post]
+func (h *sessionHandler) NextQuestion(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	sess, err := h.sessionService.NextQuestion(c.Context(), userId, c.Params("pin"))
+	if err != nil {
+		return h.handleHostError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newSessionResponse(sess))
+}
+
+// CloseSession godoc
+//
+//	@Summary	Close a session
+//	@Tags		Session
+//	@Param		pin	path	string	true	"Session pin"
+//	@Success	204
+//	@Failure	401	{string}	string
+//	@Failure	403	{string}	string
+//	@Failure	404	{string}	string
+//	@Router		/session/{pin} [delete]
+func (h *sessionHandler) CloseSession(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	err := h.sessionService.CloseSession(c.Context(), userId, c.Params("pin"))
+	if err != nil {
+		return h.handleHostError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *sessionHandler) handleHostError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, ports.ErrSessionNotFound) {
+		return c.Status(fiber.StatusNotFound).SendString(err.Error())
+	}
+	if errors.Is(err, ports.ErrNotSessionHost) {
+		return c.Status(fiber.StatusForbidden).SendString(err.Error())
+	}
+	if errors.Is(err, ports.ErrSessionFinished) {
+		return c.Status(fiber.StatusConflict).SendString(err.Error())
+	}
+	return err
+}
+
+func newSessionResponse(sess *session.Session) SessionResponse {
+	players := make([]PlayerResponse, len(sess.Players))
+	for i, p := range sess.Players {
+		players[i] = PlayerResponse{
+			Id:       p.Id.String(),
+			Nickname: p.Nickname,
+		}
+	}
+
+	return SessionResponse{
+		Pin:             sess.Pin,
+		GameId:          sess.Game.Id.String(),
+		GameTitle:       sess.Game.Title,
+		State:           string(sess.State),
+		CurrentQuestion: sess.CurrentQuestion,
+		TotalQuestions:  len(sess.Game.Questions),
+		Players:         players,
+	}
+}
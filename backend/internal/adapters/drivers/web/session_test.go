@@ -0,0 +1,394 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/memory"
+	"github.com/taldoflemis/brain.test/internal/adapters/drivers/web"
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/domain/result_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+	"github.com/taldoflemis/brain.test/internal/ports"
+	testshelpers "github.com/taldoflemis/brain.test/test/helpers"
+)
+
+const (
+	sessionRoute = "/session/"
+)
+
+// gameStorerStub keeps games in memory so that sessions can be exercised
+// without a database; any method it doesn't override panics.
+type gameStorerStub struct {
+	ports.GameStorer
+	games map[uuid.UUID]*game.Game
+}
+
+func (g *gameStorerStub) FindGameById(ctx context.Context, id uuid.UUID) (*game.Game, error) {
+	found, ok := g.games[id]
+	if !ok {
+		return nil, ports.ErrGameNotFound
+	}
+
+	return found, nil
+}
+
+func (g *gameStorerStub) FindPublishedGame(ctx context.Context, id uuid.UUID) (*game.Game, error) {
+	return g.FindGameById(ctx, id)
+}
+
+// resultStorerStub drops every result; any method it doesn't override panics.
+type resultStorerStub struct {
+	ports.ResultStorer
+}
+
+func (r *resultStorerStub) StoreResult(ctx context.Context, res *result.SessionResult) error {
+	return nil
+}
+
+type SessionHandlerTestSuite struct {
+	suite.Suite
+	app   *fiber.App
+	ctx   context.Context
+	addr  string
+	games *gameStorerStub
+	svc   *services.SessionService
+	idp   ports.AuthenticationManager
+}
+
+func (s *SessionHandlerTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	logger := testshelpers.NewDummyLogger(log.Writer())
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: web.ErrorHandlerMiddleware,
+	})
+
+	// signing and checking tokens never reaches the database
+	jwtMiddleware, idp := newJWTMiddleware(logger, nil)
+	validationService := services.NewValidationService()
+	hub := web.NewSessionHub()
+
+	s.games = &gameStorerStub{games: make(map[uuid.UUID]*game.Game)}
+	s.svc = services.NewSessionService(
+		logger,
+		validationService,
+		services.NewScoringService(),
+		s.games,
+		memory.NewInMemorySessionStorer(),
+		&resultStorerStub{},
+		hub,
+	)
+
+	sessionHandler := web.NewSessionHandler(jwtMiddleware, validationService, s.svc, hub)
+	sessionHandler.RegisterRoutes(app)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatalf("error listening: %s", err)
+	}
+	go func() {
+		_ = app.Listener(ln)
+	}()
+
+	s.app = app
+	s.addr = ln.Addr().String()
+	s.idp = idp
+}
+
+func (s *SessionHandlerTestSuite) TearDownSuite() {
+	if err := s.app.Shutdown(); err != nil {
+		log.Fatalf("error shutting down app: %s", err)
+	}
+}
+
+func TestSessionHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionHandlerTestSuite))
+}
+
+func (s *SessionHandlerTestSuite) storeMockedGame(ownerId string) *game.Game {
+	mockedGame := &game.Game{
+		Id:          uuid.New(),
+		Title:       "session game",
+		Description: "session game description",
+		OwnerId:     ownerId,
+		Questions: []game.Question{
+			&game.TrueFalseQuestion{
+				Title:            "testQuestion",
+				Points:           1,
+				TimeLimit:        30,
+				TrueAlternative:  "testTrueAlternative",
+				FalseAlternative: "testFalseAlternative",
+			},
+		},
+	}
+	s.games.games[mockedGame.Id] = mockedGame
+
+	return mockedGame
+}
+
+func (s *SessionHandlerTestSuite) startSession(hostId string) string {
+	sess, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	if err != nil {
+		log.Fatalf("error starting session: %s", err)
+	}
+
+	return sess.Pin
+}
+
+func (s *SessionHandlerTestSuite) dial(path string) (*websocket.Conn, *http.Response, error) {
+	return websocket.DefaultDialer.Dial("ws://"+s.addr+path, nil)
+}
+
+func (s *SessionHandlerTestSuite) readMessage(conn *websocket.Conn) web.Message {
+	var msg web.Message
+	err := conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	assert.NoError(s.T(), err)
+	err = conn.ReadJSON(&msg)
+	assert.NoError(s.T(), err)
+
+	return msg
+}
+
+func (s *SessionHandlerTestSuite) sendMessage(
+	conn *websocket.Conn,
+	kind web.MessageType,
+	payload any,
+) {
+	var raw json.RawMessage
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		assert.NoError(s.T(), err)
+		raw = data
+	}
+
+	err := conn.WriteJSON(web.Message{Type: kind, Payload: raw})
+	assert.NoError(s.T(), err)
+}
+
+func (s *SessionHandlerTestSuite) TestStartSession() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	mockedGame := s.storeMockedGame(testUserId)
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	resp := e.POST(sessionRoute).
+		WithHeaders(headers).
+		WithJSON(map[string]any{"game_id": mockedGame.Id.String()}).
+		Expect()
+
+	// Assert
+	resp.Status(http.StatusCreated)
+	resp.JSON().Object().Value("pin").String().Length().IsEqual(6)
+}
+
+func (s *SessionHandlerTestSuite) TestStartSessionWithBadInput() {
+	// Arrange
+	t := s.T()
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+	someoneElsesGame := s.storeMockedGame(uuid.NewString())
+
+	table := []struct {
+		desc   string
+		req    map[string]any
+		status int
+	}{
+		{
+			desc:   "unknown game",
+			req:    map[string]any{"game_id": uuid.NewString()},
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "game owned by someone else",
+			req:    map[string]any{"game_id": someoneElsesGame.Id.String()},
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "invalid game id",
+			req:    map[string]any{"game_id": "tubias"},
+			status: http.StatusUnprocessableEntity,
+		},
+	}
+
+	headers := map[string]string{
+		"Authorization": authHeaderPrefix + tok.AccessToken,
+	}
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			resp := e.POST(sessionRoute).WithHeaders(headers).WithJSON(tt.req).Expect()
+
+			// Assert
+			resp.Status(tt.status)
+		})
+	}
+}
+
+func (s *SessionHandlerTestSuite) TestJoinSession() {
+	// Arrange
+	t := s.T()
+	pin := s.startSession(uuid.NewString())
+
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	resp := e.POST(sessionRoute + pin + "/join").
+		WithJSON(map[string]any{"nickname": "tubias"}).
+		Expect()
+	again := e.POST(sessionRoute + pin + "/join").
+		WithJSON(map[string]any{"nickname": "tubias"}).
+		Expect()
+	lobby := e.GET(sessionRoute + pin).Expect()
+
+	// Assert
+	resp.Status(http.StatusCreated)
+	obj := resp.JSON().Object()
+	obj.Value("nickname").IsEqual("tubias")
+	obj.Value("token").String().NotEmpty()
+	again.Status(http.StatusConflict)
+	lobby.Status(http.StatusOK)
+	lobby.JSON().Object().Value("players").Array().Length().IsEqual(1)
+}
+
+func (s *SessionHandlerTestSuite) TestJoinUnknownSession() {
+	// Arrange
+	t := s.T()
+	server := httptest.NewServer(adaptor.FiberApp(s.app))
+	e := httpexpect.Default(t, server.URL)
+
+	// Act
+	resp := e.POST(sessionRoute + "000000/join").
+		WithJSON(map[string]any{"nickname": "tubias"}).
+		Expect()
+
+	// Assert
+	resp.Status(http.StatusNotFound)
+}
+
+func (s *SessionHandlerTestSuite) TestHostSocketByAnotherUser() {
+	// Arrange
+	t := s.T()
+	pin := s.startSession(uuid.NewString())
+	tok, err := s.idp.CreateToken(s.ctx, testUserId)
+	assert.NoError(t, err)
+
+	// Act
+	_, resp, err := s.dial(sessionRoute + pin + "/host?access_token=" + tok.AccessToken)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func (s *SessionHandlerTestSuite) TestPlaySocketWithoutJoining() {
+	// Arrange
+	t := s.T()
+	pin := s.startSession(uuid.NewString())
+	player, _, err := s.dial(sessionRoute + pin + "/play")
+	assert.NoError(t, err)
+	defer player.Close()
+
+	// Act
+	s.sendMessage(player, web.AnswerMessage, web.AnswerPayload{})
+
+	// Assert
+	msg := s.readMessage(player)
+	assert.Equal(t, web.ErrorMessage, msg.Type)
+}
+
+func (s *SessionHandlerTestSuite) TestGameplayOverWebSocket() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	pin := s.startSession(hostId)
+	tok, err := s.idp.CreateToken(s.ctx, hostId)
+	assert.NoError(t, err)
+
+	host, _, err := s.dial(sessionRoute + pin + "/host?access_token=" + tok.AccessToken)
+	assert.NoError(t, err)
+	defer host.Close()
+	player, _, err := s.dial(sessionRoute + pin + "/play")
+	assert.NoError(t, err)
+	defer player.Close()
+
+	// Act
+	s.sendMessage(player, web.JoinMessage, web.JoinPayload{Nickname: "tubias"})
+	joined := s.readMessage(player)
+	playerJoined := s.readMessage(host)
+
+	s.sendMessage(host, web.NextMessage, nil)
+	hostQuestion := s.readMessage(host)
+	playerQuestion := s.readMessage(player)
+
+	s.sendMessage(player, web.AnswerMessage, map[string]any{
+		"question_index": 0,
+		"answer":         "testTrueAlternative",
+	})
+	accepted := s.readMessage(player)
+	timeUp := s.readMessage(player)
+	leaderboard := s.readMessage(player)
+
+	s.sendMessage(host, web.NextMessage, nil)
+	gameOver := s.readMessage(player)
+
+	// Assert
+	assert.Equal(t, web.JoinedMessage, joined.Type)
+	assert.Equal(t, web.PlayerJoinedMessage, playerJoined.Type)
+	assert.Equal(t, web.QuestionStartedMessage, hostQuestion.Type)
+	assert.Equal(t, web.QuestionStartedMessage, playerQuestion.Type)
+	assert.Equal(t, web.AnswerAcceptedMessage, accepted.Type)
+	assert.Equal(t, web.TimeUpMessage, timeUp.Type)
+	assert.Equal(t, web.LeaderboardMessage, leaderboard.Type)
+	assert.Equal(t, web.GameOverMessage, gameOver.Type)
+
+	var question web.QuestionStartedPayload
+	err = json.Unmarshal(playerQuestion.Payload, &question)
+	assert.NoError(t, err)
+	assert.Equal(t, question.Alternatives, question.RenderedAlternatives)
+	assert.ElementsMatch(
+		t,
+		[]string{"testTrueAlternative", "testFalseAlternative"},
+		question.Alternatives,
+	)
+
+	var result web.TimeUpPayload
+	err = json.Unmarshal(timeUp.Payload, &result)
+	assert.NoError(t, err)
+	assert.True(t, result.Correct)
+	assert.Positive(t, result.Score)
+
+	var ranking web.LeaderboardPayload
+	err = json.Unmarshal(gameOver.Payload, &ranking)
+	assert.NoError(t, err)
+	assert.Len(t, ranking.Entries, 1)
+	assert.Equal(t, "tubias", ranking.Entries[0].Nickname)
+}
@@ -0,0 +1,167 @@
+package web
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/taldoflemis/brain.test/internal/core/services"
+)
+
+const (
+	// maxSheetRows and maxSheetColumns limit how much of a spreadsheet is
+	// read, blank rows included
+	maxSheetRows    = 10 * services.MaxSpreadsheetRows
+	maxSheetColumns = 64
+)
+
+// SpreadsheetErrorResponse
+//
+//	@Description	A problem with a cell of an imported spreadsheet
+type SpreadsheetErrorResponse struct {
+	// number of the row, the header being row 1
+	Row int `json:"row"`
+	// name of the column in the template
+	Column string `json:"column"`
+	// reference of the cell, e.g. C4, empty when the column is missing
+	Cell string `json:"cell"`
+	// what is wrong with the cell
+	Message string `json:"message"`
+}
+
+// SpreadsheetImportResponse
+//
+//	@Description	The outcome of importing a spreadsheet
+type SpreadsheetImportResponse struct {
+	// number of questions read from the spreadsheet
+	Questions int `json:"questions"`
+	// problems found, the game is only created when there are none
+	Errors []SpreadsheetErrorResponse `json:"errors"`
+	// the created game, missing on dry runs and when there are errors
+	Game any `json:"game,omitempty" swaggertype:"object"`
+}
+
+// ImportSpreadsheet godoc
+//
+//	@Summary		Import a Game from a spreadsheet
+//	@Description	Creates a Game from a CSV or XLSX spreadsheet with a header row and one question per row. The columns are question, kind (quiz or true_false, defaults to quiz), time_limit, points, alternative_1 to alternative_6 and correct, which lists the correct alternatives by number or letter, e.g. "1, 3" or "A C".
+//	@Tags			Game
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			file		formData	file	true	"CSV or XLSX spreadsheet"
+//	@Param			title		formData	string	true	"Title of the game"
+//	@Param			description	formData	string	false	"Description of the game"
+//	@Param			dry_run		query		bool	false	"Only report the problems of the spreadsheet"
+//	@Success		200			{object}	SpreadsheetImportResponse
+//	@Success		201			{object}	SpreadsheetImportResponse
+//	@Failure		400			{string}	string
+//	@Failure		401			{string}	string
+//	@Failure		422			{object}	SpreadsheetImportResponse
+//	@Router			/game/import/spreadsheet [post]
+func (h *gameHandler) ImportSpreadsheet(c *fiber.Ctx) error {
+	userId := extractTokenFromContext(c)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return err
+	}
+
+	rows, err := readSpreadsheet(content)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString(err.Error())
+	}
+
+	dryRun := c.QueryBool("dry_run")
+	result, err := h.gameService.ImportSpreadsheet(c.Context(), userId, &services.ImportSpreadsheetRequest{
+		Title:       c.FormValue("title"),
+		Description: c.FormValue("description"),
+		Rows:        rows,
+		DryRun:      dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := SpreadsheetImportResponse{
+		Questions: result.Questions,
+		Errors:    make([]SpreadsheetErrorResponse, len(result.Errors)),
+	}
+	for i, e := range result.Errors {
+		resp.Errors[i] = SpreadsheetErrorResponse{
+			Row:     e.Row,
+			Column:  e.Column,
+			Cell:    e.Cell,
+			Message: e.Message,
+		}
+	}
+
+	switch {
+	case dryRun:
+		return c.Status(fiber.StatusOK).JSON(resp)
+	case len(resp.Errors) > 0:
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(resp)
+	default:
+		resp.Game = result.Game
+		return c.Status(fiber.StatusCreated).JSON(resp)
+	}
+}
+
+// readSpreadsheet reads the cells of an XLSX workbook or of a CSV file,
+// telling them apart by their content.
+func readSpreadsheet(content []byte) ([][]string, error) {
+	if bytes.HasPrefix(content, []byte("PK\x03\x04")) {
+		return readXLSX(bytes.NewReader(content), int64(len(content)))
+	}
+
+	return readCSV(content)
+}
+
+// readCSV reads comma or semicolon separated values, the latter being what
+// spreadsheets write in locales using commas as decimal separators.
+func readCSV(content []byte) ([][]string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	header, _, _ := strings.Cut(string(content), "\n")
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if strings.Count(header, ";") > strings.Count(header, ",") {
+		reader.Comma = ';'
+	}
+
+	rows := make([][]string, 0)
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == maxSheetRows {
+			break
+		}
+		if len(row) > maxSheetColumns {
+			row = row[:maxSheetColumns]
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
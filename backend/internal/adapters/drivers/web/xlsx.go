@@ -0,0 +1,230 @@
+package web
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxXLSXPartSize limits how much of each file of a workbook is read once
+	// uncompressed
+	maxXLSXPartSize = 16 << 20
+)
+
+var ErrInvalidXLSX = errors.New("Invalid XLSX workbook")
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Id string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		Id     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	T string `xml:"t"`
+	// R are the runs of rich text
+	R []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.R) == 0 {
+		return t.T
+	}
+
+	var text strings.Builder
+	for _, run := range t.R {
+		text.WriteString(run.T)
+	}
+
+	return text.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string   `xml:"r,attr"`
+			Type   string   `xml:"t,attr"`
+			Value  string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX reads the cells of the first worksheet of a workbook as text.
+func readXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalidXLSX
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedStrings := &xlsxSharedStrings{}
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		err = decodeXLSXPart(f, sharedStrings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrInvalidXLSX
+	}
+	sheet := &xlsxWorksheet{}
+	err = decodeXLSXPart(f, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		number := row.Number
+		if number == 0 {
+			number = len(rows) + 1
+		}
+		if number > maxSheetRows || number < len(rows) {
+			return nil, ErrInvalidXLSX
+		}
+		// rows without any value may be left out of the worksheet
+		for len(rows) < number {
+			rows = append(rows, []string{})
+		}
+
+		cells := rows[number-1]
+		for _, c := range row.Cells {
+			column := len(cells)
+			if c.Ref != "" {
+				column, err = xlsxColumn(c.Ref)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if column >= maxSheetColumns {
+				continue
+			}
+			for len(cells) <= column {
+				cells = append(cells, "")
+			}
+
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(sharedStrings.Items) {
+					return nil, ErrInvalidXLSX
+				}
+				cells[column] = sharedStrings.Items[i].String()
+			case "inlineStr":
+				cells[column] = c.Inline.String()
+			default:
+				cells[column] = c.Value
+			}
+		}
+		rows[number-1] = cells
+	}
+
+	return rows, nil
+}
+
+// firstSheetPath finds the file of the first worksheet of the workbook.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	workbook := &xlsxWorkbook{}
+	relationships := &xlsxRelationships{}
+
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", ErrInvalidXLSX
+	}
+	err := decodeXLSXPart(workbookFile, workbook)
+	if err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", ErrInvalidXLSX
+	}
+
+	relationshipsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "", ErrInvalidXLSX
+	}
+	err = decodeXLSXPart(relationshipsFile, relationships)
+	if err != nil {
+		return "", err
+	}
+
+	for _, relationship := range relationships.Relationships {
+		if relationship.Id != workbook.Sheets[0].Id {
+			continue
+		}
+
+		// targets are relative to the workbook unless absolute
+		if strings.HasPrefix(relationship.Target, "/") {
+			return strings.TrimPrefix(relationship.Target, "/"), nil
+		}
+		return path.Join("xl", relationship.Target), nil
+	}
+
+	return "", ErrInvalidXLSX
+}
+
+func decodeXLSXPart(f *zip.File, v any) error {
+	content, err := f.Open()
+	if err != nil {
+		return ErrInvalidXLSX
+	}
+	defer content.Close()
+
+	err = xml.NewDecoder(io.LimitReader(content, maxXLSXPartSize)).Decode(v)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidXLSX, err)
+	}
+
+	return nil
+}
+
+// xlsxColumn reads the index, from 0, of the column of a cell reference such
+// as C4.
+func xlsxColumn(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		column = column*26 + int(c-'A') + 1
+		letters++
+	}
+
+	if letters == 0 || letters > 3 {
+		return 0, ErrInvalidXLSX
+	}
+
+	return column - 1, nil
+}
@@ -0,0 +1,32 @@
+package game
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BankQuestion is a question saved to the personal bank of its owner, apart
+// from any game. Games never share a bank question, they get a copy of it.
+type BankQuestion struct {
+	Id       uuid.UUID `json:"id"         validate:"required"`
+	OwnerId  string    `json:"owner_id"   validate:"required,min=1"`
+	Question Question  `json:"question"   validate:"required"`
+	// Tags are lowercase and sorted, see NormalizeTags
+	Tags      []string  `json:"tags"       validate:"lte=20,dive,required,lte=40"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewBankQuestion saves a copy of the question, with a fresh id, to the bank
+// of ownerId.
+func NewBankQuestion(ownerId string, question Question, tags []string, now time.Time) *BankQuestion {
+	return &BankQuestion{
+		Id:        uuid.New(),
+		OwnerId:   ownerId,
+		Question:  question.Clone(),
+		Tags:      NormalizeTags(tags),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -1,13 +1,61 @@
 package game
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 type Game struct {
-	Id          uuid.UUID  `json:"id"          validate:"required,uuid4"`
-	Title       string     `json:"title"       validate:"required,gte=1,lte=120"`
-	Description string     `json:"description" validate:"min=1,max=200"`
-	OwnerId     string     `json:"owner_id"    validate:"required,min=1"`
-	Questions   []Question `json:"questions"   validate:"required,min=1,dive"`
+	Metadata
+	Id          uuid.UUID      `json:"id"          validate:"required,uuid4"`
+	Title       string         `json:"title"       validate:"required,gte=1,lte=120"`
+	Description string         `json:"description" validate:"min=1,max=200"`
+	OwnerId     string         `json:"owner_id"    validate:"required,min=1"`
+	Questions   []Question     `json:"questions"   validate:"required,min=1,dive"`
+	Scoring     *ScoringPolicy `json:"scoring"     validate:"omitempty"`
+	Visibility  Visibility     `json:"visibility"  validate:"omitempty,oneof=private unlisted public"`
+	// ForkedFrom is the id of the game this one was copied from, if any
+	ForkedFrom *uuid.UUID `json:"forked_from" validate:"omitempty"`
+	Status     Status     `json:"status"      validate:"omitempty,oneof=draft published"`
+	// Revision counts the drafts of the game, it is bumped when a published
+	// game is edited
+	Revision int `json:"revision"`
+	// PublishedAt is when the game was last published, if ever
+	PublishedAt *time.Time `json:"published_at"`
+	// CreatedAt and UpdatedAt are kept by the storage, UpdatedAt changing on
+	// every edit of the game
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Fork deep copies the game into a new private one owned by ownerId. Every
+// question of the copy gets a fresh id.
+func (g *Game) Fork(ownerId string) *Game {
+	questions := make([]Question, len(g.Questions))
+	for i, q := range g.Questions {
+		questions[i] = q.Clone()
+	}
+
+	var scoring *ScoringPolicy
+	if g.Scoring != nil {
+		policy := *g.Scoring
+		scoring = &policy
+	}
+
+	forkedFrom := g.Id
+
+	return &Game{
+		Metadata:    g.Metadata.Clone(),
+		Id:          uuid.New(),
+		Title:       g.Title,
+		Description: g.Description,
+		OwnerId:     ownerId,
+		Questions:   questions,
+		Scoring:     scoring,
+		Visibility:  PrivateVisibility,
+		ForkedFrom:  &forkedFrom,
+		Status:      DraftStatus,
+		Revision:    1,
+	}
 }
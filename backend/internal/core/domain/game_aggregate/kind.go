@@ -0,0 +1,139 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownQuestionKind = errors.New("Unknown question kind")
+	ErrInvalidAnswer       = errors.New("Invalid answer for the current question")
+)
+
+// Kind names a type of question, e.g. in requests or where it is stored.
+type Kind string
+
+// Header is what every question has, whatever its kind.
+type Header struct {
+	Id        uuid.UUID
+	Title     string
+	Points    Points
+	TimeLimit TimeLimit
+	Media     *Media
+	Format    TextFormat
+	// RenderedTitle is the title as sanitized HTML
+	RenderedTitle string
+	Metadata      Metadata
+}
+
+// KindSpec is what the core needs to know about a kind of question besides
+// its methods. Scoring goes through the methods of Question, and of
+// PartialCreditQuestion and UngradedQuestion for the kinds implementing them.
+// How a kind is stored or requested is up to each adapter, which registers
+// every kind it maps in one place.
+type KindSpec struct {
+	Kind Kind
+	// New returns a question of the kind with only its header filled in, the
+	// rest being decoded into it
+	New func(header Header) Question
+	// DecodeAnswer turns the JSON answer sent by a player into the value the
+	// question expects, failing with ErrInvalidAnswer
+	DecodeAnswer func(question Question, raw json.RawMessage) (any, error)
+	// Present describes how questions of the kind are answered, see
+	// Presentation. It may be nil for kinds answered with nothing more than
+	// their header tells.
+	Present func(question Question) Presentation
+	// Validations are the custom validation tags used by the fields of the
+	// kind
+	Validations map[string]validator.Func
+}
+
+var (
+	kindsMu sync.RWMutex
+	kinds   = make(map[Kind]*KindSpec)
+)
+
+// RegisterKind makes a kind of question known to the app. Kinds are meant to
+// be registered from init, before the validation service is created, and
+// registering one twice panics.
+func RegisterKind(spec KindSpec) {
+	kindsMu.Lock()
+	defer kindsMu.Unlock()
+
+	if spec.Kind == "" || spec.New == nil || spec.DecodeAnswer == nil {
+		panic("game: incomplete question kind")
+	}
+	if _, ok := kinds[spec.Kind]; ok {
+		panic(fmt.Sprintf("game: question kind %q registered twice", spec.Kind))
+	}
+
+	kinds[spec.Kind] = &spec
+}
+
+// LookupKind returns the spec of a registered kind.
+func LookupKind(kind Kind) (*KindSpec, error) {
+	kindsMu.RLock()
+	defer kindsMu.RUnlock()
+
+	spec, ok := kinds[kind]
+	if !ok {
+		return nil, ErrUnknownQuestionKind
+	}
+
+	return spec, nil
+}
+
+// Kinds returns the spec of every registered kind, sorted by kind.
+func Kinds() []*KindSpec {
+	kindsMu.RLock()
+	defer kindsMu.RUnlock()
+
+	specs := make([]*KindSpec, 0, len(kinds))
+	for _, spec := range kinds {
+		specs = append(specs, spec)
+	}
+
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Kind < specs[j].Kind
+	})
+
+	return specs
+}
+
+// DecodeQuestion decodes a question of the kind from its JSON, whose fields
+// are named after the ones of the question.
+func DecodeQuestion(kind Kind, data []byte) (Question, error) {
+	spec, err := LookupKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	question := spec.New(Header{})
+	err = json.Unmarshal(data, question)
+	if err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
+
+// answerDecoder adapts a function decoding the answers to questions of type Q
+// to any question.
+func answerDecoder[Q Question](
+	decode func(question Q, raw json.RawMessage) (any, error),
+) func(Question, json.RawMessage) (any, error) {
+	return func(question Question, raw json.RawMessage) (any, error) {
+		q, ok := question.(Q)
+		if !ok {
+			return nil, ErrUnknownQuestionKind
+		}
+
+		return decode(q, raw)
+	}
+}
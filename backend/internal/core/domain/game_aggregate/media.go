@@ -0,0 +1,72 @@
+package game
+
+import (
+	"github.com/google/uuid"
+)
+
+type MediaKind string
+
+const (
+	ImageMedia MediaKind = "image"
+	AudioMedia MediaKind = "audio"
+	VideoMedia MediaKind = "video"
+)
+
+// Media references an uploaded file shown along a question or an
+// alternative.
+type Media struct {
+	Id   uuid.UUID `validate:"required"`
+	Kind MediaKind `validate:"required,oneof=image audio video"`
+	// Start and End are offsets, in seconds, to play only part of an audio or
+	// video, End being zero when it plays until the end
+	Start float64 `validate:"gte=0,excluded_if=Kind image"`
+	End   float64 `validate:"omitempty,gtfield=Start,excluded_if=Kind image"`
+}
+
+// Clone copies the reference, the file itself is shared.
+func (m *Media) Clone() *Media {
+	if m == nil {
+		return nil
+	}
+
+	clone := *m
+	return &clone
+}
+
+// MediaAlternativesQuestion is implemented by questions whose alternatives
+// may have media of their own.
+type MediaAlternativesQuestion interface {
+	AlternativesMedia() []*Media
+}
+
+// AttachedMedia lists the media shown with the question, its own and the
+// ones of its alternatives.
+func AttachedMedia(question Question) []*Media {
+	media := make([]*Media, 0)
+	if m := question.GetMedia(); m != nil {
+		media = append(media, m)
+	}
+
+	if alternatives, ok := question.(MediaAlternativesQuestion); ok {
+		media = append(media, alternatives.AlternativesMedia()...)
+	}
+
+	return media
+}
+
+// MediaIds lists the files the questions of the game reference, each once.
+func (g *Game) MediaIds() []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	ids := make([]uuid.UUID, 0)
+
+	for _, q := range g.Questions {
+		for _, m := range AttachedMedia(q) {
+			if !seen[m.Id] {
+				seen[m.Id] = true
+				ids = append(ids, m.Id)
+			}
+		}
+	}
+
+	return ids
+}
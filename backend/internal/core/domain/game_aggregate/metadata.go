@@ -0,0 +1,77 @@
+package game
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Difficulty tells how hard a game or a question is meant to be.
+type Difficulty string
+
+const (
+	EasyDifficulty   Difficulty = "easy"
+	MediumDifficulty Difficulty = "medium"
+	HardDifficulty   Difficulty = "hard"
+)
+
+// Metadata classifies a game or a question so that its owner can find it
+// among many, none of it changes how it is played.
+type Metadata struct {
+	// Tags are lowercase and sorted, see NormalizeTags
+	Tags []string `json:"tags"       validate:"lte=20,dive,required,lte=40"`
+	// Category is the subject, e.g. biology, lowercase like tags
+	Category   string     `json:"category"   validate:"lte=60"`
+	Difficulty Difficulty `json:"difficulty" validate:"omitempty,oneof=easy medium hard"`
+}
+
+// GetMetadata exposes the metadata of the games and questions embedding it.
+func (m *Metadata) GetMetadata() *Metadata {
+	return m
+}
+
+// Normalize normalizes the tags and the category, see NormalizeTags.
+func (m *Metadata) Normalize() {
+	m.Tags = NormalizeTags(m.Tags)
+	m.Category = NormalizeTag(m.Category)
+}
+
+// Clone deep copies the metadata.
+func (m Metadata) Clone() Metadata {
+	m.Tags = slices.Clone(m.Tags)
+	return m
+}
+
+// NormalizeMetadata normalizes the metadata of the game and of every one of
+// its questions.
+func (g *Game) NormalizeMetadata() {
+	g.Metadata.Normalize()
+	for _, q := range g.Questions {
+		q.GetMetadata().Normalize()
+	}
+}
+
+// NormalizeTags trims and lowercases the tags, dropping empty and repeated
+// ones, so that Photosynthesis and photosynthesis are the same tag.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+
+	return normalized
+}
+
+// NormalizeTag trims and lowercases a single tag, collapsing its spaces.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
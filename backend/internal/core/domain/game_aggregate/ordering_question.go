@@ -0,0 +1,169 @@
+package game
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+const OrderingKind Kind = "ordering"
+
+func init() {
+	RegisterKind(KindSpec{
+		Kind: OrderingKind,
+		New: func(header Header) Question {
+			return &OrderingQuestion{
+				Id:            header.Id,
+				Title:         header.Title,
+				Points:        header.Points,
+				TimeLimit:     header.TimeLimit,
+				Media:         header.Media,
+				Format:        header.Format,
+				RenderedTitle: header.RenderedTitle,
+				Metadata:      header.Metadata,
+			}
+		},
+		DecodeAnswer: answerDecoder((*OrderingQuestion).decodeAnswer),
+		Present:      presenter((*OrderingQuestion).present),
+	})
+}
+
+// OrderingQuestion is answered by arranging its items in the right sequence,
+// e.g. the events of a timeline or the steps of a process.
+type OrderingQuestion struct {
+	Metadata
+	Id            uuid.UUID  `validate:"omitempty"`
+	Title         string     `validate:"required,lte=1000,markup,visiblelte=120"`
+	Points        Points     `validate:"required,gte=0,lte=2"`
+	TimeLimit     TimeLimit  `validate:"required,gte=5,lte=180"`
+	Media         *Media     `validate:"omitempty"`
+	Format        TextFormat `validate:"omitempty,oneof=plain markdown markdown_latex"`
+	RenderedTitle string     `validate:"omitempty"`
+	// Items in the right order, written in the format of the question
+	Items []string `validate:"required,min=2,max=6,unique,dive,required,lte=1000,markup,visiblelte=120"`
+	// RenderedItems are the items as sanitized HTML
+	RenderedItems []string `validate:"omitempty"`
+	// PartialScoring rewards every item placed in the right position, instead
+	// of only the whole sequence
+	PartialScoring bool `validate:"omitempty"`
+}
+
+func (q *OrderingQuestion) Kind() Kind {
+	return OrderingKind
+}
+
+func (q *OrderingQuestion) GetTitle() string {
+	return q.Title
+}
+
+func (q *OrderingQuestion) GetMedia() *Media {
+	return q.Media
+}
+
+func (q *OrderingQuestion) Render() {
+	q.RenderedTitle = RenderText(q.Format, q.Title)
+	q.RenderedItems = renderTexts(q.Format, q.Items)
+}
+
+func (q *OrderingQuestion) GetFormat() TextFormat {
+	return q.Format
+}
+
+func (q *OrderingQuestion) GetRenderedTitle() string {
+	return q.RenderedTitle
+}
+
+// IsCorrect tells whether the answer, the items in the order picked by the
+// player, is the right sequence.
+func (q *OrderingQuestion) IsCorrect(answer any) bool {
+	return q.placed(answer) == len(q.Items)
+}
+
+// PartialCredit grades the answer, the items in the order picked by the
+// player, by the share of items in the right position when partial scoring is
+// on.
+func (q *OrderingQuestion) PartialCredit(answer any) int {
+	placed := q.placed(answer)
+	if len(q.Items) == 0 {
+		return 0
+	}
+
+	if placed == len(q.Items) {
+		return FullCredit
+	}
+
+	if !q.PartialScoring {
+		return 0
+	}
+
+	return placed * FullCredit / len(q.Items)
+}
+
+func (q *OrderingQuestion) GetPoints() Points {
+	return q.Points
+}
+
+func (q *OrderingQuestion) GetTimeLimit() TimeLimit {
+	return q.TimeLimit
+}
+
+func (q *OrderingQuestion) Clone() Question {
+	clone := *q
+	clone.Id = uuid.New()
+	clone.Media = q.Media.Clone()
+	clone.Metadata = q.Metadata.Clone()
+	clone.Items = make([]string, len(q.Items))
+	copy(clone.Items, q.Items)
+	clone.RenderedItems = slices.Clone(q.RenderedItems)
+
+	return &clone
+}
+
+// present shuffles the items, they are kept in the right order.
+func (q *OrderingQuestion) present() Presentation {
+	return Presentation{
+		Choices: choicesOf(q.Format, q.Items, q.RenderedItems),
+		Shuffle: true,
+	}
+}
+
+// decodeAnswer accepts every item, in the order picked by the player.
+func (q *OrderingQuestion) decodeAnswer(raw json.RawMessage) (any, error) {
+	var arranged []string
+	err := json.Unmarshal(raw, &arranged)
+	if err != nil || len(arranged) != len(q.Items) {
+		return nil, ErrInvalidAnswer
+	}
+
+	return arranged, nil
+}
+
+// placed counts the items of the answer in the right position. Answers that
+// aren't an arrangement of every item place none.
+func (q *OrderingQuestion) placed(answer any) int {
+	arranged, ok := answer.([]string)
+	if !ok || len(arranged) != len(q.Items) {
+		return 0
+	}
+
+	remaining := make(map[string]int, len(q.Items))
+	for _, item := range q.Items {
+		remaining[item]++
+	}
+	for _, item := range arranged {
+		if remaining[item] == 0 {
+			return 0
+		}
+		remaining[item]--
+	}
+
+	placed := 0
+	for i, item := range arranged {
+		if item == q.Items[i] {
+			placed++
+		}
+	}
+
+	return placed
+}
@@ -0,0 +1,135 @@
+package game
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+const PollKind Kind = "poll"
+
+func init() {
+	RegisterKind(KindSpec{
+		Kind: PollKind,
+		New: func(header Header) Question {
+			return &PollQuestion{
+				Id:            header.Id,
+				Title:         header.Title,
+				TimeLimit:     header.TimeLimit,
+				Media:         header.Media,
+				Format:        header.Format,
+				RenderedTitle: header.RenderedTitle,
+				Metadata:      header.Metadata,
+			}
+		},
+		DecodeAnswer: answerDecoder((*PollQuestion).decodeAnswer),
+		Present:      presenter((*PollQuestion).present),
+	})
+}
+
+// PollQuestion asks players to pick one of its options. There is no right
+// option, the answers are tallied instead.
+type PollQuestion struct {
+	Metadata
+	Id            uuid.UUID  `validate:"omitempty"`
+	Title         string     `validate:"required,lte=1000,markup,visiblelte=120"`
+	TimeLimit     TimeLimit  `validate:"required,gte=5,lte=180"`
+	Media         *Media     `validate:"omitempty"`
+	Format        TextFormat `validate:"omitempty,oneof=plain markdown markdown_latex"`
+	RenderedTitle string     `validate:"omitempty"`
+	// Options are written in the format of the question
+	Options []string `validate:"required,min=2,max=6,unique,dive,required,lte=1000,markup,visiblelte=120"`
+	// RenderedOptions are the options as sanitized HTML
+	RenderedOptions []string `validate:"omitempty"`
+}
+
+func (q *PollQuestion) Kind() Kind {
+	return PollKind
+}
+
+func (q *PollQuestion) GetTitle() string {
+	return q.Title
+}
+
+func (q *PollQuestion) GetMedia() *Media {
+	return q.Media
+}
+
+func (q *PollQuestion) Render() {
+	q.RenderedTitle = RenderText(q.Format, q.Title)
+	q.RenderedOptions = renderTexts(q.Format, q.Options)
+}
+
+func (q *PollQuestion) GetFormat() TextFormat {
+	return q.Format
+}
+
+func (q *PollQuestion) GetRenderedTitle() string {
+	return q.RenderedTitle
+}
+
+// IsCorrect is always false, polls are ungraded.
+func (q *PollQuestion) IsCorrect(answer any) bool {
+	return false
+}
+
+func (q *PollQuestion) GetPoints() Points {
+	return 0
+}
+
+func (q *PollQuestion) GetTimeLimit() TimeLimit {
+	return q.TimeLimit
+}
+
+func (q *PollQuestion) Clone() Question {
+	clone := *q
+	clone.Id = uuid.New()
+	clone.Media = q.Media.Clone()
+	clone.Metadata = q.Metadata.Clone()
+	clone.Options = make([]string, len(q.Options))
+	copy(clone.Options, q.Options)
+	clone.RenderedOptions = slices.Clone(q.RenderedOptions)
+
+	return &clone
+}
+
+// present shows the options in the order they are kept, their index being
+// the answer.
+func (q *PollQuestion) present() Presentation {
+	return Presentation{
+		Choices: choicesOf(q.Format, q.Options, q.RenderedOptions),
+		Mode:    SingleChoiceQuizMode,
+	}
+}
+
+// decodeAnswer accepts the index of the picked option.
+func (q *PollQuestion) decodeAnswer(raw json.RawMessage) (any, error) {
+	var chosen int
+	err := json.Unmarshal(raw, &chosen)
+	if err != nil || chosen < 0 || chosen >= len(q.Options) {
+		return nil, ErrInvalidAnswer
+	}
+
+	return chosen, nil
+}
+
+// Tally counts how many answers, the indices of the picked options, went to
+// each option. Every option is present, in the order they are shown, even if
+// no one picked it.
+func (q *PollQuestion) Tally(answers []any) []Tally {
+	tallies := make([]Tally, len(q.Options))
+	for i, option := range q.Options {
+		tallies[i].Value = option
+	}
+
+	for _, answer := range answers {
+		picked, ok := answer.(int)
+		if !ok || picked < 0 || picked >= len(q.Options) {
+			continue
+		}
+		tallies[picked].Count++
+	}
+
+	return tallies
+}
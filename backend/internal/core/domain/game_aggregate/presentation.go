@@ -0,0 +1,85 @@
+package game
+
+// Choice is one of the texts a question is answered with, by picking or
+// arranging them.
+type Choice struct {
+	Text   string
+	Format TextFormat
+	// Rendered is the text as sanitized HTML, empty for text saved before it
+	// had a format
+	Rendered string
+	Media    *Media
+	// Correct tells whether the choice is a right one to pick
+	Correct bool
+}
+
+// RenderedOrDefault returns the render of the choice, rendering its text when
+// it has none.
+func (c Choice) RenderedOrDefault() string {
+	if c.Rendered == "" {
+		return RenderText(c.Format, c.Text)
+	}
+
+	return c.Rendered
+}
+
+// choicesOf turns texts written in the same format, and their render if
+// any, into choices.
+func choicesOf(format TextFormat, texts []string, rendered []string) []Choice {
+	choices := make([]Choice, len(texts))
+	for i, text := range texts {
+		choices[i] = Choice{Text: text, Format: format}
+		if i < len(rendered) {
+			choices[i].Rendered = rendered[i]
+		}
+	}
+
+	return choices
+}
+
+// Range is where the answers to questions answered with a number lie.
+type Range struct {
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+// Presentation is how a question is answered, besides what its header tells.
+type Presentation struct {
+	// Choices are picked or arranged by players, in the order they are kept
+	Choices []Choice
+	// Mode is how many of the choices may be picked, empty when they are
+	// arranged instead
+	Mode QuizMode
+	// Credit is how answers picking some of the right choices are scored,
+	// empty when they are all or nothing
+	Credit CreditPolicy
+	// Shuffle tells whether the choices must be shown in a random order, as
+	// their order would give the answer away
+	Shuffle bool
+	// Range is set for questions answered with a number
+	Range *Range
+}
+
+// Present describes how the question is answered. Kinds without a Present
+// hook are answered with nothing more than their header tells.
+func Present(question Question) Presentation {
+	spec, err := LookupKind(question.Kind())
+	if err != nil || spec.Present == nil {
+		return Presentation{}
+	}
+
+	return spec.Present(question)
+}
+
+// presenter adapts a function presenting questions of type Q to any question.
+func presenter[Q Question](present func(question Q) Presentation) func(Question) Presentation {
+	return func(question Question) Presentation {
+		q, ok := question.(Q)
+		if !ok {
+			return Presentation{}
+		}
+
+		return present(q)
+	}
+}
@@ -3,9 +3,49 @@ package game
 type Points int
 type TimeLimit int64
 
+// FullCredit is the credit, in percent, of an entirely right answer
+const FullCredit = 100
+
 type Question interface {
+	Kind() Kind
 	GetTitle() string
+	// GetMedia is the media shown along the title, if any
+	GetMedia() *Media
 	IsCorrect(answer any) bool
 	GetPoints() Points
 	GetTimeLimit() TimeLimit
+	// Clone deep copies the question, giving the copy a fresh id
+	Clone() Question
+	// GetMetadata is the tags, category and difficulty of the question
+	GetMetadata() *Metadata
+}
+
+// PartialCreditQuestion is implemented by questions whose answers can be
+// partly right.
+type PartialCreditQuestion interface {
+	// PartialCredit is the percentage, from 0 to FullCredit, of the points
+	// the answer earns
+	PartialCredit(answer any) int
+}
+
+// UngradedQuestion is implemented by questions that ask for an opinion rather
+// than a right answer, e.g. polls. They are worth no points, IsCorrect is
+// always false for them and their answers are tallied instead of scored.
+type UngradedQuestion interface {
+	// Tally aggregates the answers given to the question into how many
+	// players gave each of them
+	Tally(answers []any) []Tally
+}
+
+// Tally is how many players gave the same answer to an ungraded question.
+type Tally struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// IsGraded tells whether the answers to the question are right or wrong, as
+// opposed to ungraded questions where IsCorrect doesn't mean anything.
+func IsGraded(question Question) bool {
+	_, ungraded := question.(UngradedQuestion)
+	return !ungraded
 }
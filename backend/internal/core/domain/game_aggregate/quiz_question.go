@@ -1,48 +1,193 @@
 package game
 
 import (
+	"encoding/json"
 	"reflect"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
+const QuizKind Kind = "quiz"
+
+func init() {
+	RegisterKind(KindSpec{
+		Kind: QuizKind,
+		New: func(header Header) Question {
+			return &QuizQuestion{
+				Id:            header.Id,
+				Title:         header.Title,
+				Points:        header.Points,
+				TimeLimit:     header.TimeLimit,
+				Media:         header.Media,
+				Format:        header.Format,
+				RenderedTitle: header.RenderedTitle,
+				Metadata:      header.Metadata,
+				Alternatives:  []Alternative{},
+			}
+		},
+		DecodeAnswer: answerDecoder((*QuizQuestion).decodeAnswer),
+		Present:      presenter((*QuizQuestion).present),
+		Validations: map[string]validator.Func{
+			"correctalternatives": ValidateCorrectAlternatives,
+		},
+	})
+}
+
+// QuizMode tells how many alternatives of a quiz question a player may pick.
+type QuizMode string
+
+const (
+	// SingleChoiceQuizMode questions have exactly one correct alternative and
+	// players pick one
+	SingleChoiceQuizMode QuizMode = "single"
+	// MultiSelectQuizMode questions may have many correct alternatives and
+	// players pick any amount of them
+	MultiSelectQuizMode QuizMode = "multiple"
+)
+
+// CreditPolicy tells how much of the points a partly right answer to a
+// multi-select quiz question earns.
+type CreditPolicy string
+
+const (
+	// AllOrNothingCredit only rewards picking exactly the correct alternatives
+	AllOrNothingCredit CreditPolicy = "all_or_nothing"
+	// ProportionalCredit rewards the share of alternatives rightly picked or
+	// rightly left out
+	ProportionalCredit CreditPolicy = "proportional"
+	// PenaltyCredit rewards every correct pick and takes as much away for
+	// every wrong one, never going below zero
+	PenaltyCredit CreditPolicy = "penalty"
+)
+
 type QuizQuestion struct {
-	Id           uuid.UUID     `validate:"omitempty"`
-	Title        string        `validate:"required,gte=1,lte=120"`
-	Points       Points        `validate:"required,gte=0,lte=2"`
-	TimeLimit    TimeLimit     `validate:"required,gte=5,lte=180"`
-	Alternatives []Alternative `validate:"required,min=3,atleastonecorrect,dive,required"`
+	Metadata
+	Id            uuid.UUID     `validate:"omitempty"`
+	Title         string        `validate:"required,lte=1000,markup,visiblelte=120"`
+	Points        Points        `validate:"required,gte=0,lte=2"`
+	TimeLimit     TimeLimit     `validate:"required,gte=5,lte=180"`
+	Media         *Media        `validate:"omitempty"`
+	Format        TextFormat    `validate:"omitempty,oneof=plain markdown markdown_latex"`
+	RenderedTitle string        `validate:"omitempty"`
+	Mode          QuizMode      `validate:"omitempty,oneof=single multiple"`
+	Credit        CreditPolicy  `validate:"omitempty,oneof=all_or_nothing proportional penalty"`
+	Alternatives  []Alternative `validate:"required,min=3,correctalternatives,dive,required"`
 }
 
 type Alternative struct {
-	Data      string `validate:"required,gte=1,lte=120"`
-	IsCorrect bool   `validate:"omitempty"`
+	Data      string     `validate:"required,lte=1000,markup,visiblelte=120"`
+	IsCorrect bool       `validate:"omitempty"`
+	Media     *Media     `validate:"omitempty"`
+	Format    TextFormat `validate:"omitempty,oneof=plain markdown markdown_latex"`
+	// Rendered is the data as sanitized HTML
+	Rendered string `validate:"omitempty"`
+}
+
+func (q *QuizQuestion) Kind() Kind {
+	return QuizKind
 }
 
 func (q *QuizQuestion) GetTitle() string {
 	return q.Title
 }
 
-func (q *QuizQuestion) IsCorrect(answer any) bool {
-	answerAlternatives, ok := answer.([]*Alternative)
-	if !ok {
-		return false
+func (q *QuizQuestion) GetMedia() *Media {
+	return q.Media
+}
+
+func (q *QuizQuestion) Render() {
+	q.RenderedTitle = RenderText(q.Format, q.Title)
+	for i := range q.Alternatives {
+		q.Alternatives[i].Rendered = RenderText(q.Alternatives[i].Format, q.Alternatives[i].Data)
 	}
+}
 
-	if len(answerAlternatives) != len(q.Alternatives) {
-		return false
+func (q *QuizQuestion) GetFormat() TextFormat {
+	return q.Format
+}
+
+func (q *QuizQuestion) GetRenderedTitle() string {
+	return q.RenderedTitle
+}
+
+// ModeOrDefault returns the mode of the question, questions are multi-select
+// unless told otherwise.
+func (q *QuizQuestion) ModeOrDefault() QuizMode {
+	if q.Mode == "" {
+		return MultiSelectQuizMode
+	}
+
+	return q.Mode
+}
+
+// CreditOrDefault returns the credit policy of the question, defaulting to
+// all-or-nothing.
+func (q *QuizQuestion) CreditOrDefault() CreditPolicy {
+	if q.Credit == "" {
+		return AllOrNothingCredit
+	}
+
+	return q.Credit
+}
+
+// IsCorrect tells whether the answer, the indices of the picked
+// alternatives, earns full credit.
+func (q *QuizQuestion) IsCorrect(answer any) bool {
+	return q.PartialCredit(answer) == FullCredit
+}
+
+// PartialCredit grades the answer, the indices of the picked alternatives,
+// following the mode and credit policy of the question.
+func (q *QuizQuestion) PartialCredit(answer any) int {
+	picked, ok := pickedAlternatives(answer, len(q.Alternatives))
+	if !ok || len(picked) == 0 {
+		return 0
 	}
 
-	for i, correctAlternative := range q.Alternatives {
-		candidateAlternative := answerAlternatives[i]
+	if q.ModeOrDefault() == SingleChoiceQuizMode {
+		if len(picked) != 1 {
+			return 0
+		}
+		for i := range picked {
+			if q.Alternatives[i].IsCorrect {
+				return FullCredit
+			}
+		}
+		return 0
+	}
 
-		if candidateAlternative.IsCorrect != correctAlternative.IsCorrect {
-			return false
+	correct, hits, misses := 0, 0, 0
+	for i, alternative := range q.Alternatives {
+		if alternative.IsCorrect {
+			correct++
+		}
+		if picked[i] {
+			if alternative.IsCorrect {
+				hits++
+			} else {
+				misses++
+			}
 		}
 	}
 
-	return true
+	if correct == 0 {
+		return 0
+	}
+
+	switch q.CreditOrDefault() {
+	case ProportionalCredit:
+		// correct picks plus wrong alternatives left out
+		right := hits + len(q.Alternatives) - correct - misses
+		return right * FullCredit / len(q.Alternatives)
+	case PenaltyCredit:
+		return max(hits-misses, 0) * FullCredit / correct
+	default:
+		if hits == correct && misses == 0 {
+			return FullCredit
+		}
+		return 0
+	}
 }
 
 func (q *QuizQuestion) GetPoints() Points {
@@ -53,20 +198,125 @@ func (q *QuizQuestion) GetTimeLimit() TimeLimit {
 	return q.TimeLimit
 }
 
-func ValidateAtLeastOneCorrect(fl validator.FieldLevel) bool {
+func (q *QuizQuestion) Clone() Question {
+	clone := *q
+	clone.Id = uuid.New()
+	clone.Media = q.Media.Clone()
+	clone.Metadata = q.Metadata.Clone()
+	clone.Alternatives = make([]Alternative, len(q.Alternatives))
+	for i, alternative := range q.Alternatives {
+		alternative.Media = alternative.Media.Clone()
+		clone.Alternatives[i] = alternative
+	}
+
+	return &clone
+}
+
+// AlternativesMedia lists the media of the alternatives that have one.
+func (q *QuizQuestion) AlternativesMedia() []*Media {
+	media := make([]*Media, 0)
+	for _, alternative := range q.Alternatives {
+		if alternative.Media != nil {
+			media = append(media, alternative.Media)
+		}
+	}
+
+	return media
+}
+
+// pickedAlternatives reads an answer made of alternative indices, either a
+// single index or a slice of them. Answers with an index outside of the
+// alternatives aren't valid.
+func pickedAlternatives(answer any, alternatives int) (map[int]bool, bool) {
+	var indices []int
+
+	switch a := answer.(type) {
+	case int:
+		indices = []int{a}
+	case []int:
+		indices = a
+	default:
+		return nil, false
+	}
+
+	picked := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		if i < 0 || i >= alternatives {
+			return nil, false
+		}
+		picked[i] = true
+	}
+
+	return picked, true
+}
+
+// present shows the alternatives in the order they are kept, their index
+// being the answer.
+func (q *QuizQuestion) present() Presentation {
+	choices := make([]Choice, len(q.Alternatives))
+	for i, a := range q.Alternatives {
+		choices[i] = Choice{
+			Text:     a.Data,
+			Format:   a.Format,
+			Rendered: a.Rendered,
+			Media:    a.Media,
+			Correct:  a.IsCorrect,
+		}
+	}
+
+	return Presentation{Choices: choices, Mode: q.ModeOrDefault(), Credit: q.CreditOrDefault()}
+}
+
+// decodeAnswer accepts the index of one alternative or the indices of many.
+func (q *QuizQuestion) decodeAnswer(raw json.RawMessage) (any, error) {
+	var chosen []int
+	err := json.Unmarshal(raw, &chosen)
+	if err != nil {
+		var single int
+		if json.Unmarshal(raw, &single) != nil {
+			return nil, ErrInvalidAnswer
+		}
+		chosen = []int{single}
+	}
+
+	for _, i := range chosen {
+		if i < 0 || i >= len(q.Alternatives) {
+			return nil, ErrInvalidAnswer
+		}
+	}
+
+	return chosen, nil
+}
+
+// ValidateCorrectAlternatives makes sure a quiz question has a correct
+// alternative, and only one when it is single-choice.
+func ValidateCorrectAlternatives(fl validator.FieldLevel) bool {
 	if fl.Field().Kind() != reflect.Slice {
 		return false
 	}
 
-	alternatives := fl.Field().Interface()
-	slice := reflect.ValueOf(alternatives)
+	alternatives, ok := fl.Field().Interface().([]Alternative)
+	if !ok {
+		return false
+	}
 
-	for i := 0; i < slice.Len(); i++ {
-		alt := slice.Index(i).Interface().(Alternative)
+	correct := 0
+	for _, alt := range alternatives {
 		if alt.IsCorrect {
-			return true
+			correct++
 		}
 	}
 
-	return false
+	mode := MultiSelectQuizMode
+	if parent := reflect.Indirect(fl.Parent()); parent.Kind() == reflect.Struct {
+		if question, ok := parent.Interface().(QuizQuestion); ok {
+			mode = question.ModeOrDefault()
+		}
+	}
+
+	if mode == SingleChoiceQuizMode {
+		return correct == 1
+	}
+
+	return correct >= 1
 }
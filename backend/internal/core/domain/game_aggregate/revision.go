@@ -0,0 +1,104 @@
+package game
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Revision is an immutable record of a game right after one change to its
+// info or questions.
+type Revision struct {
+	// Number counts the changes made to a game, starting at 1
+	Number    int       `json:"number"`
+	GameId    uuid.UUID `json:"game_id"`
+	AuthorId  string    `json:"author_id"`
+	CreatedAt time.Time `json:"created_at"`
+	// Summary lists what changed since the previous revision
+	Summary []string `json:"summary"`
+	// Game is the whole definition at this revision, it is only loaded when a
+	// single revision is requested
+	Game *Game `json:"game,omitempty"`
+}
+
+// NewRevision records after as changed by authorId, summarizing how it
+// differs from before. A nil before means the game was just created.
+func NewRevision(authorId string, before, after *Game, createdAt time.Time) *Revision {
+	return &Revision{
+		GameId:    after.Id,
+		AuthorId:  authorId,
+		CreatedAt: createdAt,
+		Summary:   Diff(before, after),
+		Game:      after,
+	}
+}
+
+// Diff describes, one line per change, how after differs from before.
+func Diff(before, after *Game) []string {
+	if before == nil {
+		return []string{"game created"}
+	}
+
+	changes := make([]string, 0)
+
+	if before.Title != after.Title {
+		changes = append(changes, fmt.Sprintf("title changed to %q", after.Title))
+	}
+	if before.Description != after.Description {
+		changes = append(changes, "description changed")
+	}
+	if before.VisibilityOrDefault() != after.VisibilityOrDefault() {
+		changes = append(changes, fmt.Sprintf("visibility changed to %s", after.VisibilityOrDefault()))
+	}
+	if *before.ScoringPolicyOrDefault() != *after.ScoringPolicyOrDefault() {
+		changes = append(changes, "scoring policy changed")
+	}
+	if !slices.Equal(before.Tags, after.Tags) {
+		changes = append(changes, "tags changed")
+	}
+	if before.Category != after.Category {
+		changes = append(changes, fmt.Sprintf("category changed to %q", after.Category))
+	}
+	if before.Difficulty != after.Difficulty {
+		changes = append(changes, fmt.Sprintf("difficulty changed to %q", after.Difficulty))
+	}
+
+	for i := 0; i < len(before.Questions) || i < len(after.Questions); i++ {
+		switch {
+		case i >= len(before.Questions):
+			changes = append(changes, fmt.Sprintf("question %d added", i+1))
+		case i >= len(after.Questions):
+			changes = append(changes, fmt.Sprintf("question %d removed", i+1))
+		case !sameQuestion(before.Questions[i], after.Questions[i]):
+			changes = append(changes, fmt.Sprintf("question %d changed", i+1))
+		}
+	}
+
+	return changes
+}
+
+// sameQuestion compares two questions ignoring their ids, which change every
+// time questions are stored.
+func sameQuestion(a, b Question) bool {
+	return reflect.DeepEqual(withoutId(a), withoutId(b))
+}
+
+func withoutId(q Question) any {
+	v := reflect.Indirect(reflect.ValueOf(q))
+	if v.Kind() != reflect.Struct {
+		return q
+	}
+
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+
+	id := c.FieldByName("Id")
+	if id.IsValid() && id.CanSet() {
+		id.Set(reflect.Zero(id.Type()))
+	}
+
+	return c.Interface()
+}
@@ -0,0 +1,33 @@
+package game
+
+// ScoringPolicy configures how answers to a game are turned into points.
+type ScoringPolicy struct {
+	// BasePoints awarded to a correct answer of a question worth 1 point
+	BasePoints int `json:"base_points"      validate:"gte=0,lte=10000"`
+	// SpeedWeight is the percentage of the base points that depends on how
+	// fast the player answered, the remainder is awarded to any correct answer
+	SpeedWeight int `json:"speed_weight"     validate:"gte=0,lte=100"`
+	// StreakBonus is added for every consecutive correct answer after the first
+	StreakBonus int `json:"streak_bonus"     validate:"gte=0,lte=1000"`
+	// MaxStreakBonus caps the bonus awarded for a single answer
+	MaxStreakBonus int `json:"max_streak_bonus" validate:"gte=0,lte=10000"`
+}
+
+func DefaultScoringPolicy() *ScoringPolicy {
+	return &ScoringPolicy{
+		BasePoints:     1000,
+		SpeedWeight:    50,
+		StreakBonus:    100,
+		MaxStreakBonus: 500,
+	}
+}
+
+// ScoringPolicyOrDefault returns the policy configured for the game, falling
+// back to DefaultScoringPolicy when there is none.
+func (g *Game) ScoringPolicyOrDefault() *ScoringPolicy {
+	if g.Scoring == nil {
+		return DefaultScoringPolicy()
+	}
+
+	return g.Scoring
+}
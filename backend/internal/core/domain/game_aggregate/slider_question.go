@@ -0,0 +1,175 @@
+package game
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+const SliderKind Kind = "slider"
+
+func init() {
+	RegisterKind(KindSpec{
+		Kind: SliderKind,
+		New: func(header Header) Question {
+			return &SliderQuestion{
+				Id:            header.Id,
+				Title:         header.Title,
+				Points:        header.Points,
+				TimeLimit:     header.TimeLimit,
+				Media:         header.Media,
+				Format:        header.Format,
+				RenderedTitle: header.RenderedTitle,
+				Metadata:      header.Metadata,
+			}
+		},
+		DecodeAnswer: answerDecoder((*SliderQuestion).decodeAnswer),
+		Present:      presenter((*SliderQuestion).present),
+	})
+}
+
+// stepEpsilon absorbs the floating point error of answers made of steps
+const stepEpsilon = 1e-9
+
+// SliderQuestion is answered by picking a number between Min and Max in
+// increments of Step, e.g. to estimate a quantity.
+type SliderQuestion struct {
+	Metadata
+	Id            uuid.UUID  `validate:"omitempty"`
+	Title         string     `validate:"required,lte=1000,markup,visiblelte=120"`
+	Points        Points     `validate:"required,gte=0,lte=2"`
+	TimeLimit     TimeLimit  `validate:"required,gte=5,lte=180"`
+	Media         *Media     `validate:"omitempty"`
+	Format        TextFormat `validate:"omitempty,oneof=plain markdown markdown_latex"`
+	RenderedTitle string     `validate:"omitempty"`
+	Min           float64    `validate:"ltfield=Max"`
+	Max           float64    `validate:"gtfield=Min"`
+	Step          float64    `validate:"gt=0"`
+	// Target is the right answer
+	Target float64 `validate:"gtefield=Min,ltefield=Max"`
+	// Tolerance is how far from the target an answer may be and still be
+	// right
+	Tolerance float64 `validate:"gte=0"`
+	// ProportionalScoring rewards answers outside of the tolerance by how
+	// close they are to the target
+	ProportionalScoring bool `validate:"omitempty"`
+}
+
+func (q *SliderQuestion) Kind() Kind {
+	return SliderKind
+}
+
+func (q *SliderQuestion) GetTitle() string {
+	return q.Title
+}
+
+func (q *SliderQuestion) GetMedia() *Media {
+	return q.Media
+}
+
+func (q *SliderQuestion) Render() {
+	q.RenderedTitle = RenderText(q.Format, q.Title)
+}
+
+func (q *SliderQuestion) GetFormat() TextFormat {
+	return q.Format
+}
+
+func (q *SliderQuestion) GetRenderedTitle() string {
+	return q.RenderedTitle
+}
+
+// IsCorrect tells whether the answer, a number, is within the tolerance of
+// the target.
+func (q *SliderQuestion) IsCorrect(answer any) bool {
+	return q.PartialCredit(answer) == FullCredit
+}
+
+// PartialCredit grades the answer, a number, giving full credit within the
+// tolerance of the target. With proportional scoring the credit then falls
+// linearly, down to nothing at the farthest end of the slider.
+func (q *SliderQuestion) PartialCredit(answer any) int {
+	value, ok := q.pickedValue(answer)
+	if !ok {
+		return 0
+	}
+
+	distance := math.Abs(value - q.Target)
+	if distance <= q.Tolerance+stepEpsilon {
+		return FullCredit
+	}
+
+	if !q.ProportionalScoring {
+		return 0
+	}
+
+	farthest := math.Max(q.Target-q.Min, q.Max-q.Target)
+	if farthest <= q.Tolerance {
+		return 0
+	}
+
+	share := (farthest - distance) / (farthest - q.Tolerance)
+	return max(int(math.Floor(share*FullCredit)), 0)
+}
+
+func (q *SliderQuestion) GetPoints() Points {
+	return q.Points
+}
+
+func (q *SliderQuestion) GetTimeLimit() TimeLimit {
+	return q.TimeLimit
+}
+
+func (q *SliderQuestion) Clone() Question {
+	clone := *q
+	clone.Id = uuid.New()
+	clone.Media = q.Media.Clone()
+	clone.Metadata = q.Metadata.Clone()
+
+	return &clone
+}
+
+func (q *SliderQuestion) present() Presentation {
+	return Presentation{Range: &Range{Min: q.Min, Max: q.Max, Step: q.Step}}
+}
+
+// decodeAnswer accepts the picked number, whether it is on the slider is left
+// to scoring.
+func (q *SliderQuestion) decodeAnswer(raw json.RawMessage) (any, error) {
+	var picked float64
+	err := json.Unmarshal(raw, &picked)
+	if err != nil {
+		return nil, ErrInvalidAnswer
+	}
+
+	return picked, nil
+}
+
+// pickedValue reads the number answered, which must be on the slider, i.e.
+// between Min and Max and a whole amount of steps away from Min.
+func (q *SliderQuestion) pickedValue(answer any) (float64, bool) {
+	var value float64
+
+	switch a := answer.(type) {
+	case float64:
+		value = a
+	case int:
+		value = float64(a)
+	default:
+		return 0, false
+	}
+
+	if math.IsNaN(value) || value < q.Min-stepEpsilon || value > q.Max+stepEpsilon {
+		return 0, false
+	}
+
+	if q.Step > 0 {
+		steps := (value - q.Min) / q.Step
+		if math.Abs(steps-math.Round(steps)) > stepEpsilon*math.Max(1, steps) {
+			return 0, false
+		}
+	}
+
+	return value, true
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"time"
+)
+
+// Status tells whether a game is still being written or ready to be played.
+type Status string
+
+const (
+	// DraftStatus games may be incomplete and can't be played
+	DraftStatus Status = "draft"
+	// PublishedStatus games passed every validation rule and their snapshot
+	// is what sessions play
+	PublishedStatus Status = "published"
+)
+
+// StatusOrDefault returns the status of the game, games are drafts unless
+// told otherwise.
+func (g *Game) StatusOrDefault() Status {
+	if g.Status == "" {
+		return DraftStatus
+	}
+
+	return g.Status
+}
+
+// Snapshot freezes the game as published at the given time. The snapshot
+// shares nothing with the game, so later edits don't change it.
+func (g *Game) Snapshot(publishedAt time.Time) *Game {
+	questions := make([]Question, len(g.Questions))
+	for i, q := range g.Questions {
+		questions[i] = q.Clone()
+	}
+
+	snapshot := *g
+	snapshot.Questions = questions
+	snapshot.Metadata = g.Metadata.Clone()
+	snapshot.Status = PublishedStatus
+	snapshot.PublishedAt = &publishedAt
+	if g.Scoring != nil {
+		policy := *g.Scoring
+		snapshot.Scoring = &policy
+	}
+
+	return &snapshot
+}
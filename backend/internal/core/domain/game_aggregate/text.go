@@ -0,0 +1,357 @@
+package game
+
+import (
+	"errors"
+	"html"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// TextFormat tells how the text of a question, i.e. its title or the data of
+// its alternatives, is written.
+type TextFormat string
+
+const (
+	// PlainText is shown as is, the default
+	PlainText TextFormat = "plain"
+	// MarkdownText supports **strong**, *emphasis*, ~~deleted~~, `code`,
+	// ^superscript^, ~subscript~, line breaks, and the <sub>, <sup> and <br>
+	// HTML tags
+	MarkdownText TextFormat = "markdown"
+	// MarkdownLatexText is MarkdownText with inline LaTeX between dollar
+	// signs, e.g. $\frac{1}{2}$, typeset by clients
+	MarkdownLatexText TextFormat = "markdown_latex"
+)
+
+var (
+	ErrDisallowedHTML = errors.New("Only the <sub>, <sup> and <br> HTML tags are allowed")
+	ErrInvalidLatex   = errors.New("Invalid LaTeX")
+)
+
+// TextValidations are the custom validation tags of formatted text, shared by
+// every kind. The format is read from the Format field next to the text.
+var TextValidations = map[string]validator.Func{
+	// markup rejects text that can't be rendered in its format
+	"markup": validateMarkup,
+	// visiblelte limits the length, in runes, of the text readers see
+	"visiblelte": validateVisibleLength,
+}
+
+// RenderedQuestion is implemented by questions with formatted text.
+type RenderedQuestion interface {
+	// Render fills in the rendered HTML of the text of the question from its
+	// source
+	Render()
+	GetFormat() TextFormat
+	GetRenderedTitle() string
+}
+
+// Render renders the text of every question of the game.
+func (g *Game) Render() {
+	for _, q := range g.Questions {
+		if rendered, ok := q.(RenderedQuestion); ok {
+			rendered.Render()
+		}
+	}
+}
+
+// RenderText turns the source into HTML safe to show as is. Markup that
+// isn't allowed is escaped, CheckText tells whether there is any.
+func RenderText(format TextFormat, source string) string {
+	out, _ := renderText(format, source)
+	return out
+}
+
+// renderTexts renders every one of the sources, written in the same format.
+func renderTexts(format TextFormat, sources []string) []string {
+	rendered := make([]string, len(sources))
+	for i, source := range sources {
+		rendered[i] = RenderText(format, source)
+	}
+
+	return rendered
+}
+
+// CheckText fails with ErrDisallowedHTML or ErrInvalidLatex when the source
+// has markup that isn't allowed in its format.
+func CheckText(format TextFormat, source string) error {
+	_, err := renderText(format, source)
+	return err
+}
+
+// VisibleText is what readers see of the source once rendered, i.e. without
+// markup. Formulas count as their LaTeX source.
+func VisibleText(format TextFormat, source string) string {
+	if format == "" || format == PlainText {
+		return source
+	}
+
+	out := strings.ReplaceAll(RenderText(format, source), "<br>", "\n")
+	return html.UnescapeString(htmlTagPattern.ReplaceAllString(out, ""))
+}
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<[^>]*>`)
+	allowedTagPattern = regexp.MustCompile(`^<(/?)(sub|sup)>|^<br\s*/?>`)
+	latexCommand      = regexp.MustCompile(`\\([a-zA-Z]+)`)
+)
+
+// disallowedLatexCommands could load files, define macros or link elsewhere
+// when typeset.
+var disallowedLatexCommands = map[string]bool{
+	"href":            true,
+	"url":             true,
+	"includegraphics": true,
+	"input":           true,
+	"include":         true,
+	"def":             true,
+	"edef":            true,
+	"gdef":            true,
+	"let":             true,
+	"newcommand":      true,
+	"renewcommand":    true,
+	"providecommand":  true,
+	"write":           true,
+	"openin":          true,
+	"openout":         true,
+	"catcode":         true,
+	"htmlId":          true,
+	"htmlClass":       true,
+	"htmlStyle":       true,
+	"htmlData":        true,
+}
+
+// inlineMarkers are the Markdown delimiters, longest first so that ** isn't
+// read as two *.
+var inlineMarkers = []struct {
+	delimiter string
+	tag       string
+	// tight markers may not wrap whitespace, e.g. H~2~O
+	tight bool
+}{
+	{"**", "strong", false},
+	{"~~", "del", false},
+	{"*", "em", false},
+	{"^", "sup", true},
+	{"~", "sub", true},
+}
+
+type textRenderer struct {
+	math bool
+	err  error
+}
+
+func renderText(format TextFormat, source string) (string, error) {
+	switch format {
+	case "", PlainText:
+		return strings.ReplaceAll(html.EscapeString(source), "\n", "<br>"), nil
+	case MarkdownText, MarkdownLatexText:
+		r := &textRenderer{math: format == MarkdownLatexText}
+		out := r.render(source)
+		return out, r.err
+	default:
+		return html.EscapeString(source), nil
+	}
+}
+
+func (r *textRenderer) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *textRenderer) render(s string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(s); {
+		rest := s[i:]
+
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.IndexByte(`\*_~^$<>`+"`", rest[1]) >= 0:
+			out.WriteString(html.EscapeString(rest[1:2]))
+			i += 2
+			continue
+		case rest[0] == '\n':
+			out.WriteString("<br>")
+			i++
+			continue
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				out.WriteString("<code>" + html.EscapeString(rest[1:end+1]) + "</code>")
+				i += end + 2
+				continue
+			}
+		case rest[0] == '$' && r.math:
+			end := closingDollar(rest[1:])
+			if end < 0 {
+				r.fail(ErrInvalidLatex)
+				break
+			}
+			tex := rest[1 : end+1]
+			r.checkLatex(tex)
+			out.WriteString(`<span class="math">` + html.EscapeString(tex) + "</span>")
+			i += end + 2
+			continue
+		case rest[0] == '<':
+			if n, ok := r.renderTag(rest, &out); ok {
+				i += n
+				continue
+			}
+		}
+
+		if n, ok := r.renderMarker(rest, &out); ok {
+			i += n
+			continue
+		}
+
+		c, size := utf8.DecodeRuneInString(rest)
+		out.WriteString(html.EscapeString(string(c)))
+		i += size
+	}
+
+	return out.String()
+}
+
+// renderTag writes the allowed HTML tag at the start of s, along everything
+// up to its closing tag. Any other tag is escaped and reported.
+func (r *textRenderer) renderTag(s string, out *strings.Builder) (int, bool) {
+	match := allowedTagPattern.FindStringSubmatch(s)
+	if match == nil {
+		if len(s) > 1 && (isLetter(s[1]) || strings.IndexByte("/!?", s[1]) >= 0) {
+			r.fail(ErrDisallowedHTML)
+		}
+		return 0, false
+	}
+
+	if match[2] == "" {
+		out.WriteString("<br>")
+		return len(match[0]), true
+	}
+
+	closing := "</" + match[2] + ">"
+	end := strings.Index(s[len(match[0]):], closing)
+	if match[1] == "/" || end < 0 {
+		r.fail(ErrDisallowedHTML)
+		return 0, false
+	}
+
+	inner := s[len(match[0]) : len(match[0])+end]
+	out.WriteString("<" + match[2] + ">" + r.render(inner) + closing)
+	return len(match[0]) + end + len(closing), true
+}
+
+// renderMarker writes the text between the Markdown delimiter at the start
+// of s and the matching one. Delimiters without a match are left as text.
+func (r *textRenderer) renderMarker(s string, out *strings.Builder) (int, bool) {
+	for _, marker := range inlineMarkers {
+		if !strings.HasPrefix(s, marker.delimiter) {
+			continue
+		}
+
+		size := len(marker.delimiter)
+		end := strings.Index(s[size:], marker.delimiter)
+		if end <= 0 {
+			continue
+		}
+
+		inner := s[size : size+end]
+		if strings.TrimSpace(inner) != inner {
+			continue
+		}
+		if marker.tight && strings.IndexFunc(inner, unicode.IsSpace) >= 0 {
+			continue
+		}
+
+		out.WriteString("<" + marker.tag + ">" + r.render(inner) + "</" + marker.tag + ">")
+		return size + end + size, true
+	}
+
+	return 0, false
+}
+
+func (r *textRenderer) checkLatex(tex string) {
+	depth := 0
+	for i := 0; i < len(tex); i++ {
+		switch tex[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth < 0 {
+				r.fail(ErrInvalidLatex)
+				return
+			}
+		}
+	}
+	if depth != 0 || strings.TrimSpace(tex) == "" {
+		r.fail(ErrInvalidLatex)
+		return
+	}
+
+	for _, match := range latexCommand.FindAllStringSubmatch(tex, -1) {
+		if disallowedLatexCommands[match[1]] {
+			r.fail(ErrInvalidLatex)
+			return
+		}
+	}
+}
+
+// closingDollar returns the index of the first dollar sign of s that isn't
+// escaped, or -1.
+func closingDollar(s string) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '$':
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// textFormatOf reads the format of a field from the Format field of the
+// struct it belongs to, texts without one being plain.
+func textFormatOf(fl validator.FieldLevel) TextFormat {
+	parent := fl.Parent()
+	if parent.Kind() == reflect.Ptr {
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return PlainText
+	}
+
+	format := parent.FieldByName("Format")
+	if !format.IsValid() || format.Kind() != reflect.String {
+		return PlainText
+	}
+
+	return TextFormat(format.String())
+}
+
+func validateMarkup(fl validator.FieldLevel) bool {
+	return CheckText(textFormatOf(fl), fl.Field().String()) == nil
+}
+
+func validateVisibleLength(fl validator.FieldLevel) bool {
+	max, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		panic(err)
+	}
+
+	visible := VisibleText(textFormatOf(fl), fl.Field().String())
+	return utf8.RuneCountInString(visible) <= max
+}
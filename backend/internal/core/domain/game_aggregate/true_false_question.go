@@ -1,22 +1,79 @@
 package game
 
 import (
+	"encoding/json"
+
 	"github.com/google/uuid"
 )
 
+const TrueFalseKind Kind = "true_false"
+
+func init() {
+	RegisterKind(KindSpec{
+		Kind: TrueFalseKind,
+		New: func(header Header) Question {
+			return &TrueFalseQuestion{
+				Id:            header.Id,
+				Title:         header.Title,
+				Points:        header.Points,
+				TimeLimit:     header.TimeLimit,
+				Media:         header.Media,
+				Format:        header.Format,
+				RenderedTitle: header.RenderedTitle,
+				Metadata:      header.Metadata,
+			}
+		},
+		DecodeAnswer: answerDecoder((*TrueFalseQuestion).decodeAnswer),
+		Present:      presenter((*TrueFalseQuestion).present),
+	})
+}
+
 type TrueFalseQuestion struct {
-	Id               uuid.UUID `validate:"omitempty"`
-	Title            string    `validate:"required,gte=1,lte=120"`
-	Points           Points    `validate:"required,gte=0,lte=2"`
-	TimeLimit        TimeLimit `validate:"required,gte=5,lte=180"`
-	TrueAlternative  string    `validate:"required,gte=1,lte=120"`
-	FalseAlternative string    `validate:"required,gte=1,lte=120"`
+	Metadata
+	Id        uuid.UUID  `validate:"omitempty"`
+	Title     string     `validate:"required,lte=1000,markup,visiblelte=120"`
+	Points    Points     `validate:"required,gte=0,lte=2"`
+	TimeLimit TimeLimit  `validate:"required,gte=5,lte=180"`
+	Media     *Media     `validate:"omitempty"`
+	Format    TextFormat `validate:"omitempty,oneof=plain markdown markdown_latex"`
+	// RenderedTitle is the title as sanitized HTML
+	RenderedTitle string
+	// TrueAlternative and FalseAlternative are written in the format of the
+	// question, like its title
+	TrueAlternative  string `validate:"required,lte=1000,markup,visiblelte=120"`
+	FalseAlternative string `validate:"required,lte=1000,markup,visiblelte=120"`
+	// RenderedTrueAlternative and RenderedFalseAlternative are the
+	// alternatives as sanitized HTML
+	RenderedTrueAlternative  string `validate:"omitempty"`
+	RenderedFalseAlternative string `validate:"omitempty"`
+}
+
+func (t *TrueFalseQuestion) Kind() Kind {
+	return TrueFalseKind
 }
 
 func (t *TrueFalseQuestion) GetTitle() string {
 	return t.Title
 }
 
+func (t *TrueFalseQuestion) GetMedia() *Media {
+	return t.Media
+}
+
+func (t *TrueFalseQuestion) Render() {
+	t.RenderedTitle = RenderText(t.Format, t.Title)
+	t.RenderedTrueAlternative = RenderText(t.Format, t.TrueAlternative)
+	t.RenderedFalseAlternative = RenderText(t.Format, t.FalseAlternative)
+}
+
+func (t *TrueFalseQuestion) GetFormat() TextFormat {
+	return t.Format
+}
+
+func (t *TrueFalseQuestion) GetRenderedTitle() string {
+	return t.RenderedTitle
+}
+
 func (t *TrueFalseQuestion) IsCorrect(answer any) bool {
 	answerString, ok := answer.(string)
 	if !ok {
@@ -33,3 +90,44 @@ func (t *TrueFalseQuestion) GetPoints() Points {
 func (t *TrueFalseQuestion) GetTimeLimit() TimeLimit {
 	return t.TimeLimit
 }
+
+func (t *TrueFalseQuestion) Clone() Question {
+	clone := *t
+	clone.Id = uuid.New()
+	clone.Media = t.Media.Clone()
+	clone.Metadata = t.Metadata.Clone()
+
+	return &clone
+}
+
+// present lists the true alternative first, hence the shuffle.
+func (t *TrueFalseQuestion) present() Presentation {
+	return Presentation{
+		Choices: []Choice{
+			{
+				Text:     t.TrueAlternative,
+				Format:   t.Format,
+				Rendered: t.RenderedTrueAlternative,
+				Correct:  true,
+			},
+			{
+				Text:     t.FalseAlternative,
+				Format:   t.Format,
+				Rendered: t.RenderedFalseAlternative,
+			},
+		},
+		Mode:    SingleChoiceQuizMode,
+		Shuffle: true,
+	}
+}
+
+// decodeAnswer accepts the text of the chosen alternative.
+func (t *TrueFalseQuestion) decodeAnswer(raw json.RawMessage) (any, error) {
+	var chosen string
+	err := json.Unmarshal(raw, &chosen)
+	if err != nil {
+		return nil, ErrInvalidAnswer
+	}
+
+	return chosen, nil
+}
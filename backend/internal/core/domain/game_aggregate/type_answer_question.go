@@ -0,0 +1,251 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+)
+
+const TypeAnswerKind Kind = "type_answer"
+
+// MaxTypedAnswerLength bounds the text, in bytes, players may type so that
+// fuzzy matching stays cheap
+const MaxTypedAnswerLength = 200
+
+func init() {
+	RegisterKind(KindSpec{
+		Kind: TypeAnswerKind,
+		New: func(header Header) Question {
+			return &TypeAnswerQuestion{
+				Id:            header.Id,
+				Title:         header.Title,
+				Points:        header.Points,
+				TimeLimit:     header.TimeLimit,
+				Media:         header.Media,
+				Format:        header.Format,
+				RenderedTitle: header.RenderedTitle,
+				Metadata:      header.Metadata,
+			}
+		},
+		DecodeAnswer: answerDecoder((*TypeAnswerQuestion).decodeAnswer),
+		Validations: map[string]validator.Func{
+			"regexp": ValidateRegexp,
+		},
+	})
+}
+
+// TypeAnswerQuestion is answered by typing free text, which is accepted when
+// it matches one of the accepted answers or the pattern.
+type TypeAnswerQuestion struct {
+	Metadata
+	Id        uuid.UUID  `validate:"omitempty"`
+	Title     string     `validate:"required,lte=1000,markup,visiblelte=120"`
+	Points    Points     `validate:"required,gte=0,lte=2"`
+	TimeLimit TimeLimit  `validate:"required,gte=5,lte=180"`
+	Media     *Media     `validate:"omitempty"`
+	Format    TextFormat `validate:"omitempty,oneof=plain markdown markdown_latex"`
+	// RenderedTitle is the title as sanitized HTML
+	RenderedTitle   string
+	AcceptedAnswers []string `validate:"required_without=Pattern,dive,required,lte=120"`
+	// CaseSensitive answers must match the letter case of an accepted answer
+	CaseSensitive bool `validate:"omitempty"`
+	// AccentSensitive answers must match the accents of an accepted answer
+	AccentSensitive bool `validate:"omitempty"`
+	// Tolerance is how many edits, in Levenshtein distance, an answer may be
+	// away from an accepted answer
+	Tolerance int `validate:"gte=0,lte=5"`
+	// Pattern is a regular expression that the whole answer may match instead
+	Pattern string `validate:"omitempty,lte=200,regexp"`
+	// compiled caches the last compiledPattern, so that answers of a running
+	// session don't compile Pattern again
+	compiled atomic.Value
+}
+
+// compiledPattern is an expression matched by answers, compiled.
+type compiledPattern struct {
+	expr    string
+	pattern *regexp.Regexp
+}
+
+func (q *TypeAnswerQuestion) Kind() Kind {
+	return TypeAnswerKind
+}
+
+func (q *TypeAnswerQuestion) GetTitle() string {
+	return q.Title
+}
+
+func (q *TypeAnswerQuestion) GetMedia() *Media {
+	return q.Media
+}
+
+func (q *TypeAnswerQuestion) Render() {
+	q.RenderedTitle = RenderText(q.Format, q.Title)
+}
+
+func (q *TypeAnswerQuestion) GetFormat() TextFormat {
+	return q.Format
+}
+
+func (q *TypeAnswerQuestion) GetRenderedTitle() string {
+	return q.RenderedTitle
+}
+
+// IsCorrect tells whether the typed answer, a string, is accepted.
+func (q *TypeAnswerQuestion) IsCorrect(answer any) bool {
+	typed, ok := answer.(string)
+	if !ok {
+		return false
+	}
+
+	normalized := q.normalize(typed)
+	if normalized == "" {
+		return false
+	}
+
+	for _, accepted := range q.AcceptedAnswers {
+		if levenshtein(normalized, q.normalize(accepted)) <= q.Tolerance {
+			return true
+		}
+	}
+
+	if q.Pattern == "" {
+		return false
+	}
+
+	pattern := q.compilePattern()
+	if pattern == nil {
+		return false
+	}
+
+	return pattern.MatchString(q.foldAccents(strings.Join(strings.Fields(typed), " ")))
+}
+
+// compilePattern returns the expression whole answers must match, compiling
+// it only when Pattern, or the sensitivity of the question, changed since it
+// was last compiled. It is nil when Pattern doesn't compile.
+func (q *TypeAnswerQuestion) compilePattern() *regexp.Regexp {
+	// lowercasing could change the meaning of the pattern, e.g. \S, so case is
+	// ignored with a flag instead
+	flags := ""
+	if !q.CaseSensitive {
+		flags = "(?i)"
+	}
+	expr := flags + `^(?:` + q.foldAccents(q.Pattern) + `)$`
+
+	cached, ok := q.compiled.Load().(*compiledPattern)
+	if ok && cached.expr == expr {
+		return cached.pattern
+	}
+
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		pattern = nil
+	}
+	q.compiled.Store(&compiledPattern{expr: expr, pattern: pattern})
+
+	return pattern
+}
+
+func (q *TypeAnswerQuestion) GetPoints() Points {
+	return q.Points
+}
+
+func (q *TypeAnswerQuestion) GetTimeLimit() TimeLimit {
+	return q.TimeLimit
+}
+
+func (q *TypeAnswerQuestion) Clone() Question {
+	clone := *q
+	clone.compiled = atomic.Value{}
+	clone.Id = uuid.New()
+	clone.Media = q.Media.Clone()
+	clone.Metadata = q.Metadata.Clone()
+	clone.AcceptedAnswers = make([]string, len(q.AcceptedAnswers))
+	copy(clone.AcceptedAnswers, q.AcceptedAnswers)
+
+	return &clone
+}
+
+// decodeAnswer accepts the typed text.
+func (q *TypeAnswerQuestion) decodeAnswer(raw json.RawMessage) (any, error) {
+	var typed string
+	err := json.Unmarshal(raw, &typed)
+	if err != nil || len(typed) > MaxTypedAnswerLength {
+		return nil, ErrInvalidAnswer
+	}
+
+	return typed, nil
+}
+
+// normalize trims and collapses the spaces of text, dropping its case and
+// accents unless the question is sensitive to them.
+func (q *TypeAnswerQuestion) normalize(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+
+	if !q.CaseSensitive {
+		text = strings.ToLower(text)
+	}
+
+	return q.foldAccents(text)
+}
+
+// foldAccents drops the accents of text unless the question is sensitive to
+// them.
+func (q *TypeAnswerQuestion) foldAccents(text string) string {
+	if q.AccentSensitive {
+		return text
+	}
+
+	text = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, norm.NFD.String(text))
+
+	return norm.NFC.String(text)
+}
+
+// levenshtein counts the single rune insertions, deletions or substitutions
+// needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+// ValidateRegexp makes sure a string field holds a valid regular expression.
+func ValidateRegexp(fl validator.FieldLevel) bool {
+	if fl.Field().Kind() != reflect.String {
+		return false
+	}
+
+	_, err := regexp.Compile(fl.Field().String())
+	return err == nil
+}
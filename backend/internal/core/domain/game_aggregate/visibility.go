@@ -0,0 +1,28 @@
+package game
+
+// Visibility controls who besides the owner can see a game.
+type Visibility string
+
+const (
+	// PrivateVisibility games are only seen by their owner
+	PrivateVisibility Visibility = "private"
+	// UnlistedVisibility games are seen by anyone with their link
+	UnlistedVisibility Visibility = "unlisted"
+	// PublicVisibility games are also listed in the public catalogue
+	PublicVisibility Visibility = "public"
+)
+
+// IsShared tells whether people other than the owner can see the game.
+func (v Visibility) IsShared() bool {
+	return v == UnlistedVisibility || v == PublicVisibility
+}
+
+// VisibilityOrDefault returns the visibility of the game, games are private
+// unless told otherwise.
+func (g *Game) VisibilityOrDefault() Visibility {
+	if g.Visibility == "" {
+		return PrivateVisibility
+	}
+
+	return g.Visibility
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const WordCloudKind Kind = "word_cloud"
+
+func init() {
+	RegisterKind(KindSpec{
+		Kind: WordCloudKind,
+		New: func(header Header) Question {
+			return &WordCloudQuestion{
+				Id:            header.Id,
+				Title:         header.Title,
+				TimeLimit:     header.TimeLimit,
+				Media:         header.Media,
+				Format:        header.Format,
+				RenderedTitle: header.RenderedTitle,
+				Metadata:      header.Metadata,
+			}
+		},
+		DecodeAnswer: answerDecoder((*WordCloudQuestion).decodeAnswer),
+	})
+}
+
+// MaxWordCloudAnswerLength is the longest answer, in runes, a word cloud
+// question accepts
+const MaxWordCloudAnswerLength = 40
+
+// WordCloudQuestion asks players for a short free text answer, e.g. a word
+// describing something. There is no right answer, the answers are tallied by
+// how often they were given instead.
+type WordCloudQuestion struct {
+	Metadata
+	Id            uuid.UUID  `validate:"omitempty"`
+	Title         string     `validate:"required,lte=1000,markup,visiblelte=120"`
+	TimeLimit     TimeLimit  `validate:"required,gte=5,lte=180"`
+	Media         *Media     `validate:"omitempty"`
+	Format        TextFormat `validate:"omitempty,oneof=plain markdown markdown_latex"`
+	RenderedTitle string     `validate:"omitempty"`
+}
+
+func (q *WordCloudQuestion) Kind() Kind {
+	return WordCloudKind
+}
+
+func (q *WordCloudQuestion) GetTitle() string {
+	return q.Title
+}
+
+func (q *WordCloudQuestion) GetMedia() *Media {
+	return q.Media
+}
+
+func (q *WordCloudQuestion) Render() {
+	q.RenderedTitle = RenderText(q.Format, q.Title)
+}
+
+func (q *WordCloudQuestion) GetFormat() TextFormat {
+	return q.Format
+}
+
+func (q *WordCloudQuestion) GetRenderedTitle() string {
+	return q.RenderedTitle
+}
+
+// IsCorrect is always false, word clouds are ungraded.
+func (q *WordCloudQuestion) IsCorrect(answer any) bool {
+	return false
+}
+
+func (q *WordCloudQuestion) GetPoints() Points {
+	return 0
+}
+
+func (q *WordCloudQuestion) GetTimeLimit() TimeLimit {
+	return q.TimeLimit
+}
+
+func (q *WordCloudQuestion) Clone() Question {
+	clone := *q
+	clone.Id = uuid.New()
+	clone.Media = q.Media.Clone()
+	clone.Metadata = q.Metadata.Clone()
+
+	return &clone
+}
+
+// decodeAnswer accepts a few words, blank answers are rejected.
+func (q *WordCloudQuestion) decodeAnswer(raw json.RawMessage) (any, error) {
+	var typed string
+	err := json.Unmarshal(raw, &typed)
+	if err != nil || strings.TrimSpace(typed) == "" ||
+		utf8.RuneCountInString(typed) > MaxWordCloudAnswerLength {
+		return nil, ErrInvalidAnswer
+	}
+
+	return typed, nil
+}
+
+// Tally counts the answers, strings, ignoring letter case and extra spaces.
+// The most frequent answers come first, ties sorted alphabetically.
+func (q *WordCloudQuestion) Tally(answers []any) []Tally {
+	counts := make(map[string]int)
+	for _, answer := range answers {
+		text, ok := answer.(string)
+		if !ok {
+			continue
+		}
+
+		text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+		if text == "" {
+			continue
+		}
+		counts[text]++
+	}
+
+	tallies := make([]Tally, 0, len(counts))
+	for value, count := range counts {
+		tallies = append(tallies, Tally{Value: value, Count: count})
+	}
+
+	sort.Slice(tallies, func(i, j int) bool {
+		if tallies[i].Count != tallies[j].Count {
+			return tallies[i].Count > tallies[j].Count
+		}
+		return tallies[i].Value < tallies[j].Value
+	})
+
+	return tallies
+}
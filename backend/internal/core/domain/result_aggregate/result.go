@@ -0,0 +1,138 @@
+package result
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/domain/session_aggregate"
+)
+
+// Participant is a player of a finished session along with where they placed.
+type Participant struct {
+	Id       uuid.UUID `json:"id"`
+	Nickname string    `json:"nickname"`
+	Score    int       `json:"score"`
+	// Rank is the 1-based position of the player in the final leaderboard
+	Rank     int       `json:"rank"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// Answer is an answer submitted during a finished session. The question is
+// copied over so that it can still be read after the game is changed.
+type Answer struct {
+	PlayerId      uuid.UUID     `json:"player_id"`
+	QuestionIndex int           `json:"question_index"`
+	QuestionTitle string        `json:"question_title"`
+	Value         any           `json:"value"`
+	Correct       bool          `json:"correct"`
+	Ungraded      bool          `json:"ungraded"`
+	Points        int           `json:"points"`
+	ResponseTime  time.Duration `json:"response_time"`
+	AnsweredAt    time.Time     `json:"answered_at"`
+}
+
+// Distribution is how the answers to an ungraded question, e.g. a poll, were
+// spread among the players.
+type Distribution struct {
+	QuestionIndex int          `json:"question_index"`
+	QuestionTitle string       `json:"question_title"`
+	Tallies       []game.Tally `json:"tallies"`
+}
+
+// SessionResult is the outcome of a finished session. It only references the
+// game by id and keeps its own copy of everything else, since the game may be
+// edited or deleted afterwards.
+type SessionResult struct {
+	Id         uuid.UUID `json:"id"`
+	Pin        string    `json:"pin"`
+	GameId     uuid.UUID `json:"game_id"`
+	GameTitle  string    `json:"game_title"`
+	HostId     string    `json:"host_id"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Participants ordered by their final rank
+	Participants []*Participant `json:"participants"`
+	Answers      []*Answer      `json:"answers"`
+	// Distributions of the ungraded questions, in the order of the game
+	Distributions []*Distribution `json:"distributions"`
+}
+
+func NewSessionResult(sess *session.Session, finishedAt time.Time) *SessionResult {
+	leaderboard := sess.Leaderboard()
+	participants := make([]*Participant, len(leaderboard))
+	for i, p := range leaderboard {
+		participants[i] = &Participant{
+			Id:       p.Id,
+			Nickname: p.Nickname,
+			Score:    p.Score,
+			Rank:     i + 1,
+			JoinedAt: p.JoinedAt,
+		}
+	}
+
+	answers := make([]*Answer, len(sess.Answers))
+	for i, a := range sess.Answers {
+		answers[i] = &Answer{
+			PlayerId:      a.PlayerId,
+			QuestionIndex: a.Question,
+			QuestionTitle: sess.Game.Questions[a.Question].GetTitle(),
+			Value:         a.Value,
+			Correct:       a.Correct,
+			Ungraded:      a.Ungraded,
+			Points:        a.Points,
+			ResponseTime:  a.ResponseTime,
+			AnsweredAt:    a.AnsweredAt,
+		}
+	}
+
+	distributions := make([]*Distribution, 0)
+	for i, question := range sess.Game.Questions {
+		if game.IsGraded(question) {
+			continue
+		}
+
+		distributions = append(distributions, &Distribution{
+			QuestionIndex: i,
+			QuestionTitle: question.GetTitle(),
+			Tallies:       sess.Distribution(i),
+		})
+	}
+
+	return &SessionResult{
+		Id:            uuid.New(),
+		Pin:           sess.Pin,
+		GameId:        sess.Game.Id,
+		GameTitle:     sess.Game.Title,
+		HostId:        sess.HostId,
+		StartedAt:     sess.CreatedAt,
+		FinishedAt:    finishedAt,
+		Participants:  participants,
+		Answers:       answers,
+		Distributions: distributions,
+	}
+}
+
+func (r *SessionResult) FindParticipant(id uuid.UUID) *Participant {
+	for _, p := range r.Participants {
+		if p.Id == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// AnswersOf returns the answers submitted by a player, in the order they were
+// given.
+func (r *SessionResult) AnswersOf(playerId uuid.UUID) []*Answer {
+	answers := make([]*Answer, 0)
+	for _, a := range r.Answers {
+		if a.PlayerId == playerId {
+			answers = append(answers, a)
+		}
+	}
+
+	return answers
+}
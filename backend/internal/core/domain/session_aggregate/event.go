@@ -0,0 +1,24 @@
+package session
+
+import "github.com/google/uuid"
+
+type EventKind string
+
+const (
+	PlayerJoinedEvent    EventKind = "player_joined"
+	QuestionStartedEvent EventKind = "question_started"
+	AnswerAcceptedEvent  EventKind = "answer_accepted"
+	TimeUpEvent          EventKind = "time_up"
+	LeaderboardEvent     EventKind = "leaderboard"
+	GameOverEvent        EventKind = "game_over"
+)
+
+// Event describes something that happened to a running session. Session is
+// the state right after the event, and PlayerId is set when the event only
+// concerns a single player.
+type Event struct {
+	Kind     EventKind
+	Session  *Session
+	PlayerId uuid.UUID
+	Answer   *Answer
+}
@@ -0,0 +1,190 @@
+package session
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+type State string
+
+const (
+	LobbyState    State = "lobby"
+	QuestionState State = "question"
+	ResultsState  State = "results"
+	FinishedState State = "finished"
+)
+
+const NoQuestion = -1
+
+type Player struct {
+	Id       uuid.UUID `json:"id"`
+	Nickname string    `json:"nickname"`
+	// Token authenticates the player for the lifetime of the session.
+	Token string `json:"-"`
+	Score int    `json:"score"`
+	// Streak counts the consecutive correct answers of the player
+	Streak   int       `json:"streak"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type Answer struct {
+	PlayerId uuid.UUID `json:"player_id"`
+	Question int       `json:"question"`
+	Value    any       `json:"value"`
+	Correct  bool      `json:"correct"`
+	// Ungraded answers were given to a question without a right answer
+	Ungraded     bool          `json:"ungraded"`
+	Points       int           `json:"points"`
+	ResponseTime time.Duration `json:"response_time"`
+	AnsweredAt   time.Time     `json:"answered_at"`
+}
+
+type Session struct {
+	Pin               string     `json:"pin"`
+	HostId            string     `json:"host_id"`
+	Game              *game.Game `json:"-"`
+	State             State      `json:"state"`
+	CurrentQuestion   int        `json:"current_question"`
+	QuestionStartedAt time.Time  `json:"question_started_at"`
+	Players           []*Player  `json:"players"`
+	Answers           []*Answer  `json:"answers"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+func NewSession(pin, hostId string, g *game.Game, now time.Time) *Session {
+	return &Session{
+		Pin:             pin,
+		HostId:          hostId,
+		Game:            g,
+		State:           LobbyState,
+		CurrentQuestion: NoQuestion,
+		Players:         make([]*Player, 0),
+		Answers:         make([]*Answer, 0),
+		CreatedAt:       now,
+	}
+}
+
+func (s *Session) FindPlayer(id uuid.UUID) *Player {
+	for _, p := range s.Players {
+		if p.Id == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (s *Session) FindPlayerByToken(token string) *Player {
+	for _, p := range s.Players {
+		if p.Token == token {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (s *Session) HasNickname(nickname string) bool {
+	for _, p := range s.Players {
+		if strings.EqualFold(p.Nickname, nickname) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Session) Question() game.Question {
+	if s.State != QuestionState {
+		return nil
+	}
+
+	return s.Game.Questions[s.CurrentQuestion]
+}
+
+func (s *Session) HasNextQuestion() bool {
+	return s.CurrentQuestion+1 < len(s.Game.Questions)
+}
+
+func (s *Session) Deadline() time.Time {
+	limit := time.Duration(s.Question().GetTimeLimit()) * time.Second
+	return s.QuestionStartedAt.Add(limit)
+}
+
+func (s *Session) FindAnswer(playerId uuid.UUID, question int) *Answer {
+	for _, a := range s.Answers {
+		if a.PlayerId == playerId && a.Question == question {
+			return a
+		}
+	}
+
+	return nil
+}
+
+func (s *Session) AnswersTo(question int) []*Answer {
+	answers := make([]*Answer, 0)
+	for _, a := range s.Answers {
+		if a.Question == question {
+			answers = append(answers, a)
+		}
+	}
+
+	return answers
+}
+
+// Distribution tallies the answers to an ungraded question, it is nil for
+// questions that are graded.
+func (s *Session) Distribution(question int) []game.Tally {
+	ungraded, ok := s.Game.Questions[question].(game.UngradedQuestion)
+	if !ok {
+		return nil
+	}
+
+	answers := s.AnswersTo(question)
+	values := make([]any, len(answers))
+	for i, a := range answers {
+		values[i] = a.Value
+	}
+
+	return ungraded.Tally(values)
+}
+
+// Leaderboard returns the players ranked by score, ties going to whoever
+// joined first.
+func (s *Session) Leaderboard() []*Player {
+	ranking := make([]*Player, len(s.Players))
+	copy(ranking, s.Players)
+
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].Score != ranking[j].Score {
+			return ranking[i].Score > ranking[j].Score
+		}
+		return ranking[i].JoinedAt.Before(ranking[j].JoinedAt)
+	})
+
+	return ranking
+}
+
+// Clone copies the mutable parts of the session so that callers can't change
+// the stored state without going through the storer. The game is shared since
+// it is never modified while a session is running.
+func (s *Session) Clone() *Session {
+	clone := *s
+	clone.Players = make([]*Player, len(s.Players))
+	for i, p := range s.Players {
+		player := *p
+		clone.Players[i] = &player
+	}
+	clone.Answers = make([]*Answer, len(s.Answers))
+	for i, a := range s.Answers {
+		answer := *a
+		clone.Answers[i] = &answer
+	}
+
+	return &clone
+}
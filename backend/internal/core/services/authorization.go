@@ -0,0 +1,42 @@
+package services
+
+import (
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+// ForbiddenError is returned when a user is authenticated but not allowed to
+// do what was asked. It wraps the reason, so errors.Is keeps working on it.
+type ForbiddenError struct {
+	reason error
+}
+
+func NewForbiddenError(reason error) *ForbiddenError {
+	return &ForbiddenError{reason: reason}
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.reason.Error()
+}
+
+func (e *ForbiddenError) Unwrap() error {
+	return e.reason
+}
+
+// authorizeGameOwner makes sure userId owns the game.
+func authorizeGameOwner(userId string, g *game.Game) error {
+	if g.OwnerId != userId {
+		return NewForbiddenError(ports.ErrNotGameOwner)
+	}
+
+	return nil
+}
+
+// authorizeBankOwner makes sure userId owns the question of a bank.
+func authorizeBankOwner(userId string, q *game.BankQuestion) error {
+	if q.OwnerId != userId {
+		return NewForbiddenError(ports.ErrNotBankQuestionOwner)
+	}
+
+	return nil
+}
@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+const (
+	defaultBankPageSize = 20
+	// MaxBankSelection is the most questions taken from a bank at once
+	MaxBankSelection = 50
+)
+
+type SaveBankQuestionRequest struct {
+	Question game.Question `validate:"required"`
+	Tags     []string
+}
+
+type SearchBankRequest struct {
+	Search string      `validate:"lte=120"`
+	Tags   []string    `validate:"lte=20,dive,required,lte=40"`
+	Kinds  []game.Kind `validate:"lte=20"`
+	// Category and Difficulty are the ones of the questions themselves
+	Category   string          `validate:"lte=60"`
+	Difficulty game.Difficulty `validate:"omitempty,oneof=easy medium hard"`
+	// Page starts at 1, defaulting to the first page
+	Page     int `validate:"gte=0"`
+	PageSize int `validate:"gte=0,lte=50"`
+}
+
+type BankQuestionsPage struct {
+	Questions []*game.BankQuestion
+	Page      int
+	PageSize  int
+	Total     int
+}
+
+// BankSelection picks questions of a bank, either the ones with the given
+// ids or Count of them at random among the ones matching the search.
+type BankSelection struct {
+	QuestionIds []uuid.UUID     `validate:"lte=50"`
+	Search      string          `validate:"lte=120"`
+	Tags        []string        `validate:"lte=20,dive,required,lte=40"`
+	Kinds       []game.Kind     `validate:"lte=20"`
+	Category    string          `validate:"lte=60"`
+	Difficulty  game.Difficulty `validate:"omitempty,oneof=easy medium hard"`
+	Count       int             `validate:"required_without=QuestionIds,gte=0,lte=50"`
+}
+
+type CreateGameFromBankRequest struct {
+	Title       string
+	Description string
+	Selection   BankSelection
+	// Draft games may be created without any question
+	Draft bool
+}
+
+// BankService keeps the question banks of users. Questions always move
+// between banks and games as copies, never as references: a game keeps the
+// questions it was built with however its bank changes later, the same way
+// the bank keeps what was saved to it from a game.
+type BankService struct {
+	logger            ports.Logger
+	validationService *ValidationService
+	bankStorer        ports.BankStorer
+	gameService       *GameService
+}
+
+func NewBankService(
+	logger ports.Logger,
+	validationService *ValidationService,
+	bankStorer ports.BankStorer,
+	gameService *GameService,
+) *BankService {
+	return &BankService{
+		logger:            logger,
+		validationService: validationService,
+		bankStorer:        bankStorer,
+		gameService:       gameService,
+	}
+}
+
+// SaveQuestion saves a copy of the question to the bank of userId. Unlike
+// questions of drafts, it must be complete.
+func (s *BankService) SaveQuestion(
+	ctx context.Context,
+	userId string,
+	req *SaveBankQuestionRequest,
+) (*game.BankQuestion, error) {
+	saved, err := s.newBankQuestion(ctx, userId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.bankStorer.StoreBankQuestion(ctx, saved)
+	if err != nil {
+		s.logger.Errorf("Failed to store bank question %v", err)
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// SaveGameQuestions saves a copy of every question of a game owned by
+// userId to their bank, all with the same tags. Either every question is
+// saved or none of them is.
+func (s *BankService) SaveGameQuestions(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+	tags []string,
+) ([]*game.BankQuestion, error) {
+	found, err := s.gameService.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := make([]*game.BankQuestion, len(found.Questions))
+	for i, q := range found.Questions {
+		saved[i], err = s.newBankQuestion(ctx, userId, &SaveBankQuestionRequest{
+			Question: q,
+			Tags:     tags,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.bankStorer.StoreBankQuestions(ctx, saved)
+	if err != nil {
+		s.logger.Errorf("Failed to store bank questions %v", err)
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// UpdateQuestion replaces the question and tags of a question of the bank of
+// userId. Games it was copied into keep their copy as it was.
+func (s *BankService) UpdateQuestion(
+	ctx context.Context,
+	userId string,
+	questionId uuid.UUID,
+	req *SaveBankQuestionRequest,
+) (*game.BankQuestion, error) {
+	err := s.validationService.Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.findOwnedQuestion(ctx, userId, questionId)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := game.NewBankQuestion(userId, req.Question, req.Tags, time.Now())
+	updated.Id = found.Id
+	updated.CreatedAt = found.CreatedAt
+
+	err = s.checkQuestion(ctx, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.bankStorer.UpdateBankQuestion(ctx, updated)
+	if err != nil {
+		s.logger.Errorf("Failed to update bank question %v", err)
+		return nil, err
+	}
+
+	s.gameService.deleteOrphanedMedia(ctx, &game.Game{Questions: []game.Question{found.Question}})
+
+	return updated, nil
+}
+
+// DeleteQuestion deletes a question of the bank of userId.
+func (s *BankService) DeleteQuestion(
+	ctx context.Context,
+	userId string,
+	questionId uuid.UUID,
+) error {
+	found, err := s.findOwnedQuestion(ctx, userId, questionId)
+	if err != nil {
+		return err
+	}
+
+	err = s.bankStorer.DeleteBankQuestion(ctx, questionId)
+	if err != nil {
+		s.logger.Errorf("Failed to delete bank question %v", err)
+		return err
+	}
+
+	s.gameService.deleteOrphanedMedia(ctx, &game.Game{Questions: []game.Question{found.Question}})
+
+	return nil
+}
+
+// GetQuestion returns a question of the bank only if userId owns it.
+func (s *BankService) GetQuestion(
+	ctx context.Context,
+	userId string,
+	questionId uuid.UUID,
+) (*game.BankQuestion, error) {
+	return s.findOwnedQuestion(ctx, userId, questionId)
+}
+
+// SearchQuestions lists a page of the questions of the bank of userId,
+// newest first.
+func (s *BankService) SearchQuestions(
+	ctx context.Context,
+	userId string,
+	req *SearchBankRequest,
+) (*BankQuestionsPage, error) {
+	err := s.validationService.Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	query := ports.BankQuery{
+		OwnerId:    userId,
+		Search:     req.Search,
+		Tags:       game.NormalizeTags(req.Tags),
+		Kinds:      req.Kinds,
+		Category:   game.NormalizeTag(req.Category),
+		Difficulty: req.Difficulty,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = defaultBankPageSize
+	}
+
+	questions, total, err := s.bankStorer.FindBankQuestions(ctx, query)
+	if err != nil {
+		s.logger.Errorf("Failed to find bank questions %v", err)
+		return nil, err
+	}
+
+	return &BankQuestionsPage{
+		Questions: questions,
+		Page:      query.Page,
+		PageSize:  query.PageSize,
+		Total:     total,
+	}, nil
+}
+
+// CreateGameFromBank creates a game owned by userId out of copies of the
+// selected questions of their bank, e.g. 10 random questions tagged
+// photosynthesis. The game is stored along with all of its questions at once.
+func (s *BankService) CreateGameFromBank(
+	ctx context.Context,
+	userId string,
+	req *CreateGameFromBankRequest,
+) (*game.Game, error) {
+	questions, err := s.selectQuestions(ctx, userId, &req.Selection)
+	if err != nil {
+		return nil, err
+	}
+
+	newGame := &game.Game{
+		Title:       req.Title,
+		Description: req.Description,
+		Questions:   questions,
+	}
+
+	if req.Draft {
+		err = s.gameService.CreateDraftGame(ctx, userId, newGame)
+	} else {
+		err = s.gameService.CreateNewGame(ctx, userId, newGame)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newGame, nil
+}
+
+// AddToGame appends copies of the selected questions of the bank of userId
+// to a game they own.
+func (s *BankService) AddToGame(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+	selection *BankSelection,
+) (*game.Game, error) {
+	found, err := s.gameService.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.selectQuestions(ctx, userId, selection)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.gameService.UpdateGameQuestions(ctx, userId, gameId, &UpdateGameQuestionsRequest{
+		Questions: append(found.Questions, questions...),
+	})
+}
+
+// selectQuestions returns copies, with fresh ids, of the selected questions.
+// Questions picked by id keep the order they were given in.
+func (s *BankService) selectQuestions(
+	ctx context.Context,
+	userId string,
+	selection *BankSelection,
+) ([]game.Question, error) {
+	err := s.validationService.Validate(selection)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []*game.BankQuestion
+	if len(selection.QuestionIds) > 0 {
+		selected, err = s.bankStorer.FindBankQuestionsByIds(ctx, selection.QuestionIds)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, q := range selected {
+			err = authorizeBankOwner(userId, q)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		selected, err = s.bankStorer.FindRandomBankQuestions(ctx, ports.BankQuery{
+			OwnerId:    userId,
+			Search:     selection.Search,
+			Tags:       game.NormalizeTags(selection.Tags),
+			Kinds:      selection.Kinds,
+			Category:   game.NormalizeTag(selection.Category),
+			Difficulty: selection.Difficulty,
+		}, selection.Count)
+		if err != nil {
+			s.logger.Errorf("Failed to pick bank questions %v", err)
+			return nil, err
+		}
+	}
+
+	questions := make([]game.Question, len(selected))
+	for i, q := range selected {
+		questions[i] = q.Question.Clone()
+	}
+
+	return questions, nil
+}
+
+func (s *BankService) findOwnedQuestion(
+	ctx context.Context,
+	userId string,
+	questionId uuid.UUID,
+) (*game.BankQuestion, error) {
+	found, err := s.bankStorer.FindBankQuestion(ctx, questionId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizeBankOwner(userId, found)
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// newBankQuestion builds a question about to be saved to the bank of userId,
+// checking it along the way.
+func (s *BankService) newBankQuestion(
+	ctx context.Context,
+	userId string,
+	req *SaveBankQuestionRequest,
+) (*game.BankQuestion, error) {
+	err := s.validationService.Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := game.NewBankQuestion(userId, req.Question, req.Tags, time.Now())
+
+	err = s.checkQuestion(ctx, saved)
+	if err != nil {
+		return nil, err
+	}
+
+	return saved, nil
+}
+
+// checkQuestion renders and validates a question about to be saved to a
+// bank, along with the media it references.
+func (s *BankService) checkQuestion(ctx context.Context, q *game.BankQuestion) error {
+	holder := &game.Game{Questions: []game.Question{q.Question}}
+	holder.Render()
+	holder.NormalizeMetadata()
+
+	err := s.validationService.Validate(q)
+	if err != nil {
+		return err
+	}
+
+	return s.gameService.checkMedia(ctx, q.OwnerId, holder)
+}
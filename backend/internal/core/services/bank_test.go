@@ -0,0 +1,226 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+func (s *GameServiceTestSuite) mockedBankQuestion(title string) game.Question {
+	return &game.TrueFalseQuestion{
+		Title:            title,
+		Points:           1,
+		TimeLimit:        30,
+		TrueAlternative:  "yes",
+		FalseAlternative: "no",
+	}
+}
+
+func (s *GameServiceTestSuite) TestSaveBankQuestion() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+
+	table := []struct {
+		testDescription string
+		req             *services.SaveBankQuestionRequest
+		valid           bool
+	}{
+		{
+			testDescription: "complete question",
+			req: &services.SaveBankQuestionRequest{
+				Question: s.mockedBankQuestion("Plants need light"),
+				Tags:     []string{"Photosynthesis", " photosynthesis "},
+			},
+			valid: true,
+		},
+		{
+			testDescription: "incomplete question",
+			req: &services.SaveBankQuestionRequest{
+				Question: &game.TrueFalseQuestion{Title: "Plants need light"},
+			},
+		},
+		{
+			testDescription: "no question",
+			req:             &services.SaveBankQuestionRequest{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Act
+			saved, err := s.bankSvc.SaveQuestion(s.ctx, ownerId, tt.req)
+
+			// Assert
+			if !tt.valid {
+				validatorError := &services.ValidationError{}
+				assert.ErrorAs(t, err, &validatorError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"photosynthesis"}, saved.Tags)
+
+			found, err := s.bankSvc.GetQuestion(s.ctx, ownerId, saved.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, saved.Question, found.Question)
+
+			_, err = s.bankSvc.GetQuestion(s.ctx, uuid.NewString(), saved.Id)
+			assert.ErrorIs(t, err, ports.ErrNotBankQuestionOwner)
+		})
+	}
+}
+
+func (s *GameServiceTestSuite) TestSaveGameQuestionsToBank() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	complete := s.generateMockedGame("complete", "desc", ownerId, []game.Question{
+		s.mockedBankQuestion("Plants need light"),
+		s.mockedBankQuestion("Cells have a nucleus"),
+	})
+	err := s.svc.CreateNewGame(s.ctx, ownerId, complete)
+	assert.NoError(t, err)
+	incomplete := s.generateMockedGame("incomplete", "desc", ownerId, []game.Question{
+		s.mockedBankQuestion("Leaves are green"),
+		&game.TrueFalseQuestion{Title: "Roots drink water"},
+	})
+	err = s.svc.CreateDraftGame(s.ctx, ownerId, incomplete)
+	assert.NoError(t, err)
+
+	// Act
+	saved, err := s.bankSvc.SaveGameQuestions(s.ctx, ownerId, complete.Id, []string{"Biology"})
+	_, incompleteErr := s.bankSvc.SaveGameQuestions(s.ctx, ownerId, incomplete.Id, nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, saved, 2)
+	assert.Equal(t, []string{"biology"}, saved[0].Tags)
+
+	validatorError := &services.ValidationError{}
+	assert.ErrorAs(t, incompleteErr, &validatorError)
+
+	page, err := s.bankSvc.SearchQuestions(s.ctx, ownerId, &services.SearchBankRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page.Total)
+}
+
+func (s *GameServiceTestSuite) TestCreateGameFromBank() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	saved := make([]*game.BankQuestion, 0)
+	for i := 0; i < 3; i++ {
+		q, err := s.bankSvc.SaveQuestion(s.ctx, ownerId, &services.SaveBankQuestionRequest{
+			Question: s.mockedBankQuestion(fmt.Sprintf("Plants need %d things", i)),
+			Tags:     []string{"photosynthesis"},
+		})
+		assert.NoError(t, err)
+		saved = append(saved, q)
+	}
+	_, err := s.bankSvc.SaveQuestion(s.ctx, ownerId, &services.SaveBankQuestionRequest{
+		Question: s.mockedBankQuestion("Cells have a nucleus"),
+		Tags:     []string{"cells"},
+	})
+	assert.NoError(t, err)
+
+	// Act
+	random, randomErr := s.bankSvc.CreateGameFromBank(s.ctx, ownerId, &services.CreateGameFromBankRequest{
+		Title:     "random",
+		Selection: services.BankSelection{Tags: []string{"Photosynthesis"}, Count: 2},
+	})
+	picked, pickedErr := s.bankSvc.CreateGameFromBank(s.ctx, ownerId, &services.CreateGameFromBankRequest{
+		Title:     "picked",
+		Selection: services.BankSelection{QuestionIds: []uuid.UUID{saved[2].Id, saved[0].Id}},
+	})
+	_, othersErr := s.bankSvc.CreateGameFromBank(s.ctx, uuid.NewString(), &services.CreateGameFromBankRequest{
+		Title:     "stolen",
+		Selection: services.BankSelection{QuestionIds: []uuid.UUID{saved[0].Id}},
+	})
+
+	// Assert
+	assert.NoError(t, randomErr)
+	assert.Len(t, random.Questions, 2)
+	for _, q := range random.Questions {
+		assert.Contains(t, q.GetTitle(), "Plants need")
+	}
+
+	assert.NoError(t, pickedErr)
+	assert.Equal(t, game.PublishedStatus, picked.Status)
+	assert.Equal(t, saved[2].Question.GetTitle(), picked.Questions[0].GetTitle())
+	assert.Equal(t, saved[0].Question.GetTitle(), picked.Questions[1].GetTitle())
+	assert.NotEqual(t, saved[0].Question.(*game.TrueFalseQuestion).Id, picked.Questions[1].(*game.TrueFalseQuestion).Id)
+
+	assert.ErrorIs(t, othersErr, ports.ErrNotBankQuestionOwner)
+}
+
+func (s *GameServiceTestSuite) TestAddBankQuestionsToGame() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	saved, err := s.bankSvc.SaveQuestion(s.ctx, ownerId, &services.SaveBankQuestionRequest{
+		Question: s.mockedBankQuestion("Plants need light"),
+	})
+	assert.NoError(t, err)
+
+	// Act
+	updated, err := s.bankSvc.AddToGame(s.ctx, ownerId, mockedGame.Id, &services.BankSelection{
+		QuestionIds: []uuid.UUID{saved.Id},
+	})
+	_, err2 := s.bankSvc.UpdateQuestion(s.ctx, ownerId, saved.Id, &services.SaveBankQuestionRequest{
+		Question: s.mockedBankQuestion("Plants need sunlight"),
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, err2)
+	assert.Len(t, updated.Questions, 2)
+
+	found, err := s.svc.GetGameById(s.ctx, ownerId, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "Plants need light", found.Questions[1].GetTitle())
+}
+
+func (s *GameServiceTestSuite) TestBankKeepsMediaOfDeletedGames() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	image := s.uploadMockedImage(ownerId)
+	question := &game.TrueFalseQuestion{
+		Title:            "What is shown?",
+		Points:           1,
+		TimeLimit:        30,
+		Media:            &game.Media{Id: image.Id, Kind: game.ImageMedia},
+		TrueAlternative:  "a plant",
+		FalseAlternative: "a cell",
+	}
+	mockedGame := s.generateMockedGame("title", "desc", ownerId, []game.Question{question})
+	err := s.svc.CreateNewGame(s.ctx, ownerId, mockedGame)
+	assert.NoError(t, err)
+	saved, err := s.bankSvc.SaveQuestion(s.ctx, ownerId, &services.SaveBankQuestionRequest{
+		Question: question,
+	})
+	assert.NoError(t, err)
+
+	// Act
+	err = s.svc.DeleteGame(s.ctx, ownerId, mockedGame.Id)
+	_, content, openErr := s.mediaSvc.OpenMedia(s.ctx, image.Id)
+	if openErr == nil {
+		content.Close()
+	}
+	deleteErr := s.bankSvc.DeleteQuestion(s.ctx, ownerId, saved.Id)
+	_, _, openAfterErr := s.mediaSvc.OpenMedia(s.ctx, image.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, openErr)
+	assert.NoError(t, deleteErr)
+	assert.ErrorIs(t, openAfterErr, ports.ErrMediaNotFound)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+// gameCursor is what the opaque cursors of GamesPage hold. The sort and order
+// are kept along the position so that a cursor isn't used with a listing it
+// wasn't made for.
+type gameCursor struct {
+	Sort       ports.GameSort `json:"s"`
+	Descending bool           `json:"d"`
+	Key        string         `json:"k"`
+	Id         uuid.UUID      `json:"i"`
+}
+
+func encodeGameCursor(opts *ports.FindGamesOptions, g *game.Game) string {
+	cursor := gameCursor{
+		Sort:       opts.Sort,
+		Descending: opts.Descending,
+		Key:        gameSortKey(g, opts.Sort),
+		Id:         g.Id,
+	}
+
+	// marshalling a struct of strings, a bool and an uuid can't fail
+	data, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeGameCursor reads the cursor given as field, which must have been made
+// for the listing of opts.
+func decodeGameCursor(
+	field string,
+	encoded string,
+	opts *ports.FindGamesOptions,
+) (*ports.GameCursor, error) {
+	invalid := &ValidationError{}
+	invalid.AddNewMessage(newErrorMessage(field, encoded, "cursor of the same sort and order"))
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var cursor gameCursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || cursor.Sort != opts.Sort || cursor.Descending != opts.Descending {
+		return nil, invalid
+	}
+
+	// time keys end up cast by the storage, they must parse
+	if cursor.Sort != ports.TitleGameSort {
+		_, err = time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, invalid
+		}
+	}
+
+	return &ports.GameCursor{Key: cursor.Key, Id: cursor.Id}, nil
+}
+
+// gameSortKey is the value of the game a listing of the given sort is ordered
+// by.
+func gameSortKey(g *game.Game, sort ports.GameSort) string {
+	switch sort {
+	case ports.TitleGameSort:
+		return g.Title
+	case ports.UpdatedGameSort:
+		return g.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		return g.CreatedAt.Format(time.RFC3339Nano)
+	}
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -9,24 +11,135 @@ import (
 	"github.com/taldoflemis/brain.test/internal/ports"
 )
 
+type UpdateGameInfoRequest struct {
+	// fields left nil are kept as they are
+	Title       *string             `validate:"omitempty,gte=1,lte=120"`
+	Description *string             `validate:"omitempty,min=1,max=200"`
+	Scoring     *game.ScoringPolicy `validate:"omitempty"`
+	Visibility  *game.Visibility    `validate:"omitempty,oneof=private unlisted public"`
+	Tags        *[]string           `validate:"omitempty,lte=20,dive,required,lte=40"`
+	Category    *string             `validate:"omitempty,lte=60"`
+	Difficulty  *game.Difficulty    `validate:"omitempty,oneof=easy medium hard"`
+}
+
+// PatchGameRequest changes part of a game, the fields left nil are kept as
+// they are.
+type PatchGameRequest struct {
+	UpdateGameInfoRequest
+	Questions *[]game.Question
+}
+
+// UpdateGameQuestionsRequest holds the new questions of a game. Like the rest
+// of a draft they may be incomplete, every rule is only checked when the game
+// is published.
+type UpdateGameQuestionsRequest struct {
+	Questions []game.Question
+}
+
+const (
+	defaultPublicGamesPageSize = 20
+	defaultGamesPageSize       = 20
+	// maxTagSuggestions is how many tags are suggested at once
+	maxTagSuggestions = 10
+)
+
+// draftFields are the only fields of a game validated before it is published,
+// besides its questions, so that work in progress can be saved
+var draftFields = []string{"Id", "Title", "OwnerId", "Scoring", "Visibility", "Metadata.Tags", "Metadata.Category", "Metadata.Difficulty"}
+
+// incompleteRules are the rules the questions of a draft may break, the ones
+// only met once a question is complete
+var incompleteRules = []string{"required", "required_without", "min", "correctalternatives"}
+
+// MetadataFilterRequest keeps the games, or questions, having every one of
+// the tags and the category and difficulty when they are set.
+type MetadataFilterRequest struct {
+	Tags       []string        `validate:"lte=20,dive,required,lte=40"`
+	Category   string          `validate:"lte=60"`
+	Difficulty game.Difficulty `validate:"omitempty,oneof=easy medium hard"`
+}
+
+// toMetadataFilter normalizes the filter like the metadata it is matched
+// against.
+func (r *MetadataFilterRequest) toMetadataFilter() ports.MetadataFilter {
+	normalized := game.Metadata{Tags: r.Tags, Category: r.Category}
+	normalized.Normalize()
+
+	return ports.MetadataFilter{
+		Tags:       normalized.Tags,
+		Category:   normalized.Category,
+		Difficulty: r.Difficulty,
+	}
+}
+
+type GetGamesRequest struct {
+	Filter MetadataFilterRequest
+	// Search matches games whose title contain it, ignoring case
+	Search string `validate:"lte=120"`
+	// Sort defaults to the last edited games first
+	Sort ports.GameSort `validate:"omitempty,oneof=created updated title"`
+	// Order defaults to desc when sorting by time and asc by title
+	Order string `validate:"omitempty,oneof=asc desc"`
+	// After and Before are cursors of a previous page, of the same sort and
+	// order, to get the page after or before it
+	After    string
+	Before   string `validate:"excluded_with=After"`
+	PageSize int    `validate:"gte=0,lte=50"`
+	// SummaryOnly skips loading questions
+	SummaryOnly bool
+}
+
+type GamesPage struct {
+	Games []*game.Game
+	// Total counts the games matching the filter and search across all pages
+	Total int
+	// Next and Prev are the cursors of the pages after and before this one,
+	// empty when there is no such page
+	Next string
+	Prev string
+}
+
+type BrowsePublicGamesRequest struct {
+	Search string `validate:"lte=120"`
+	Filter MetadataFilterRequest
+	Sort   ports.GameSort `validate:"omitempty,oneof=recent plays"`
+	// Page starts at 1, defaulting to the first page
+	Page     int `validate:"gte=0"`
+	PageSize int `validate:"gte=0,lte=50"`
+}
+
+type PublicGamesPage struct {
+	Games    []*ports.GameListing
+	Page     int
+	PageSize int
+	Total    int
+}
+
 type GameService struct {
 	logger            ports.Logger
 	validationService *ValidationService
 	gameStorer        ports.GameStorer
+	revisionStorer    ports.RevisionStorer
+	mediaStorer       ports.MediaStorer
 }
 
 func NewGameService(
 	logger ports.Logger,
 	validationService *ValidationService,
 	gameStorer ports.GameStorer,
+	revisionStorer ports.RevisionStorer,
+	mediaStorer ports.MediaStorer,
 ) *GameService {
 	return &GameService{
 		logger:            logger,
 		validationService: validationService,
 		gameStorer:        gameStorer,
+		revisionStorer:    revisionStorer,
+		mediaStorer:       mediaStorer,
 	}
 }
 
+// CreateNewGame stores a complete game and publishes it right away.
 func (s *GameService) CreateNewGame(
 	ctx context.Context,
 	userId string,
@@ -34,6 +147,11 @@ func (s *GameService) CreateNewGame(
 ) error {
 	req.OwnerId = userId
 	req.Id = uuid.New()
+	req.Visibility = req.VisibilityOrDefault()
+	req.Status = game.DraftStatus
+	req.Revision = 1
+	req.Render()
+	req.NormalizeMetadata()
 
 	err := s.validationService.Validate(req)
 	if err != nil {
@@ -41,25 +159,521 @@ func (s *GameService) CreateNewGame(
 		return err
 	}
 
-	err = s.gameStorer.StoreGame(ctx, req)
+	err = s.checkMedia(ctx, userId, req)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.createGame(ctx, userId, req, &now)
+}
+
+// CreateDraftGame stores a game that may still be incomplete, it can't be
+// played until it is published.
+func (s *GameService) CreateDraftGame(
+	ctx context.Context,
+	userId string,
+	req *game.Game,
+) error {
+	req.OwnerId = userId
+	req.Id = uuid.New()
+	req.Visibility = req.VisibilityOrDefault()
+	req.Status = game.DraftStatus
+	req.Revision = 1
+	req.Render()
+	req.NormalizeMetadata()
+
+	err := s.validationService.ValidateDraft(req)
+	if err != nil {
+		s.logger.Errorf("Failed to validate draft %v", err)
+		return err
+	}
+
+	err = s.checkMedia(ctx, userId, req)
+	if err != nil {
+		return err
+	}
+
+	return s.createGame(ctx, userId, req, nil)
+}
+
+// createGame stores a new game of userId along with its first revision, and
+// publishes it at publishedAt unless it is nil.
+func (s *GameService) createGame(
+	ctx context.Context,
+	userId string,
+	g *game.Game,
+	publishedAt *time.Time,
+) error {
+	revision := game.NewRevision(userId, nil, g, time.Now())
+	err := s.gameStorer.CreateGame(ctx, g, revision, publishedAt)
 	if err != nil {
-		s.logger.Errorf("Failed to store game %v", err)
+		s.logger.Errorf("Failed to create game %v", err)
 		return err
 	}
 
 	return nil
 }
 
+// PublishGame runs every validation rule against the current version of a
+// game owned by userId, and freezes it as the version sessions play.
+func (s *GameService) PublishGame(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+) (*game.Game, error) {
+	found, err := s.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.validationService.Validate(found)
+	if err != nil {
+		s.logger.Errorf("Failed to validate game %v", err)
+		return nil, err
+	}
+
+	snapshot := found.Snapshot(time.Now())
+	err = s.gameStorer.PublishGame(ctx, snapshot)
+	if err != nil {
+		s.logger.Errorf("Failed to publish game %v", err)
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// GetGamesByUserId lists a page of the games of userId, only the ones
+// matching the filter and search if any.
 func (s *GameService) GetGamesByUserId(
 	ctx context.Context,
 	userId string,
-) ([]*game.Game, error) {
-	return s.gameStorer.FindAllGamesByUserId(ctx, userId)
+	req *GetGamesRequest,
+) (*GamesPage, error) {
+	err := s.validationService.Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := ports.FindGamesOptions{
+		SummaryOnly: req.SummaryOnly,
+		Filter:      req.Filter.toMetadataFilter(),
+		Search:      req.Search,
+		Sort:        req.Sort,
+	}
+	if opts.Sort == "" {
+		opts.Sort = ports.UpdatedGameSort
+	}
+	opts.Descending = req.Order == "desc" || (req.Order == "" && opts.Sort != ports.TitleGameSort)
+
+	pageSize := req.PageSize
+	if pageSize == 0 {
+		pageSize = defaultGamesPageSize
+	}
+	// one game more than the page tells whether there is another page past it
+	opts.Limit = pageSize + 1
+
+	if req.After != "" {
+		opts.After, err = decodeGameCursor("After", req.After, &opts)
+	}
+	if req.Before != "" {
+		opts.Before, err = decodeGameCursor("Before", req.Before, &opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	games, total, err := s.gameStorer.FindAllGamesByUserId(ctx, userId, opts)
+	if err != nil {
+		s.logger.Errorf("Failed to find games %v", err)
+		return nil, err
+	}
+
+	backwards := opts.Before != nil
+	more := len(games) > pageSize
+	if more && backwards {
+		games = games[1:]
+	} else if more {
+		games = games[:pageSize]
+	}
+
+	page := &GamesPage{
+		Games: games,
+		Total: total,
+	}
+	if len(games) == 0 {
+		return page, nil
+	}
+
+	// coming back from a page means there is one after this one, and the
+	// other way around
+	if more || backwards {
+		page.Next = encodeGameCursor(&opts, games[len(games)-1])
+	}
+	if more && backwards || opts.After != nil {
+		page.Prev = encodeGameCursor(&opts, games[0])
+	}
+
+	return page, nil
 }
 
+// SuggestTags autocompletes a tag from the ones userId already used, the
+// most used first.
+func (s *GameService) SuggestTags(
+	ctx context.Context,
+	userId string,
+	prefix string,
+) ([]string, error) {
+	tags, err := s.gameStorer.FindTagsByUserId(ctx, userId, game.NormalizeTag(prefix), maxTagSuggestions)
+	if err != nil {
+		s.logger.Errorf("Failed to find tags %v", err)
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// GetGameById returns the game only if userId owns it.
 func (s *GameService) GetGameById(
 	ctx context.Context,
+	userId string,
 	gameId uuid.UUID,
 ) (*game.Game, error) {
-	return s.gameStorer.FindGameById(ctx, gameId)
+	return s.findOwnedGame(ctx, userId, gameId)
+}
+
+// GetSharedGame returns the published version of a game anyone can see, i.e.
+// one that is either unlisted or public. Private games are reported as not
+// found so that their existence isn't leaked.
+func (s *GameService) GetSharedGame(
+	ctx context.Context,
+	gameId uuid.UUID,
+) (*game.Game, error) {
+	found, err := s.gameStorer.FindGameById(ctx, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found.VisibilityOrDefault().IsShared() {
+		return nil, ports.ErrGameNotFound
+	}
+
+	return s.gameStorer.FindPublishedGame(ctx, gameId)
+}
+
+// BrowsePublicGames lists a page of the public catalogue.
+func (s *GameService) BrowsePublicGames(
+	ctx context.Context,
+	req *BrowsePublicGamesRequest,
+) (*PublicGamesPage, error) {
+	err := s.validationService.Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	query := ports.PublicGamesQuery{
+		Search:   req.Search,
+		Filter:   req.Filter.toMetadataFilter(),
+		Sort:     req.Sort,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+	if query.Sort == "" {
+		query.Sort = ports.RecentGameSort
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PageSize == 0 {
+		query.PageSize = defaultPublicGamesPageSize
+	}
+
+	games, total, err := s.gameStorer.FindPublicGames(ctx, query)
+	if err != nil {
+		s.logger.Errorf("Failed to find public games %v", err)
+		return nil, err
+	}
+
+	return &PublicGamesPage{
+		Games:    games,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+		Total:    total,
+	}, nil
+}
+
+// CopyGame forks a game userId can see, i.e. one they own or that is shared,
+// into a new private game owned by them. Owners copy the current version of
+// their game, everyone else the published one, so that unpublished edits
+// aren't leaked.
+func (s *GameService) CopyGame(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+) (*game.Game, error) {
+	found, err := s.gameStorer.FindGameById(ctx, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizeGameOwner(userId, found)
+	if err != nil {
+		if !found.VisibilityOrDefault().IsShared() {
+			return nil, err
+		}
+
+		found, err = s.gameStorer.FindPublishedGame(ctx, gameId)
+		if errors.Is(err, ports.ErrGameNotPublished) {
+			return nil, ports.ErrGameNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fork := found.Fork(userId)
+
+	err = s.validationService.ValidateDraft(fork)
+	if err != nil {
+		s.logger.Errorf("Failed to validate draft %v", err)
+		return nil, err
+	}
+
+	err = s.createGame(ctx, userId, fork, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return fork, nil
+}
+
+// DeleteGame deletes a game owned by userId.
+func (s *GameService) DeleteGame(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+) error {
+	found, err := s.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return err
+	}
+
+	err = s.gameStorer.DeleteGame(ctx, gameId)
+	if err != nil {
+		s.logger.Errorf("Failed to delete game %v", err)
+		return err
+	}
+
+	s.deleteOrphanedMedia(ctx, found)
+
+	return nil
+}
+
+// UpdateGame replaces the info and questions of a game owned by userId.
+func (s *GameService) UpdateGame(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+	req *game.Game,
+) (*game.Game, error) {
+	req.Id = gameId
+	req.OwnerId = userId
+	req.Visibility = req.VisibilityOrDefault()
+	req.Render()
+	req.NormalizeMetadata()
+
+	err := s.validationService.ValidateDraft(req)
+	if err != nil {
+		s.logger.Errorf("Failed to validate draft %v", err)
+		return nil, err
+	}
+
+	found, err := s.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkMedia(ctx, userId, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.replaceGame(ctx, userId, found, req)
+}
+
+// replaceGame overwrites the info and questions of current with the ones of
+// req, recording the change as a new revision.
+func (s *GameService) replaceGame(
+	ctx context.Context,
+	userId string,
+	current *game.Game,
+	req *game.Game,
+	notes ...string,
+) (*game.Game, error) {
+	info := &ports.GameInfo{
+		Title:       req.Title,
+		Description: req.Description,
+		Scoring:     req.Scoring,
+		Visibility:  req.VisibilityOrDefault(),
+		Metadata:    req.Metadata,
+	}
+
+	err := s.gameStorer.UpdateGame(ctx, current.Id, info, req.Questions)
+	if err != nil {
+		s.logger.Errorf("Failed to update game %v", err)
+		return nil, err
+	}
+
+	after, err := s.findRecordedGame(ctx, userId, current, notes...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.deleteOrphanedMedia(ctx, current)
+
+	return after, nil
+}
+
+// UpdateGameInfo changes the title, description or scoring policy of a game
+// owned by userId, leaving its questions untouched.
+func (s *GameService) UpdateGameInfo(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+	req *UpdateGameInfoRequest,
+) (*game.Game, error) {
+	err := s.validationService.Validate(req)
+	if err != nil {
+		s.logger.Errorf("Failed to validate game info %v", err)
+		return nil, err
+	}
+
+	found, err := s.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	info := mergeGameInfo(found, req)
+
+	err = s.gameStorer.UpdateGameInfo(ctx, gameId, info)
+	if err != nil {
+		s.logger.Errorf("Failed to update game info %v", err)
+		return nil, err
+	}
+
+	return s.findRecordedGame(ctx, userId, found)
+}
+
+// UpdateGameQuestions replaces the questions of a game owned by userId.
+func (s *GameService) UpdateGameQuestions(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+	req *UpdateGameQuestionsRequest,
+) (*game.Game, error) {
+	return s.PatchGame(ctx, userId, gameId, &PatchGameRequest{Questions: &req.Questions})
+}
+
+// PatchGame changes the info, the questions or both of a game owned by
+// userId, all at once.
+func (s *GameService) PatchGame(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+	req *PatchGameRequest,
+) (*game.Game, error) {
+	if req.Questions == nil {
+		return s.UpdateGameInfo(ctx, userId, gameId, &req.UpdateGameInfoRequest)
+	}
+
+	err := s.validationService.Validate(req.UpdateGameInfoRequest)
+	if err != nil {
+		s.logger.Errorf("Failed to validate game info %v", err)
+		return nil, err
+	}
+
+	found, err := s.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	info := mergeGameInfo(found, &req.UpdateGameInfoRequest)
+	updated := &game.Game{
+		Id:          found.Id,
+		OwnerId:     found.OwnerId,
+		Title:       info.Title,
+		Description: info.Description,
+		Scoring:     info.Scoring,
+		Visibility:  info.Visibility,
+		Metadata:    info.Metadata,
+		Questions:   *req.Questions,
+	}
+	updated.Render()
+	updated.NormalizeMetadata()
+
+	err = s.validationService.ValidateDraft(updated)
+	if err != nil {
+		s.logger.Errorf("Failed to validate draft %v", err)
+		return nil, err
+	}
+
+	err = s.checkMedia(ctx, userId, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.replaceGame(ctx, userId, found, updated)
+}
+
+// mergeGameInfo returns the info of found with the fields req sets replaced.
+func mergeGameInfo(found *game.Game, req *UpdateGameInfoRequest) *ports.GameInfo {
+	info := &ports.GameInfo{
+		Title:       found.Title,
+		Description: found.Description,
+		Scoring:     found.Scoring,
+		Visibility:  found.VisibilityOrDefault(),
+		Metadata:    found.Metadata.Clone(),
+	}
+	if req.Title != nil {
+		info.Title = *req.Title
+	}
+	if req.Description != nil {
+		info.Description = *req.Description
+	}
+	if req.Scoring != nil {
+		info.Scoring = req.Scoring
+	}
+	if req.Visibility != nil {
+		info.Visibility = *req.Visibility
+	}
+	if req.Tags != nil {
+		info.Metadata.Tags = *req.Tags
+	}
+	if req.Category != nil {
+		info.Metadata.Category = *req.Category
+	}
+	if req.Difficulty != nil {
+		info.Metadata.Difficulty = *req.Difficulty
+	}
+	info.Metadata.Normalize()
+
+	return info
+}
+
+func (s *GameService) findOwnedGame(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+) (*game.Game, error) {
+	found, err := s.gameStorer.FindGameById(ctx, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizeGameOwner(userId, found)
+	if err != nil {
+		return nil, err
+	}
+
+	return found, nil
 }
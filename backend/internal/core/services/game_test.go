@@ -1,9 +1,12 @@
 package services_test
 
 import (
+	"bytes"
 	"context"
 	"log"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -11,9 +14,11 @@ import (
 	"github.com/stretchr/testify/suite"
 	testcontainers "github.com/testcontainers/testcontainers-go/modules/postgres"
 
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/media"
 	"github.com/taldoflemis/brain.test/internal/adapters/driven/postgres"
 	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
 	"github.com/taldoflemis/brain.test/internal/core/services"
+	"github.com/taldoflemis/brain.test/internal/ports"
 	testshelpers "github.com/taldoflemis/brain.test/test/helpers"
 )
 
@@ -27,6 +32,8 @@ type GameServiceTestSuite struct {
 	ctx         context.Context
 	pool        *pgxpool.Pool
 	svc         *services.GameService
+	mediaSvc    *services.MediaService
+	bankSvc     *services.BankService
 }
 
 func (s *GameServiceTestSuite) SetupSuite() {
@@ -42,14 +49,32 @@ func (s *GameServiceTestSuite) SetupSuite() {
 	}
 
 	gameStorer := postgres.NewPostgresGameStorer(pool)
+	revisionStorer := postgres.NewPostgresRevisionStorer(pool)
 	s.pgContainer = pgContainer
 	s.pool = pool
 	logger := testshelpers.NewDummyLogger(log.Writer())
-	s.svc = services.NewGameService(logger, services.NewValidationService(), gameStorer)
+	mediaStorer, err := media.NewLocalMediaStorer(s.T().TempDir())
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.svc = services.NewGameService(
+		logger,
+		services.NewValidationService(),
+		gameStorer,
+		revisionStorer,
+		mediaStorer,
+	)
+	s.mediaSvc = services.NewMediaService(logger, mediaStorer)
+	s.bankSvc = services.NewBankService(
+		logger,
+		services.NewValidationService(),
+		postgres.NewPostgresBankStorer(pool),
+		s.svc,
+	)
 }
 
 func (s *GameServiceTestSuite) TearDownTest() {
-	_, err := s.pool.Exec(s.ctx, "TRUNCATE TABLE games CASCADE")
+	_, err := s.pool.Exec(s.ctx, "TRUNCATE TABLE games, bank_questions CASCADE")
 	if err != nil {
 		log.Fatalf("error truncating games table: %s", err)
 	}
@@ -469,6 +494,249 @@ func (s *GameServiceTestSuite) TestCreateNewInvalidGame() {
 				},
 			},
 		},
+		{
+			testDescription: "game with single choice quiz question with two correct alternatives",
+			title:           "title 22",
+			desc:            "testDescription 22",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.QuizQuestion{
+					Title:        "title 22",
+					Points:       1,
+					TimeLimit:    30,
+					Mode:         game.SingleChoiceQuizMode,
+					Alternatives: *s.generateMockedQuizAlternatives(),
+				},
+			},
+		},
+		{
+			testDescription: "game with quiz question with unknown credit policy",
+			title:           "title 23",
+			desc:            "testDescription 23",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.QuizQuestion{
+					Title:        "title 23",
+					Points:       1,
+					TimeLimit:    30,
+					Credit:       "tubias",
+					Alternatives: *s.generateMockedQuizAlternatives(),
+				},
+			},
+		},
+		{
+			testDescription: "game with type answer question with invalid pattern",
+			title:           "title 24",
+			desc:            "testDescription 24",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.TypeAnswerQuestion{
+					Title:     "title 24",
+					Points:    1,
+					TimeLimit: 30,
+					Pattern:   "colou?r(",
+				},
+			},
+		},
+		{
+			testDescription: "game with type answer question without answers",
+			title:           "title 25",
+			desc:            "testDescription 25",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.TypeAnswerQuestion{
+					Title:     "title 25",
+					Points:    1,
+					TimeLimit: 30,
+				},
+			},
+		},
+		{
+			testDescription: "game with ordering question with a single item",
+			title:           "title 26",
+			desc:            "testDescription 26",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.OrderingQuestion{
+					Title:     "title 26",
+					Points:    1,
+					TimeLimit: 30,
+					Items:     []string{"first"},
+				},
+			},
+		},
+		{
+			testDescription: "game with ordering question with too many items",
+			title:           "title 27",
+			desc:            "testDescription 27",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.OrderingQuestion{
+					Title:     "title 27",
+					Points:    1,
+					TimeLimit: 30,
+					Items:     []string{"1", "2", "3", "4", "5", "6", "7"},
+				},
+			},
+		},
+		{
+			testDescription: "game with ordering question with repeated items",
+			title:           "title 28",
+			desc:            "testDescription 28",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.OrderingQuestion{
+					Title:     "title 28",
+					Points:    1,
+					TimeLimit: 30,
+					Items:     []string{"first", "first"},
+				},
+			},
+		},
+		{
+			testDescription: "game with slider question with an empty range",
+			title:           "title 29",
+			desc:            "testDescription 29",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.SliderQuestion{
+					Title:     "title 29",
+					Points:    1,
+					TimeLimit: 30,
+					Min:       10,
+					Max:       10,
+					Step:      1,
+					Target:    10,
+				},
+			},
+		},
+		{
+			testDescription: "game with slider question with target out of range",
+			title:           "title 30",
+			desc:            "testDescription 30",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.SliderQuestion{
+					Title:     "title 30",
+					Points:    1,
+					TimeLimit: 30,
+					Min:       0,
+					Max:       10,
+					Step:      1,
+					Target:    11,
+				},
+			},
+		},
+		{
+			testDescription: "game with poll question with a single option",
+			title:           "title 31",
+			desc:            "testDescription 31",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.PollQuestion{
+					Title:     "title 31",
+					TimeLimit: 30,
+					Options:   []string{"only"},
+				},
+			},
+		},
+		{
+			testDescription: "game with poll question with repeated options",
+			title:           "title 32",
+			desc:            "testDescription 32",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.PollQuestion{
+					Title:     "title 32",
+					TimeLimit: 30,
+					Options:   []string{"same", "same"},
+				},
+			},
+		},
+		{
+			testDescription: "game with word cloud question without time limit",
+			title:           "title 33",
+			desc:            "testDescription 33",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.WordCloudQuestion{
+					Title: "title 33",
+				},
+			},
+		},
+		{
+			testDescription: "game with image played from an offset",
+			title:           "title 34",
+			desc:            "testDescription 34",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.PollQuestion{
+					Title:     "title 34",
+					TimeLimit: 30,
+					Options:   []string{"a", "b"},
+					Media:     &game.Media{Id: uuid.New(), Kind: game.ImageMedia, Start: 3},
+				},
+			},
+		},
+		{
+			testDescription: "game with audio ending before it starts",
+			title:           "title 35",
+			desc:            "testDescription 35",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.PollQuestion{
+					Title:     "title 35",
+					TimeLimit: 30,
+					Options:   []string{"a", "b"},
+					Media:     &game.Media{Id: uuid.New(), Kind: game.AudioMedia, Start: 10, End: 5},
+				},
+			},
+		},
+		{
+			testDescription: "game with media that was never uploaded",
+			title:           "title 36",
+			desc:            "testDescription 36",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.PollQuestion{
+					Title:     "title 36",
+					TimeLimit: 30,
+					Options:   []string{"a", "b"},
+					Media:     &game.Media{Id: uuid.New(), Kind: game.VideoMedia},
+				},
+			},
+		},
+		{
+			testDescription: "game with markdown title containing a script",
+			title:           "title 37",
+			desc:            "testDescription 37",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.WordCloudQuestion{
+					Title:     "title <script>alert(37)</script>",
+					TimeLimit: 30,
+					Format:    game.MarkdownText,
+				},
+			},
+		},
+		{
+			testDescription: "game with quiz alternative containing a link in latex",
+			title:           "title 38",
+			desc:            "testDescription 38",
+			ownerID:         userID,
+			questions: []game.Question{
+				&game.QuizQuestion{
+					Title:     "title 38",
+					Points:    1,
+					TimeLimit: 30,
+					Alternatives: []game.Alternative{
+						{Data: `$\href{https://example.com}{1}$`, IsCorrect: true, Format: game.MarkdownLatexText},
+						{Data: "2"},
+						{Data: "3"},
+					},
+				},
+			},
+		},
 	}
 
 	validatorError := &services.ValidationError{}
@@ -484,3 +752,925 @@ func (s *GameServiceTestSuite) TestCreateNewInvalidGame() {
 		})
 	}
 }
+
+func (s *GameServiceTestSuite) createMockedGame(ownerId string) *game.Game {
+	mockedGame := s.generateMockedGame("dumb title", "desc here", ownerId, []game.Question{
+		&game.TrueFalseQuestion{
+			Title:            "testQuestion",
+			Points:           1,
+			TimeLimit:        30,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+	})
+
+	err := s.svc.CreateNewGame(s.ctx, ownerId, mockedGame)
+	if err != nil {
+		log.Fatalf("error creating game: %s", err)
+	}
+
+	return mockedGame
+}
+
+func (s *GameServiceTestSuite) TestUpdateGameInfo() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	title := "new title"
+
+	// Act
+	updated, err := s.svc.UpdateGameInfo(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameInfoRequest{Title: &title},
+	)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, title, updated.Title)
+	assert.Equal(t, mockedGame.Description, updated.Description)
+}
+
+func (s *GameServiceTestSuite) TestGameMetadata() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.generateMockedGame("plants", "desc here", ownerId, []game.Question{
+		&game.TrueFalseQuestion{
+			Metadata:         game.Metadata{Tags: []string{" Chlorophyll "}},
+			Title:            "testQuestion",
+			Points:           1,
+			TimeLimit:        30,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+	})
+	mockedGame.Metadata = game.Metadata{
+		Tags:     []string{"Photosynthesis", "photosynthesis ", "Plants"},
+		Category: " Biology ",
+	}
+	tags := []string{"Cells"}
+	difficulty := game.HardDifficulty
+	other := s.createMockedGame(ownerId)
+
+	// Act
+	createErr := s.svc.CreateNewGame(s.ctx, ownerId, mockedGame)
+	updated, updateErr := s.svc.UpdateGameInfo(
+		s.ctx,
+		ownerId,
+		other.Id,
+		&services.UpdateGameInfoRequest{Tags: &tags, Difficulty: &difficulty},
+	)
+	filtered, filterErr := s.svc.GetGamesByUserId(s.ctx, ownerId, &services.GetGamesRequest{
+		Filter: services.MetadataFilterRequest{Tags: []string{"PLANTS"}, Category: "biology"},
+	})
+	suggested, suggestErr := s.svc.SuggestTags(s.ctx, ownerId, "C")
+	_, badErr := s.svc.GetGamesByUserId(s.ctx, ownerId, &services.GetGamesRequest{
+		Filter: services.MetadataFilterRequest{Difficulty: "impossible"},
+	})
+
+	// Assert
+	assert.NoError(t, createErr)
+	assert.Equal(t, []string{"photosynthesis", "plants"}, mockedGame.Tags)
+	assert.Equal(t, "biology", mockedGame.Category)
+	assert.Equal(t, []string{"chlorophyll"}, mockedGame.Questions[0].GetMetadata().Tags)
+
+	assert.NoError(t, updateErr)
+	assert.Equal(t, []string{"cells"}, updated.Tags)
+	assert.Equal(t, game.HardDifficulty, updated.Difficulty)
+
+	assert.NoError(t, filterErr)
+	assert.Len(t, filtered.Games, 1)
+	assert.Equal(t, mockedGame.Id, filtered.Games[0].Id)
+
+	assert.NoError(t, suggestErr)
+	assert.ElementsMatch(t, []string{"cells", "chlorophyll"}, suggested)
+
+	validatorError := &services.ValidationError{}
+	assert.ErrorAs(t, badErr, &validatorError)
+}
+
+func (s *GameServiceTestSuite) TestGetGamesPages() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	for _, title := range []string{"cells", "atoms", "bees"} {
+		mockedGame := s.generateMockedGame(title, "desc here", ownerId, []game.Question{
+			&game.TrueFalseQuestion{
+				Title:            "testQuestion",
+				Points:           1,
+				TimeLimit:        30,
+				TrueAlternative:  "testTrueAlternative",
+				FalseAlternative: "testFalseAlternative",
+			},
+		})
+		err := s.svc.CreateNewGame(s.ctx, ownerId, mockedGame)
+		assert.NoError(t, err)
+	}
+	titles := func(page *services.GamesPage) []string {
+		titles := make([]string, len(page.Games))
+		for i, g := range page.Games {
+			titles[i] = g.Title
+		}
+		return titles
+	}
+
+	// Act
+	first, firstErr := s.svc.GetGamesByUserId(s.ctx, ownerId, &services.GetGamesRequest{
+		Sort:     ports.TitleGameSort,
+		PageSize: 2,
+	})
+	second, secondErr := s.svc.GetGamesByUserId(s.ctx, ownerId, &services.GetGamesRequest{
+		Sort:     ports.TitleGameSort,
+		PageSize: 2,
+		After:    first.Next,
+	})
+	back, backErr := s.svc.GetGamesByUserId(s.ctx, ownerId, &services.GetGamesRequest{
+		Sort:     ports.TitleGameSort,
+		PageSize: 2,
+		Before:   second.Prev,
+	})
+	recent, recentErr := s.svc.GetGamesByUserId(s.ctx, ownerId, &services.GetGamesRequest{})
+	_, otherSortErr := s.svc.GetGamesByUserId(s.ctx, ownerId, &services.GetGamesRequest{
+		Sort:  ports.CreatedGameSort,
+		After: first.Next,
+	})
+	_, garbageErr := s.svc.GetGamesByUserId(s.ctx, ownerId, &services.GetGamesRequest{
+		After: "garbage",
+	})
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.Equal(t, []string{"atoms", "bees"}, titles(first))
+	assert.Equal(t, 3, first.Total)
+	assert.NotEmpty(t, first.Next)
+	assert.Empty(t, first.Prev)
+
+	assert.NoError(t, secondErr)
+	assert.Equal(t, []string{"cells"}, titles(second))
+	assert.Empty(t, second.Next)
+	assert.NotEmpty(t, second.Prev)
+
+	assert.NoError(t, backErr)
+	assert.Equal(t, []string{"atoms", "bees"}, titles(back))
+	assert.NotEmpty(t, back.Next)
+	assert.Empty(t, back.Prev)
+
+	assert.NoError(t, recentErr)
+	assert.Equal(t, []string{"bees", "atoms", "cells"}, titles(recent))
+
+	validatorError := &services.ValidationError{}
+	assert.ErrorAs(t, otherSortErr, &validatorError)
+	assert.ErrorAs(t, garbageErr, &validatorError)
+}
+
+func (s *GameServiceTestSuite) TestUpdateGameQuestions() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	questions := []game.Question{
+		&game.QuizQuestion{
+			Title:        "quiz",
+			Points:       1,
+			TimeLimit:    30,
+			Alternatives: *s.generateMockedQuizAlternatives(),
+		},
+		&game.TrueFalseQuestion{
+			Title:            "another",
+			Points:           2,
+			TimeLimit:        20,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+	}
+
+	// Act
+	_, err := s.svc.UpdateGameQuestions(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameQuestionsRequest{Questions: questions},
+	)
+
+	// Assert
+	assert.NoError(t, err)
+
+	var titles []string
+	rows, err := s.pool.Query(
+		s.ctx,
+		`SELECT title FROM questions WHERE game_id = $1 ORDER BY "order"`,
+		mockedGame.Id,
+	)
+	assert.NoError(t, err)
+	for rows.Next() {
+		var title string
+		err = rows.Scan(&title)
+		assert.NoError(t, err)
+		titles = append(titles, title)
+	}
+	assert.Equal(t, []string{"quiz", "another"}, titles)
+}
+
+func (s *GameServiceTestSuite) TestPatchGame() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	before, err := s.svc.GetRevisions(s.ctx, ownerId, mockedGame.Id)
+	assert.NoError(t, err)
+	title := "new title"
+	questions := []game.Question{
+		&game.TrueFalseQuestion{
+			Title:            "another",
+			Points:           2,
+			TimeLimit:        20,
+			TrueAlternative:  "testTrueAlternative",
+			FalseAlternative: "testFalseAlternative",
+		},
+	}
+
+	// Act
+	updated, err := s.svc.PatchGame(s.ctx, ownerId, mockedGame.Id, &services.PatchGameRequest{
+		UpdateGameInfoRequest: services.UpdateGameInfoRequest{Title: &title},
+		Questions:             &questions,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, title, updated.Title)
+	assert.Equal(t, mockedGame.Description, updated.Description)
+	assert.Len(t, updated.Questions, 1)
+
+	after, err := s.svc.GetRevisions(s.ctx, ownerId, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, after, len(before)+1)
+}
+
+func (s *GameServiceTestSuite) TestUpdateMalformedGameQuestions() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	incomplete := []game.Question{
+		&game.QuizQuestion{Title: "quiz", Points: 1, TimeLimit: 30},
+	}
+	malformed := []game.Question{
+		&game.QuizQuestion{
+			Title:        strings.Repeat("a", 121),
+			Points:       1,
+			TimeLimit:    30,
+			Alternatives: *s.generateMockedQuizAlternatives(),
+		},
+	}
+
+	// Act
+	_, incompleteErr := s.svc.UpdateGameQuestions(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameQuestionsRequest{Questions: incomplete},
+	)
+	_, malformedErr := s.svc.UpdateGameQuestions(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameQuestionsRequest{Questions: malformed},
+	)
+
+	// Assert
+	assert.NoError(t, incompleteErr)
+	validatorError := &services.ValidationError{}
+	assert.ErrorAs(t, malformedErr, &validatorError)
+
+	found, err := s.svc.GetGameById(s.ctx, ownerId, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "quiz", found.Questions[0].GetTitle())
+}
+
+func (s *GameServiceTestSuite) TestUpdateGameWithBadInput() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	title := "new title"
+	empty := ""
+
+	table := []struct {
+		desc   string
+		userId string
+		gameId uuid.UUID
+		req    *services.UpdateGameInfoRequest
+		err    error
+	}{
+		{
+			desc:   "game owned by someone else",
+			userId: uuid.NewString(),
+			gameId: mockedGame.Id,
+			req:    &services.UpdateGameInfoRequest{Title: &title},
+			err:    ports.ErrNotGameOwner,
+		},
+		{
+			desc:   "unknown game",
+			userId: ownerId,
+			gameId: uuid.New(),
+			req:    &services.UpdateGameInfoRequest{Title: &title},
+			err:    ports.ErrGameNotFound,
+		},
+		{
+			desc:   "empty title",
+			userId: ownerId,
+			gameId: mockedGame.Id,
+			req:    &services.UpdateGameInfoRequest{Title: &empty},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			_, err := s.svc.UpdateGameInfo(s.ctx, tt.userId, tt.gameId, tt.req)
+
+			// Assert
+			assert.Error(t, err)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func (s *GameServiceTestSuite) TestGetGameById() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+
+	// Act
+	found, err := s.svc.GetGameById(s.ctx, ownerId, mockedGame.Id)
+	_, errNotOwner := s.svc.GetGameById(s.ctx, uuid.NewString(), mockedGame.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, mockedGame.Id, found.Id)
+	assert.ErrorIs(t, errNotOwner, ports.ErrNotGameOwner)
+
+	var forbidden *services.ForbiddenError
+	assert.ErrorAs(t, errNotOwner, &forbidden)
+}
+
+func (s *GameServiceTestSuite) TestDeleteGame() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+
+	// Act
+	errNotOwner := s.svc.DeleteGame(s.ctx, uuid.NewString(), mockedGame.Id)
+	err := s.svc.DeleteGame(s.ctx, ownerId, mockedGame.Id)
+
+	// Assert
+	assert.ErrorIs(t, errNotOwner, ports.ErrNotGameOwner)
+	assert.NoError(t, err)
+
+	_, err = s.svc.GetGameById(s.ctx, ownerId, mockedGame.Id)
+	assert.ErrorIs(t, err, ports.ErrGameNotFound)
+}
+
+func (s *GameServiceTestSuite) TestGetSharedGame() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	private := s.createMockedGame(ownerId)
+	unlisted := s.createMockedGame(ownerId)
+	visibility := game.UnlistedVisibility
+	_, err := s.svc.UpdateGameInfo(
+		s.ctx,
+		ownerId,
+		unlisted.Id,
+		&services.UpdateGameInfoRequest{Visibility: &visibility},
+	)
+	assert.NoError(t, err)
+
+	// Act
+	found, err := s.svc.GetSharedGame(s.ctx, unlisted.Id)
+	_, errPrivate := s.svc.GetSharedGame(s.ctx, private.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, game.UnlistedVisibility, found.Visibility)
+	assert.ErrorIs(t, errPrivate, ports.ErrGameNotFound)
+}
+
+func (s *GameServiceTestSuite) TestBrowsePublicGamesWithBadInput() {
+	// Arrange
+	t := s.T()
+
+	table := []struct {
+		desc string
+		req  *services.BrowsePublicGamesRequest
+	}{
+		{
+			desc: "unknown sort",
+			req:  &services.BrowsePublicGamesRequest{Sort: "tubias"},
+		},
+		{
+			desc: "page too big",
+			req:  &services.BrowsePublicGamesRequest{PageSize: 51},
+		},
+		{
+			desc: "negative page",
+			req:  &services.BrowsePublicGamesRequest{Page: -1},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			_, err := s.svc.BrowsePublicGames(s.ctx, tt.req)
+
+			// Assert
+			var validationErr *services.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+		})
+	}
+}
+
+func (s *GameServiceTestSuite) TestCopyGame() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.generateMockedGame("dumb title", "desc here", ownerId, []game.Question{
+		&game.QuizQuestion{
+			Title:        "quiz",
+			Points:       1,
+			TimeLimit:    30,
+			Alternatives: *s.generateMockedQuizAlternatives(),
+		},
+	})
+	mockedGame.Visibility = game.PublicVisibility
+	err := s.svc.CreateNewGame(s.ctx, ownerId, mockedGame)
+	assert.NoError(t, err)
+	forkerId := uuid.NewString()
+
+	// Act
+	fork, err := s.svc.CopyGame(s.ctx, forkerId, mockedGame.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEqual(t, mockedGame.Id, fork.Id)
+	assert.Equal(t, forkerId, fork.OwnerId)
+	assert.Equal(t, game.PrivateVisibility, fork.Visibility)
+	assert.Equal(t, &mockedGame.Id, fork.ForkedFrom)
+
+	found, err := s.svc.GetGameById(s.ctx, forkerId, fork.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, &mockedGame.Id, found.ForkedFrom)
+	assert.Len(t, found.Questions, 1)
+
+	quiz := found.Questions[0].(*game.QuizQuestion)
+	original := mockedGame.Questions[0].(*game.QuizQuestion)
+	assert.Equal(t, original.Alternatives, quiz.Alternatives)
+	assert.NotEqual(t, original.Id, quiz.Id)
+}
+
+func (s *GameServiceTestSuite) TestCopySharedGameWithUnpublishedEdits() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	visibility := game.UnlistedVisibility
+	title := "unpublished title"
+	_, err := s.svc.UpdateGameInfo(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameInfoRequest{Title: &title, Visibility: &visibility},
+	)
+	assert.NoError(t, err)
+
+	draft := s.generateMockedGame("draft title", "desc here", ownerId, nil)
+	draft.Visibility = game.PublicVisibility
+	err = s.svc.CreateDraftGame(s.ctx, ownerId, draft)
+	assert.NoError(t, err)
+
+	// Act
+	fork, forkErr := s.svc.CopyGame(s.ctx, uuid.NewString(), mockedGame.Id)
+	own, ownErr := s.svc.CopyGame(s.ctx, ownerId, mockedGame.Id)
+	_, draftErr := s.svc.CopyGame(s.ctx, uuid.NewString(), draft.Id)
+
+	// Assert
+	assert.NoError(t, forkErr)
+	assert.Equal(t, mockedGame.Title, fork.Title)
+	assert.NoError(t, ownErr)
+	assert.Equal(t, title, own.Title)
+	assert.ErrorIs(t, draftErr, ports.ErrGameNotFound)
+}
+
+func (s *GameServiceTestSuite) TestCopyPrivateGameOfAnotherUser() {
+	// Arrange
+	t := s.T()
+	mockedGame := s.createMockedGame(uuid.NewString())
+
+	// Act
+	_, err := s.svc.CopyGame(s.ctx, uuid.NewString(), mockedGame.Id)
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrNotGameOwner)
+}
+
+func (s *GameServiceTestSuite) TestDraftIsPublishedOnlyWhenValid() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	draft := s.generateMockedGame("dumb title", "", ownerId, []game.Question{})
+
+	// Act
+	err := s.svc.CreateDraftGame(s.ctx, ownerId, draft)
+	assert.NoError(t, err)
+	_, invalidErr := s.svc.PublishGame(s.ctx, ownerId, draft.Id)
+	_, err = s.svc.UpdateGame(s.ctx, ownerId, draft.Id, s.generateMockedGame(
+		"dumb title",
+		"desc here",
+		ownerId,
+		[]game.Question{
+			&game.TrueFalseQuestion{
+				Title:            "testQuestion",
+				Points:           1,
+				TimeLimit:        30,
+				TrueAlternative:  "testTrueAlternative",
+				FalseAlternative: "testFalseAlternative",
+			},
+		},
+	))
+	assert.NoError(t, err)
+	published, err := s.svc.PublishGame(s.ctx, ownerId, draft.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	var validationErr *services.ValidationError
+	assert.ErrorAs(t, invalidErr, &validationErr)
+	assert.Equal(t, game.PublishedStatus, published.Status)
+	assert.NotNil(t, published.PublishedAt)
+	assert.Len(t, published.Questions, 1)
+}
+
+func (s *GameServiceTestSuite) TestEditingPublishedGameKeepsSnapshot() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	title := "new title"
+	visibility := game.PublicVisibility
+
+	// Act
+	updated, err := s.svc.UpdateGameInfo(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameInfoRequest{Title: &title, Visibility: &visibility},
+	)
+	assert.NoError(t, err)
+	shared, err := s.svc.GetSharedGame(s.ctx, mockedGame.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, game.DraftStatus, updated.Status)
+	assert.Equal(t, 2, updated.Revision)
+	assert.Equal(t, "dumb title", shared.Title)
+	assert.Equal(t, 1, shared.Revision)
+}
+
+func (s *GameServiceTestSuite) TestRestoreRevision() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	title := "broken title"
+	_, err := s.svc.UpdateGameInfo(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameInfoRequest{Title: &title},
+	)
+	assert.NoError(t, err)
+	_, err = s.svc.UpdateGameQuestions(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameQuestionsRequest{Questions: []game.Question{}},
+	)
+	assert.NoError(t, err)
+
+	// Act
+	restored, err := s.svc.RestoreRevision(s.ctx, ownerId, mockedGame.Id, 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, mockedGame.Title, restored.Title)
+	assert.Len(t, restored.Questions, 1)
+
+	revisions, err := s.svc.GetRevisions(s.ctx, ownerId, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 4)
+	assert.Equal(t, []string{
+		"restored revision 1",
+		`title changed to "dumb title"`,
+		"question 1 added",
+	}, revisions[0].Summary)
+	assert.Equal(t, []string{"question 1 removed"}, revisions[1].Summary)
+	assert.Equal(t, []string{`title changed to "broken title"`}, revisions[2].Summary)
+	assert.Equal(t, []string{"game created"}, revisions[3].Summary)
+
+	_, err = s.svc.GetRevisions(s.ctx, uuid.NewString(), mockedGame.Id)
+	assert.ErrorIs(t, err, ports.ErrNotGameOwner)
+}
+
+func (s *GameServiceTestSuite) TestRestoreMetadataRevision() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.createMockedGame(ownerId)
+	tags := []string{"plants"}
+	difficulty := game.HardDifficulty
+	_, err := s.svc.UpdateGameInfo(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameInfoRequest{Tags: &tags, Difficulty: &difficulty},
+	)
+	assert.NoError(t, err)
+	empty := []string{}
+	_, err = s.svc.UpdateGameInfo(
+		s.ctx,
+		ownerId,
+		mockedGame.Id,
+		&services.UpdateGameInfoRequest{Tags: &empty},
+	)
+	assert.NoError(t, err)
+
+	// Act
+	restored, err := s.svc.RestoreRevision(s.ctx, ownerId, mockedGame.Id, 2)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, tags, restored.Tags)
+	assert.Equal(t, difficulty, restored.Difficulty)
+
+	revisions, err := s.svc.GetRevisions(s.ctx, ownerId, mockedGame.Id)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 4)
+	assert.Equal(t, []string{"restored revision 2", "tags changed"}, revisions[0].Summary)
+	assert.Equal(t, []string{"tags changed"}, revisions[1].Summary)
+	assert.Equal(t, []string{"tags changed", `difficulty changed to "hard"`}, revisions[2].Summary)
+}
+
+// uploadMockedImage uploads a tiny PNG, only its signature being checked.
+func (s *GameServiceTestSuite) uploadMockedImage(ownerId string) *ports.MediaFile {
+	content := []byte("\x89PNG\r\n\x1a\nnot really an image")
+
+	file, err := s.mediaSvc.UploadMedia(s.ctx, ownerId, int64(len(content)), bytes.NewReader(content))
+	if err != nil {
+		log.Fatalf("error uploading media: %s", err)
+	}
+
+	return file
+}
+
+func (s *GameServiceTestSuite) TestCreateGameWithMedia() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	image := s.uploadMockedImage(ownerId)
+	alternatives := *s.generateMockedQuizAlternatives()
+	alternatives[0].Media = &game.Media{Id: image.Id, Kind: game.ImageMedia}
+	mockedGame := s.generateMockedGame("title", "desc", ownerId, []game.Question{
+		&game.QuizQuestion{
+			Title:        "testQuestion",
+			Points:       1,
+			TimeLimit:    30,
+			Media:        &game.Media{Id: image.Id, Kind: game.ImageMedia},
+			Alternatives: alternatives,
+		},
+	})
+	wrongKind := s.generateMockedGame("title", "desc", ownerId, []game.Question{
+		&game.WordCloudQuestion{
+			Title:     "testQuestion",
+			TimeLimit: 30,
+			Media:     &game.Media{Id: image.Id, Kind: game.VideoMedia},
+		},
+	})
+
+	// Act
+	err := s.svc.CreateNewGame(s.ctx, ownerId, mockedGame)
+	errWrongKind := s.svc.CreateNewGame(s.ctx, ownerId, wrongKind)
+
+	// Assert
+	assert.NoError(t, err)
+	validatorError := &services.ValidationError{}
+	assert.ErrorAs(t, errWrongKind, &validatorError)
+
+	found, err := s.svc.GetGameById(s.ctx, ownerId, mockedGame.Id)
+	assert.NoError(t, err)
+	quiz := found.Questions[0].(*game.QuizQuestion)
+	assert.Equal(t, image.Id, quiz.Media.Id)
+	assert.Equal(t, image.Id, quiz.Alternatives[0].Media.Id)
+	assert.Nil(t, quiz.Alternatives[1].Media)
+}
+
+func (s *GameServiceTestSuite) TestGameWithForeignMedia() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	otherId := uuid.NewString()
+	image := s.uploadMockedImage(ownerId)
+	question := func() game.Question {
+		return &game.TrueFalseQuestion{
+			Title:            "What is shown?",
+			Points:           1,
+			TimeLimit:        30,
+			Media:            &game.Media{Id: image.Id, Kind: game.ImageMedia},
+			TrueAlternative:  "a plant",
+			FalseAlternative: "a cell",
+		}
+	}
+	shared := s.generateMockedGame("title", "desc", ownerId, []game.Question{question()})
+	shared.Visibility = game.PublicVisibility
+	err := s.svc.CreateNewGame(s.ctx, ownerId, shared)
+	assert.NoError(t, err)
+
+	// Act
+	stolenErr := s.svc.CreateNewGame(
+		s.ctx,
+		otherId,
+		s.generateMockedGame("title", "desc", otherId, []game.Question{question()}),
+	)
+	_, bankErr := s.bankSvc.SaveQuestion(s.ctx, otherId, &services.SaveBankQuestionRequest{
+		Question: question(),
+	})
+	fork, copyErr := s.svc.CopyGame(s.ctx, otherId, shared.Id)
+	_, editErr := s.svc.UpdateGameQuestions(s.ctx, otherId, fork.Id, &services.UpdateGameQuestionsRequest{
+		Questions: []game.Question{question()},
+	})
+
+	// Assert
+	validatorError := &services.ValidationError{}
+	assert.ErrorAs(t, stolenErr, &validatorError)
+	assert.ErrorAs(t, bankErr, &validatorError)
+	assert.NoError(t, copyErr)
+	assert.NoError(t, editErr)
+}
+
+func (s *GameServiceTestSuite) TestDeleteGameDeletesOrphanedMedia() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	shared := s.uploadMockedImage(ownerId)
+	orphaned := s.uploadMockedImage(ownerId)
+	questions := func(ids ...uuid.UUID) []game.Question {
+		qs := make([]game.Question, len(ids))
+		for i, id := range ids {
+			qs[i] = &game.WordCloudQuestion{
+				Title:     "testQuestion",
+				TimeLimit: 30,
+				Media:     &game.Media{Id: id, Kind: game.ImageMedia},
+			}
+		}
+		return qs
+	}
+	deleted := s.generateMockedGame("title", "desc", ownerId, questions(shared.Id, orphaned.Id))
+	kept := s.generateMockedGame("title", "desc", ownerId, questions(shared.Id))
+	assert.NoError(t, s.svc.CreateNewGame(s.ctx, ownerId, deleted))
+	assert.NoError(t, s.svc.CreateNewGame(s.ctx, ownerId, kept))
+
+	// Act
+	err := s.svc.DeleteGame(s.ctx, ownerId, deleted.Id)
+
+	// Assert
+	assert.NoError(t, err)
+
+	_, _, err = s.mediaSvc.OpenMedia(s.ctx, orphaned.Id)
+	assert.ErrorIs(t, err, ports.ErrMediaNotFound)
+
+	_, content, err := s.mediaSvc.OpenMedia(s.ctx, shared.Id)
+	assert.NoError(t, err)
+	content.Close()
+}
+
+func (s *GameServiceTestSuite) TestSweepMedia() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	kept := s.uploadMockedImage(ownerId)
+	removed := s.uploadMockedImage(ownerId)
+	unattached := s.uploadMockedImage(ownerId)
+	question := func(id uuid.UUID) game.Question {
+		return &game.WordCloudQuestion{
+			Title:     "testQuestion",
+			TimeLimit: 30,
+			Media:     &game.Media{Id: id, Kind: game.ImageMedia},
+		}
+	}
+	mockedGame := s.generateMockedGame(
+		"title",
+		"desc",
+		ownerId,
+		[]game.Question{question(kept.Id), question(removed.Id)},
+	)
+	assert.NoError(t, s.svc.CreateNewGame(s.ctx, ownerId, mockedGame))
+	_, err := s.svc.UpdateGameQuestions(s.ctx, ownerId, mockedGame.Id, &services.UpdateGameQuestionsRequest{
+		Questions: []game.Question{question(kept.Id)},
+	})
+	assert.NoError(t, err)
+
+	// Act
+	earlyErr := s.svc.SweepMedia(s.ctx, time.Now().Add(-time.Minute))
+	_, _, earlyRemovedErr := s.mediaSvc.OpenMedia(s.ctx, removed.Id)
+	err = s.svc.SweepMedia(s.ctx, time.Now().Add(time.Minute))
+
+	// Assert
+	assert.NoError(t, earlyErr)
+	assert.NoError(t, earlyRemovedErr)
+	assert.NoError(t, err)
+
+	_, _, err = s.mediaSvc.OpenMedia(s.ctx, removed.Id)
+	assert.ErrorIs(t, err, ports.ErrMediaNotFound)
+	_, _, err = s.mediaSvc.OpenMedia(s.ctx, unattached.Id)
+	assert.ErrorIs(t, err, ports.ErrMediaNotFound)
+
+	_, content, err := s.mediaSvc.OpenMedia(s.ctx, kept.Id)
+	assert.NoError(t, err)
+	content.Close()
+}
+
+func (s *GameServiceTestSuite) TestExportAndImportGame() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	importerId := uuid.NewString()
+	image := s.uploadMockedImage(ownerId)
+	mockedGame := s.generateMockedGame("title", "desc", ownerId, []game.Question{
+		&game.WordCloudQuestion{
+			Title:     "testQuestion",
+			TimeLimit: 30,
+			Media:     &game.Media{Id: image.Id, Kind: game.ImageMedia},
+		},
+	})
+	assert.NoError(t, s.svc.CreateNewGame(s.ctx, ownerId, mockedGame))
+
+	// Act
+	exported, err := s.svc.ExportGame(s.ctx, ownerId, mockedGame.Id, true)
+	assert.NoError(t, err)
+	_, errForbidden := s.svc.ExportGame(s.ctx, importerId, mockedGame.Id, true)
+
+	media := make([]services.ImportedMedia, len(exported.Media))
+	for i, m := range exported.Media {
+		media[i] = services.ImportedMedia{Id: m.File.Id, Content: m.Content}
+	}
+	imported, err := s.svc.ImportGame(s.ctx, importerId, &services.ImportGameRequest{
+		Game:  exported.Game,
+		Media: media,
+	})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, errForbidden, ports.ErrNotGameOwner)
+	assert.Len(t, exported.Media, 1)
+	assert.Equal(t, image.Id, exported.Media[0].File.Id)
+
+	found, err := s.svc.GetGameById(s.ctx, importerId, imported.Id)
+	assert.NoError(t, err)
+	assert.NotEqual(t, mockedGame.Id, found.Id)
+	assert.Equal(t, game.PublishedStatus, found.Status)
+	question := found.Questions[0].(*game.WordCloudQuestion)
+	assert.NotEqual(t, mockedGame.Questions[0].(*game.WordCloudQuestion).Id, question.Id)
+	assert.NotEqual(t, image.Id, question.Media.Id)
+
+	_, content, err := s.mediaSvc.OpenMedia(s.ctx, question.Media.Id)
+	assert.NoError(t, err)
+	content.Close()
+}
+
+func (s *GameServiceTestSuite) TestImportGameWithTooMuchMedia() {
+	// Arrange
+	t := s.T()
+	ownerId := uuid.NewString()
+	mockedGame := s.generateMockedGame("title", "desc", ownerId, []game.Question{
+		&game.WordCloudQuestion{Title: "testQuestion", TimeLimit: 30},
+	})
+	content := make([]byte, services.MaxBundledMediaSize/2+1)
+
+	// Act
+	_, err := s.svc.ImportGame(s.ctx, ownerId, &services.ImportGameRequest{
+		Game: mockedGame,
+		Media: []services.ImportedMedia{
+			{Id: uuid.New(), Content: content},
+			{Id: uuid.New(), Content: content},
+		},
+	})
+
+	// Assert
+	validatorError := &services.ValidationError{}
+	assert.ErrorAs(t, err, &validatorError)
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+const (
+	// sniffLength is how much of a file is read to detect its content type
+	sniffLength = 512
+
+	MaxImageSize int64 = 5 << 20
+	MaxAudioSize int64 = 10 << 20
+	MaxVideoSize int64 = 50 << 20
+	// MaxBundledMediaSize is the most media, in bytes, an export may embed so
+	// that it can still be imported back
+	MaxBundledMediaSize int64 = 2 * MaxVideoSize
+
+	// MediaSweepInterval is how often SweepMedia should run, and
+	// MediaGracePeriod how long an upload is kept before it has to be
+	// attached to something
+	MediaSweepInterval = time.Hour
+	MediaGracePeriod   = 24 * time.Hour
+)
+
+// mediaContentTypes are the content types that can be uploaded, by the kind
+// of media they are.
+var mediaContentTypes = map[string]game.MediaKind{
+	"image/png":  game.ImageMedia,
+	"image/jpeg": game.ImageMedia,
+	"image/gif":  game.ImageMedia,
+	"image/webp": game.ImageMedia,
+	"audio/mpeg": game.AudioMedia,
+	"audio/wave": game.AudioMedia,
+	"video/mp4":  game.VideoMedia,
+	"video/webm": game.VideoMedia,
+}
+
+var maxMediaSizes = map[game.MediaKind]int64{
+	game.ImageMedia: MaxImageSize,
+	game.AudioMedia: MaxAudioSize,
+	game.VideoMedia: MaxVideoSize,
+}
+
+type MediaService struct {
+	logger      ports.Logger
+	mediaStorer ports.MediaStorer
+}
+
+func NewMediaService(logger ports.Logger, mediaStorer ports.MediaStorer) *MediaService {
+	return &MediaService{
+		logger:      logger,
+		mediaStorer: mediaStorer,
+	}
+}
+
+// UploadMedia stores a file of size bytes uploaded by userId. Its content
+// type is detected from the content itself rather than trusted from the
+// client.
+func (s *MediaService) UploadMedia(
+	ctx context.Context,
+	userId string,
+	size int64,
+	content io.Reader,
+) (*ports.MediaFile, error) {
+	file, err := storeUpload(ctx, s.mediaStorer, userId, size, content)
+	if err != nil {
+		s.logger.Errorf("Failed to store media %v", err)
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// storeUpload checks the content type and size of an uploaded file before
+// storing it.
+func storeUpload(
+	ctx context.Context,
+	mediaStorer ports.MediaStorer,
+	userId string,
+	size int64,
+	content io.Reader,
+) (*ports.MediaFile, error) {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	kind, ok := mediaContentTypes[contentType]
+	if !ok {
+		return nil, mediaValidationError("ContentType", contentType, "image, audio or video")
+	}
+
+	if size <= 0 || size > maxMediaSizes[kind] {
+		return nil, mediaValidationError("Size", size, fmt.Sprintf("lte=%d", maxMediaSizes[kind]))
+	}
+
+	file := &ports.MediaFile{
+		Id:          uuid.New(),
+		OwnerId:     userId,
+		Kind:        kind,
+		ContentType: contentType,
+		Size:        size,
+		CreatedAt:   time.Now(),
+	}
+
+	err = mediaStorer.StoreMedia(ctx, file, io.MultiReader(bytes.NewReader(head), content))
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// OpenMedia returns an uploaded file along its content, callers must close
+// it.
+func (s *MediaService) OpenMedia(
+	ctx context.Context,
+	id uuid.UUID,
+) (*ports.MediaFile, io.ReadCloser, error) {
+	file, err := s.mediaStorer.FindMedia(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.mediaStorer.OpenMedia(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, content, nil
+}
+
+func mediaValidationError(field string, value any, rule string) *ValidationError {
+	verr := &ValidationError{}
+	verr.AddNewMessage(newErrorMessage(field, value, rule))
+
+	return verr
+}
+
+// checkMedia makes sure every media the questions of the game reference was
+// uploaded, is of the kind the reference says and may be used by userId.
+// Users may use the media they uploaded, and the ones their games or bank
+// already reference, e.g. the media of a game they copied.
+func (s *GameService) checkMedia(ctx context.Context, userId string, g *game.Game) error {
+	verr := &ValidationError{}
+	foreign := []uuid.UUID{}
+
+	for _, q := range g.Questions {
+		for _, m := range game.AttachedMedia(q) {
+			file, err := s.mediaStorer.FindMedia(ctx, m.Id)
+			if errors.Is(err, ports.ErrMediaNotFound) {
+				verr.AddNewMessage(ErrorMessage{
+					Message: fmt.Sprintf("[Media]: '%v' | Needs to implement 'uploaded'", m.Id),
+				})
+				continue
+			}
+			if err != nil {
+				s.logger.Errorf("Failed to find media %v", err)
+				return err
+			}
+
+			if file.Kind != m.Kind {
+				verr.AddNewMessage(ErrorMessage{
+					Message: fmt.Sprintf("[Kind]: '%v' | Needs to implement 'eq=%s'", m.Kind, file.Kind),
+				})
+			}
+
+			if file.OwnerId != userId {
+				foreign = append(foreign, m.Id)
+			}
+		}
+	}
+
+	if len(foreign) > 0 {
+		referenced, err := s.gameStorer.FindMediaReferencedByUser(ctx, userId, foreign)
+		if err != nil {
+			s.logger.Errorf("Failed to find referenced media %v", err)
+			return err
+		}
+
+		for _, id := range foreign {
+			if !slices.Contains(referenced, id) {
+				verr.AddNewMessage(ErrorMessage{
+					Message: fmt.Sprintf("[Media]: '%v' | Needs to implement 'owned'", id),
+				})
+			}
+		}
+	}
+
+	if len(verr.GetMessages()) > 0 {
+		return verr
+	}
+
+	return nil
+}
+
+// deleteOrphanedMedia deletes the media before referenced that no game,
+// revision, published version or bank question references anymore. Files
+// that fail to be deleted are only logged, they are left behind rather than
+// failing the edit that orphaned them.
+func (s *GameService) deleteOrphanedMedia(ctx context.Context, before *game.Game) {
+	ids, err := s.gameStorer.FindUnreferencedMedia(ctx, before.MediaIds())
+	if err != nil {
+		s.logger.Errorf("Failed to find unreferenced media %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		err := s.mediaStorer.DeleteMedia(ctx, id)
+		if err != nil {
+			s.logger.Errorf("Failed to delete media %v", err)
+		}
+	}
+}
+
+// SweepMedia deletes the media stored before the given time that neither a
+// game, be it its current or published version, nor a bank question holds.
+// That reclaims the uploads never attached and the media only revisions keep,
+// restoring such a revision then fails until its media are uploaded again.
+// Files that fail to be deleted are only logged, the next sweep retries them.
+func (s *GameService) SweepMedia(ctx context.Context, before time.Time) error {
+	stored, err := s.mediaStorer.FindMediaStoredBefore(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	ids, err := s.gameStorer.FindUnattachedMedia(ctx, stored)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := s.mediaStorer.DeleteMedia(ctx, id)
+		if err != nil && !errors.Is(err, ports.ErrMediaNotFound) {
+			s.logger.Errorf("Failed to delete media %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/media"
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+	testshelpers "github.com/taldoflemis/brain.test/test/helpers"
+)
+
+func TestUploadMedia(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\nnot really an image")
+	mp3 := append([]byte("ID3"), make([]byte, 64)...)
+	text := []byte("just some text")
+
+	table := []struct {
+		testDescription string
+		content         []byte
+		size            int64
+		kind            game.MediaKind
+		contentType     string
+		valid           bool
+	}{
+		{
+			testDescription: "png image",
+			content:         png,
+			size:            int64(len(png)),
+			kind:            game.ImageMedia,
+			contentType:     "image/png",
+			valid:           true,
+		},
+		{
+			testDescription: "mp3 audio",
+			content:         mp3,
+			size:            int64(len(mp3)),
+			kind:            game.AudioMedia,
+			contentType:     "audio/mpeg",
+			valid:           true,
+		},
+		{
+			testDescription: "text is not media",
+			content:         text,
+			size:            int64(len(text)),
+		},
+		{
+			testDescription: "image bigger than images may be",
+			content:         png,
+			size:            services.MaxImageSize + 1,
+		},
+		{
+			testDescription: "empty file",
+			content:         []byte{},
+			size:            0,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Arrange
+			storer, err := media.NewLocalMediaStorer(t.TempDir())
+			assert.NoError(t, err)
+			svc := services.NewMediaService(testshelpers.NewDummyLogger(log.Writer()), storer)
+			ctx := context.Background()
+			ownerId := uuid.NewString()
+
+			// Act
+			file, err := svc.UploadMedia(ctx, ownerId, tt.size, bytes.NewReader(tt.content))
+
+			// Assert
+			if !tt.valid {
+				validatorError := &services.ValidationError{}
+				assert.ErrorAs(t, err, &validatorError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.kind, file.Kind)
+			assert.Equal(t, tt.contentType, file.ContentType)
+			assert.Equal(t, ownerId, file.OwnerId)
+
+			_, content, err := svc.OpenMedia(ctx, file.Id)
+			assert.NoError(t, err)
+			defer content.Close()
+
+			data, err := io.ReadAll(content)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.content, data)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+// GetRevisions lists every revision of a game owned by userId, newest first.
+func (s *GameService) GetRevisions(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+) ([]*game.Revision, error) {
+	_, err := s.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revisionStorer.FindRevisionsByGameId(ctx, gameId)
+}
+
+// GetRevision returns a past revision of a game owned by userId, including
+// the whole game as it was back then.
+func (s *GameService) GetRevision(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+	number int,
+) (*game.Revision, error) {
+	_, err := s.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.revisionStorer.FindRevision(ctx, gameId, number)
+}
+
+// RestoreRevision makes a past revision the current version of a game owned
+// by userId. Like any other edit, the restored version is a draft until it is
+// published again, and the restore itself is recorded as a new revision.
+func (s *GameService) RestoreRevision(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+	number int,
+) (*game.Game, error) {
+	found, err := s.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := s.revisionStorer.FindRevision(ctx, gameId, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.replaceGame(
+		ctx,
+		userId,
+		found,
+		revision.Game,
+		fmt.Sprintf("restored revision %d", number),
+	)
+}
+
+// findRecordedGame loads a game after userId changed it, recording how it
+// differs from before as a new revision. Notes are put at the top of the
+// summary of the revision.
+func (s *GameService) findRecordedGame(
+	ctx context.Context,
+	userId string,
+	before *game.Game,
+	notes ...string,
+) (*game.Game, error) {
+	after, err := s.gameStorer.FindGameById(ctx, before.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.recordRevision(ctx, userId, before, after, notes...)
+	if err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// recordRevision stores after as a new revision authored by userId. Edits
+// that change nothing are not recorded.
+func (s *GameService) recordRevision(
+	ctx context.Context,
+	userId string,
+	before *game.Game,
+	after *game.Game,
+	notes ...string,
+) error {
+	revision := game.NewRevision(userId, before, after, time.Now())
+	revision.Summary = append(notes, revision.Summary...)
+	if len(revision.Summary) == 0 {
+		return nil
+	}
+
+	err := s.revisionStorer.StoreRevision(ctx, revision)
+	if err != nil {
+		s.logger.Errorf("Failed to store revision %v", err)
+		return err
+	}
+
+	return nil
+}
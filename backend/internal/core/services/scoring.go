@@ -0,0 +1,105 @@
+package services
+
+import (
+	"time"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+type ScoreRequest struct {
+	Policy   *game.ScoringPolicy
+	Question game.Question
+	Answer   any
+	// ResponseTime is how long the player took to answer since the question
+	// started
+	ResponseTime time.Duration
+	// Streak is the amount of consecutive correct answers before this one
+	Streak int
+}
+
+type Score struct {
+	// Correct answers earned full credit, partly right answers still earn
+	// points but break the streak
+	Correct bool
+	// Points awarded for the answer itself, scaled by speed
+	Points int
+	// StreakBonus awarded on top of Points
+	StreakBonus int
+	// Streak is the amount of consecutive correct answers including this one
+	Streak int
+	// Ungraded answers have no right or wrong, they earn no points and leave
+	// the streak as it was
+	Ungraded bool
+}
+
+func (s Score) Total() int {
+	return s.Points + s.StreakBonus
+}
+
+// ScoringService turns answers into points. Scores only depend on the
+// request, and are computed with integer arithmetic, so every session scores
+// the same answer the same way.
+type ScoringService struct{}
+
+func NewScoringService() *ScoringService {
+	return &ScoringService{}
+}
+
+func (s *ScoringService) Score(req *ScoreRequest) Score {
+	policy := req.Policy
+	if policy == nil {
+		policy = game.DefaultScoringPolicy()
+	}
+
+	if !game.IsGraded(req.Question) {
+		return Score{Streak: req.Streak, Ungraded: true}
+	}
+
+	credit := 0
+	if partial, ok := req.Question.(game.PartialCreditQuestion); ok {
+		credit = partial.PartialCredit(req.Answer)
+	} else if req.Question.IsCorrect(req.Answer) {
+		credit = game.FullCredit
+	}
+
+	if credit <= 0 {
+		return Score{}
+	}
+
+	correct := credit >= game.FullCredit
+	streak := 0
+	if correct {
+		streak = req.Streak + 1
+	}
+
+	base := int64(policy.BasePoints) * int64(req.Question.GetPoints()) *
+		int64(min(credit, game.FullCredit)) / game.FullCredit
+	limit := time.Duration(req.Question.GetTimeLimit()) * time.Second
+
+	elapsed := req.ResponseTime
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed > limit {
+		elapsed = limit
+	}
+
+	// the slower the answer, the more of the speed dependent share is lost
+	speedShare := base * int64(policy.SpeedWeight) / 100
+	lost := int64(0)
+	if limit > 0 {
+		lost = speedShare * elapsed.Milliseconds() / limit.Milliseconds()
+	}
+
+	bonus := 0
+	if base > 0 && correct {
+		bonus = min(policy.StreakBonus*(streak-1), policy.MaxStreakBonus)
+	}
+
+	return Score{
+		Correct:     correct,
+		Points:      int(base - lost),
+		StreakBonus: bonus,
+		Streak:      streak,
+	}
+}
@@ -0,0 +1,637 @@
+package services_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+)
+
+func newScoringQuestion(points game.Points) *game.TrueFalseQuestion {
+	return &game.TrueFalseQuestion{
+		Title:            "testQuestion",
+		Points:           points,
+		TimeLimit:        20,
+		TrueAlternative:  "testTrueAlternative",
+		FalseAlternative: "testFalseAlternative",
+	}
+}
+
+func TestScore(t *testing.T) {
+	svc := services.NewScoringService()
+	policy := &game.ScoringPolicy{
+		BasePoints:     1000,
+		SpeedWeight:    50,
+		StreakBonus:    100,
+		MaxStreakBonus: 300,
+	}
+
+	table := []struct {
+		desc     string
+		req      *services.ScoreRequest
+		expected services.Score
+	}{
+		{
+			desc: "wrong answer",
+			req: &services.ScoreRequest{
+				Policy:       policy,
+				Question:     newScoringQuestion(1),
+				Answer:       "testFalseAlternative",
+				ResponseTime: time.Second,
+				Streak:       3,
+			},
+			expected: services.Score{},
+		},
+		{
+			desc: "instant answer",
+			req: &services.ScoreRequest{
+				Policy:   policy,
+				Question: newScoringQuestion(1),
+				Answer:   "testTrueAlternative",
+			},
+			expected: services.Score{Correct: true, Points: 1000, Streak: 1},
+		},
+		{
+			desc: "answer at half the time limit",
+			req: &services.ScoreRequest{
+				Policy:       policy,
+				Question:     newScoringQuestion(1),
+				Answer:       "testTrueAlternative",
+				ResponseTime: 10 * time.Second,
+			},
+			expected: services.Score{Correct: true, Points: 750, Streak: 1},
+		},
+		{
+			desc: "answer at the time limit",
+			req: &services.ScoreRequest{
+				Policy:       policy,
+				Question:     newScoringQuestion(1),
+				Answer:       "testTrueAlternative",
+				ResponseTime: 20 * time.Second,
+			},
+			expected: services.Score{Correct: true, Points: 500, Streak: 1},
+		},
+		{
+			desc: "answer after the time limit",
+			req: &services.ScoreRequest{
+				Policy:       policy,
+				Question:     newScoringQuestion(1),
+				Answer:       "testTrueAlternative",
+				ResponseTime: time.Minute,
+			},
+			expected: services.Score{Correct: true, Points: 500, Streak: 1},
+		},
+		{
+			desc: "double points question",
+			req: &services.ScoreRequest{
+				Policy:       policy,
+				Question:     newScoringQuestion(2),
+				Answer:       "testTrueAlternative",
+				ResponseTime: 10 * time.Second,
+			},
+			expected: services.Score{Correct: true, Points: 1500, Streak: 1},
+		},
+		{
+			desc: "question without points",
+			req: &services.ScoreRequest{
+				Policy:   policy,
+				Question: newScoringQuestion(0),
+				Answer:   "testTrueAlternative",
+				Streak:   2,
+			},
+			expected: services.Score{Correct: true, Points: 0, Streak: 3},
+		},
+		{
+			desc: "second answer in a streak",
+			req: &services.ScoreRequest{
+				Policy:   policy,
+				Question: newScoringQuestion(1),
+				Answer:   "testTrueAlternative",
+				Streak:   1,
+			},
+			expected: services.Score{Correct: true, Points: 1000, StreakBonus: 100, Streak: 2},
+		},
+		{
+			desc: "streak bonus is capped",
+			req: &services.ScoreRequest{
+				Policy:   policy,
+				Question: newScoringQuestion(1),
+				Answer:   "testTrueAlternative",
+				Streak:   9,
+			},
+			expected: services.Score{Correct: true, Points: 1000, StreakBonus: 300, Streak: 10},
+		},
+		{
+			desc: "policy without speed weight",
+			req: &services.ScoreRequest{
+				Policy:       &game.ScoringPolicy{BasePoints: 100},
+				Question:     newScoringQuestion(1),
+				Answer:       "testTrueAlternative",
+				ResponseTime: 19 * time.Second,
+				Streak:       4,
+			},
+			expected: services.Score{Correct: true, Points: 100, Streak: 5},
+		},
+		{
+			desc: "default policy",
+			req: &services.ScoreRequest{
+				Question:     newScoringQuestion(1),
+				Answer:       "testTrueAlternative",
+				ResponseTime: 10 * time.Second,
+			},
+			expected: services.Score{Correct: true, Points: 750, Streak: 1},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			score := svc.Score(tt.req)
+
+			// Assert
+			assert.Equal(t, tt.expected, score)
+			assert.Equal(t, score.Points+score.StreakBonus, score.Total())
+		})
+	}
+}
+
+func TestScoreIsDeterministic(t *testing.T) {
+	// Arrange
+	svc := services.NewScoringService()
+	req := &services.ScoreRequest{
+		Question:     newScoringQuestion(2),
+		Answer:       "testTrueAlternative",
+		ResponseTime: 7*time.Second + 333*time.Millisecond,
+		Streak:       2,
+	}
+
+	// Act
+	first := svc.Score(req)
+	second := svc.Score(req)
+
+	// Assert
+	assert.Equal(t, first, second)
+}
+
+func newMultiSelectQuestion(credit game.CreditPolicy) *game.QuizQuestion {
+	return &game.QuizQuestion{
+		Title:     "testQuestion",
+		Points:    1,
+		TimeLimit: 20,
+		Mode:      game.MultiSelectQuizMode,
+		Credit:    credit,
+		Alternatives: []game.Alternative{
+			{Data: "first", IsCorrect: true},
+			{Data: "second", IsCorrect: true},
+			{Data: "third"},
+			{Data: "fourth"},
+		},
+	}
+}
+
+func TestScoreQuizQuestion(t *testing.T) {
+	svc := services.NewScoringService()
+	single := newMultiSelectQuestion("")
+	single.Mode = game.SingleChoiceQuizMode
+	single.Alternatives[1].IsCorrect = false
+
+	table := []struct {
+		desc     string
+		question *game.QuizQuestion
+		answer   any
+		expected services.Score
+	}{
+		{
+			desc:     "single choice right pick",
+			question: single,
+			answer:   0,
+			expected: services.Score{Correct: true, Points: 1000, StreakBonus: 300, Streak: 4},
+		},
+		{
+			desc:     "single choice with many picks",
+			question: single,
+			answer:   []int{0, 1},
+			expected: services.Score{},
+		},
+		{
+			desc:     "all or nothing exact picks",
+			question: newMultiSelectQuestion(game.AllOrNothingCredit),
+			answer:   []int{1, 0},
+			expected: services.Score{Correct: true, Points: 1000, StreakBonus: 300, Streak: 4},
+		},
+		{
+			desc:     "all or nothing missing a pick",
+			question: newMultiSelectQuestion(game.AllOrNothingCredit),
+			answer:   []int{0},
+			expected: services.Score{},
+		},
+		{
+			desc:     "proportional with a wrong pick",
+			question: newMultiSelectQuestion(game.ProportionalCredit),
+			answer:   []int{0, 1, 2},
+			expected: services.Score{Points: 750},
+		},
+		{
+			desc:     "penalty with a wrong pick",
+			question: newMultiSelectQuestion(game.PenaltyCredit),
+			answer:   []int{0, 2},
+			expected: services.Score{},
+		},
+		{
+			desc:     "penalty with a missing pick",
+			question: newMultiSelectQuestion(game.PenaltyCredit),
+			answer:   []int{1},
+			expected: services.Score{Points: 500},
+		},
+		{
+			desc:     "index out of range",
+			question: newMultiSelectQuestion(game.ProportionalCredit),
+			answer:   []int{0, 4},
+			expected: services.Score{},
+		},
+		{
+			desc:     "empty answer",
+			question: newMultiSelectQuestion(game.ProportionalCredit),
+			answer:   []int{},
+			expected: services.Score{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			score := svc.Score(&services.ScoreRequest{
+				Question: tt.question,
+				Answer:   tt.answer,
+				Streak:   3,
+			})
+
+			// Assert
+			assert.Equal(t, tt.expected, score)
+		})
+	}
+}
+
+func TestScoreTypeAnswerQuestion(t *testing.T) {
+	svc := services.NewScoringService()
+
+	table := []struct {
+		desc     string
+		question *game.TypeAnswerQuestion
+		answer   any
+		correct  bool
+	}{
+		{
+			desc:     "exact answer",
+			question: &game.TypeAnswerQuestion{AcceptedAnswers: []string{"Brasília"}},
+			answer:   "Brasília",
+			correct:  true,
+		},
+		{
+			desc:     "case, accents and spaces are ignored",
+			question: &game.TypeAnswerQuestion{AcceptedAnswers: []string{"Brasília"}},
+			answer:   "  BRASILIA ",
+			correct:  true,
+		},
+		{
+			desc: "case sensitive",
+			question: &game.TypeAnswerQuestion{
+				AcceptedAnswers: []string{"Brasília"},
+				CaseSensitive:   true,
+			},
+			answer: "brasília",
+		},
+		{
+			desc: "accent sensitive",
+			question: &game.TypeAnswerQuestion{
+				AcceptedAnswers: []string{"Brasília"},
+				AccentSensitive: true,
+			},
+			answer: "Brasilia",
+		},
+		{
+			desc:     "typo without tolerance",
+			question: &game.TypeAnswerQuestion{AcceptedAnswers: []string{"necessary"}},
+			answer:   "neccessary",
+		},
+		{
+			desc: "typo within tolerance",
+			question: &game.TypeAnswerQuestion{
+				AcceptedAnswers: []string{"necessary"},
+				Tolerance:       1,
+			},
+			answer:  "neccessary",
+			correct: true,
+		},
+		{
+			desc: "any of the accepted answers",
+			question: &game.TypeAnswerQuestion{
+				AcceptedAnswers: []string{"color", "colour"},
+			},
+			answer:  "Colour",
+			correct: true,
+		},
+		{
+			desc:     "pattern",
+			question: &game.TypeAnswerQuestion{Pattern: `colou?r`},
+			answer:   "COLOR",
+			correct:  true,
+		},
+		{
+			desc:     "pattern must match the whole answer",
+			question: &game.TypeAnswerQuestion{Pattern: `colou?r`},
+			answer:   "colorful",
+		},
+		{
+			desc:     "empty answer",
+			question: &game.TypeAnswerQuestion{Pattern: `.*`},
+			answer:   " ",
+		},
+		{
+			desc:     "answer that isn't text",
+			question: &game.TypeAnswerQuestion{AcceptedAnswers: []string{"1"}},
+			answer:   1,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Arrange
+			tt.question.Points = 1
+			tt.question.TimeLimit = 20
+
+			// Act
+			score := svc.Score(&services.ScoreRequest{
+				Question: tt.question,
+				Answer:   tt.answer,
+			})
+
+			// Assert
+			assert.Equal(t, tt.correct, score.Correct)
+		})
+	}
+}
+
+func TestScoreTypeAnswerPatternOnceCompiled(t *testing.T) {
+	// Arrange
+	svc := services.NewScoringService()
+	question := &game.TypeAnswerQuestion{Pattern: `colou?r`, Points: 1, TimeLimit: 20}
+
+	// Act
+	var wg sync.WaitGroup
+	scores := make([]services.Score, 10)
+	for i := range scores {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			scores[i] = svc.Score(&services.ScoreRequest{Question: question, Answer: "colour"})
+		}(i)
+	}
+	wg.Wait()
+
+	question.Pattern = `grey`
+	changed := svc.Score(&services.ScoreRequest{Question: question, Answer: "colour"})
+	clone := question.Clone().(*game.TypeAnswerQuestion)
+	clone.CaseSensitive = true
+	cloned := svc.Score(&services.ScoreRequest{Question: clone, Answer: "GREY"})
+
+	// Assert
+	for _, score := range scores {
+		assert.True(t, score.Correct)
+	}
+	assert.False(t, changed.Correct)
+	assert.False(t, cloned.Correct)
+}
+
+func TestScoreOrderingQuestion(t *testing.T) {
+	svc := services.NewScoringService()
+	items := []string{"first", "second", "third", "fourth"}
+
+	table := []struct {
+		desc     string
+		partial  bool
+		answer   any
+		expected services.Score
+	}{
+		{
+			desc:     "right sequence",
+			answer:   []string{"first", "second", "third", "fourth"},
+			expected: services.Score{Correct: true, Points: 1000, Streak: 1},
+		},
+		{
+			desc:     "swapped items",
+			answer:   []string{"second", "first", "third", "fourth"},
+			expected: services.Score{},
+		},
+		{
+			desc:     "swapped items with partial scoring",
+			partial:  true,
+			answer:   []string{"second", "first", "third", "fourth"},
+			expected: services.Score{Points: 500},
+		},
+		{
+			desc:     "repeated item with partial scoring",
+			partial:  true,
+			answer:   []string{"first", "first", "third", "fourth"},
+			expected: services.Score{},
+		},
+		{
+			desc:     "missing item",
+			answer:   []string{"first", "second", "third"},
+			expected: services.Score{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Arrange
+			question := &game.OrderingQuestion{
+				Title:          "testQuestion",
+				Points:         1,
+				TimeLimit:      20,
+				Items:          items,
+				PartialScoring: tt.partial,
+			}
+
+			// Act
+			score := svc.Score(&services.ScoreRequest{
+				Question: question,
+				Answer:   tt.answer,
+			})
+
+			// Assert
+			assert.Equal(t, tt.expected, score)
+		})
+	}
+}
+
+func TestScoreSliderQuestion(t *testing.T) {
+	svc := services.NewScoringService()
+
+	table := []struct {
+		desc         string
+		proportional bool
+		answer       any
+		expected     services.Score
+	}{
+		{
+			desc:     "exact answer",
+			answer:   100.0,
+			expected: services.Score{Correct: true, Points: 1000, Streak: 1},
+		},
+		{
+			desc:     "answer within the tolerance",
+			answer:   90.0,
+			expected: services.Score{Correct: true, Points: 1000, Streak: 1},
+		},
+		{
+			desc:     "answer outside of the tolerance",
+			answer:   130.0,
+			expected: services.Score{},
+		},
+		{
+			desc:         "proportional answer outside of the tolerance",
+			proportional: true,
+			answer:       130.0,
+			expected:     services.Score{Points: 770},
+		},
+		{
+			desc:         "proportional answer at the farthest end",
+			proportional: true,
+			answer:       0,
+			expected:     services.Score{},
+		},
+		{
+			desc:         "answer between steps",
+			proportional: true,
+			answer:       97.5,
+			expected:     services.Score{},
+		},
+		{
+			desc:         "answer out of range",
+			proportional: true,
+			answer:       200.5,
+			expected:     services.Score{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Arrange
+			question := &game.SliderQuestion{
+				Title:               "testQuestion",
+				Points:              1,
+				TimeLimit:           20,
+				Min:                 0,
+				Max:                 200,
+				Step:                5,
+				Target:              100,
+				Tolerance:           10,
+				ProportionalScoring: tt.proportional,
+			}
+
+			// Act
+			score := svc.Score(&services.ScoreRequest{
+				Question: question,
+				Answer:   tt.answer,
+			})
+
+			// Assert
+			assert.Equal(t, tt.expected, score)
+		})
+	}
+}
+
+func TestScoreUngradedQuestion(t *testing.T) {
+	svc := services.NewScoringService()
+
+	table := []struct {
+		desc     string
+		question game.Question
+		answer   any
+	}{
+		{
+			desc: "poll",
+			question: &game.PollQuestion{
+				Title:     "testQuestion",
+				TimeLimit: 20,
+				Options:   []string{"a", "b"},
+			},
+			answer: 1,
+		},
+		{
+			desc: "word cloud",
+			question: &game.WordCloudQuestion{
+				Title:     "testQuestion",
+				TimeLimit: 20,
+			},
+			answer: "answer",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			score := svc.Score(&services.ScoreRequest{
+				Question: tt.question,
+				Answer:   tt.answer,
+				Streak:   3,
+			})
+
+			// Assert
+			assert.False(t, game.IsGraded(tt.question))
+			assert.Equal(t, services.Score{Streak: 3, Ungraded: true}, score)
+		})
+	}
+}
+
+func TestTallyUngradedQuestion(t *testing.T) {
+	table := []struct {
+		desc     string
+		question game.UngradedQuestion
+		answers  []any
+		expected []game.Tally
+	}{
+		{
+			desc:     "poll keeps every option in order",
+			question: &game.PollQuestion{Options: []string{"a", "b", "c"}},
+			answers:  []any{2, 0, 2, 7, "b"},
+			expected: []game.Tally{
+				{Value: "a", Count: 1},
+				{Value: "b", Count: 0},
+				{Value: "c", Count: 2},
+			},
+		},
+		{
+			desc:     "word cloud sorts by frequency",
+			question: &game.WordCloudQuestion{},
+			answers:  []any{"Blue", "red", " blue ", "green", "  ", 1, "dark  red", "Dark red"},
+			expected: []game.Tally{
+				{Value: "blue", Count: 2},
+				{Value: "dark red", Count: 2},
+				{Value: "green", Count: 1},
+				{Value: "red", Count: 1},
+			},
+		},
+		{
+			desc:     "word cloud without answers",
+			question: &game.WordCloudQuestion{},
+			answers:  []any{},
+			expected: []game.Tally{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			tallies := tt.question.Tally(tt.answers)
+
+			// Assert
+			assert.Equal(t, tt.expected, tallies)
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/result_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/domain/session_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+const (
+	pinLength        = 6
+	maxPinGeneration = 10
+	playerTokenBytes = 24
+)
+
+type JoinSessionRequest struct {
+	Nickname string `validate:"required,gte=1,lte=20"`
+}
+
+type SessionService struct {
+	logger            ports.Logger
+	validationService *ValidationService
+	scoringService    *ScoringService
+	gameStorer        ports.GameStorer
+	sessionStorer     ports.SessionStorer
+	resultStorer      ports.ResultStorer
+	publisher         ports.SessionPublisher
+}
+
+func NewSessionService(
+	logger ports.Logger,
+	validationService *ValidationService,
+	scoringService *ScoringService,
+	gameStorer ports.GameStorer,
+	sessionStorer ports.SessionStorer,
+	resultStorer ports.ResultStorer,
+	publisher ports.SessionPublisher,
+) *SessionService {
+	return &SessionService{
+		logger:            logger,
+		validationService: validationService,
+		scoringService:    scoringService,
+		gameStorer:        gameStorer,
+		sessionStorer:     sessionStorer,
+		resultStorer:      resultStorer,
+		publisher:         publisher,
+	}
+}
+
+func (s *SessionService) StartSession(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+) (*session.Session, error) {
+	g, err := s.gameStorer.FindPublishedGame(ctx, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorizeGameOwner(userId, g)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < maxPinGeneration; i++ {
+		pin, err := generatePin()
+		if err != nil {
+			return nil, err
+		}
+
+		candidate := session.NewSession(pin, userId, g, time.Now())
+		err = s.sessionStorer.StoreSession(ctx, candidate)
+		if errors.Is(err, ports.ErrPinAlreadyInUse) {
+			continue
+		}
+		if err != nil {
+			s.logger.Errorf("Failed to store session %v", err)
+			return nil, err
+		}
+
+		return candidate, nil
+	}
+
+	s.logger.Errorf("Failed to generate an unused pin after %d tries", maxPinGeneration)
+	return nil, ports.ErrPinAlreadyInUse
+}
+
+func (s *SessionService) JoinSession(
+	ctx context.Context,
+	pin string,
+	req *JoinSessionRequest,
+) (*session.Player, error) {
+	err := s.validationService.Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := generatePlayerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	player := &session.Player{
+		Id:       uuid.New(),
+		Nickname: req.Nickname,
+		Token:    token,
+		JoinedAt: time.Now(),
+	}
+
+	sess, err := s.sessionStorer.UpdateSession(ctx, pin, func(sess *session.Session) error {
+		if sess.State != session.LobbyState {
+			return ports.ErrSessionAlreadyStarted
+		}
+
+		if sess.HasNickname(req.Nickname) {
+			return ports.ErrNicknameTaken
+		}
+
+		sess.Players = append(sess.Players, player)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publisher.Publish(session.Event{
+		Kind:     session.PlayerJoinedEvent,
+		Session:  sess,
+		PlayerId: player.Id,
+	})
+
+	return player, nil
+}
+
+// AuthenticatePlayer finds the player that owns a session-scoped token.
+func (s *SessionService) AuthenticatePlayer(
+	ctx context.Context,
+	pin string,
+	token string,
+) (*session.Player, error) {
+	sess, err := s.sessionStorer.FindSessionByPin(ctx, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	player := sess.FindPlayerByToken(token)
+	if token == "" || player == nil {
+		return nil, ports.ErrPlayerNotFound
+	}
+
+	return player, nil
+}
+
+// GetHostedSession returns the session only if userId is its host.
+func (s *SessionService) GetHostedSession(
+	ctx context.Context,
+	userId string,
+	pin string,
+) (*session.Session, error) {
+	sess, err := s.sessionStorer.FindSessionByPin(ctx, pin)
+	if err != nil {
+		return nil, err
+	}
+
+	if sess.HostId != userId {
+		return nil, ports.ErrNotSessionHost
+	}
+
+	return sess, nil
+}
+
+// NextQuestion moves the session forward, starting the first question when
+// the session is still in the lobby and finishing it after the last one, in
+// which case its results are stored and the session is let go of. Should
+// storing the results fail the session is put back as it was, so that the
+// host can try again.
+func (s *SessionService) NextQuestion(
+	ctx context.Context,
+	userId string,
+	pin string,
+) (*session.Session, error) {
+	var previousState session.State
+	var previousQuestion int
+
+	sess, err := s.sessionStorer.UpdateSession(ctx, pin, func(sess *session.Session) error {
+		if sess.HostId != userId {
+			return ports.ErrNotSessionHost
+		}
+
+		if sess.State == session.FinishedState {
+			return ports.ErrSessionFinished
+		}
+
+		previousState = sess.State
+		previousQuestion = sess.CurrentQuestion
+
+		if !sess.HasNextQuestion() {
+			// the results are stored once the session is let go, finished
+			// sessions already refuse to move forward or take answers
+			sess.State = session.FinishedState
+			sess.CurrentQuestion = session.NoQuestion
+			return nil
+		}
+
+		sess.State = session.QuestionState
+		sess.CurrentQuestion++
+		sess.QuestionStartedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if sess.State == session.FinishedState {
+		err = s.resultStorer.StoreResult(ctx, result.NewSessionResult(sess, time.Now()))
+		if err != nil {
+			s.logger.Errorf("Failed to store results of session %s: %v", pin, err)
+			s.reopenSession(ctx, pin, previousState, previousQuestion)
+			return nil, err
+		}
+
+		// its results are stored, nothing is left to be done with it
+		err = s.sessionStorer.DeleteSession(ctx, pin)
+		if err != nil && !errors.Is(err, ports.ErrSessionNotFound) {
+			s.logger.Errorf("Failed to delete finished session %s: %v", pin, err)
+		}
+
+		s.publisher.Publish(session.Event{Kind: session.GameOverEvent, Session: sess})
+
+		return sess, nil
+	}
+
+	s.publisher.Publish(session.Event{Kind: session.QuestionStartedEvent, Session: sess})
+
+	question := sess.CurrentQuestion
+	time.AfterFunc(time.Until(sess.Deadline()), func() {
+		err := s.TimeUp(context.Background(), pin, question)
+		if err != nil && !errors.Is(err, ports.ErrSessionNotFound) {
+			s.logger.Errorf("Failed to close question %d of session %s: %v", question, pin, err)
+		}
+	})
+
+	return sess, nil
+}
+
+// reopenSession puts a session whose results couldn't be stored back in the
+// state it finished from.
+func (s *SessionService) reopenSession(
+	ctx context.Context,
+	pin string,
+	state session.State,
+	question int,
+) {
+	_, err := s.sessionStorer.UpdateSession(ctx, pin, func(sess *session.Session) error {
+		sess.State = state
+		sess.CurrentQuestion = question
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to reopen session %s: %v", pin, err)
+	}
+}
+
+// SubmitAnswer records the answer of a player to the running question. The
+// question is closed early once every player has answered it.
+func (s *SessionService) SubmitAnswer(
+	ctx context.Context,
+	pin string,
+	playerId uuid.UUID,
+	question int,
+	value any,
+) (*session.Answer, error) {
+	var answer *session.Answer
+	now := time.Now()
+
+	sess, err := s.sessionStorer.UpdateSession(ctx, pin, func(sess *session.Session) error {
+		player := sess.FindPlayer(playerId)
+		if player == nil {
+			return ports.ErrPlayerNotFound
+		}
+
+		if sess.State != session.QuestionState || sess.CurrentQuestion != question ||
+			now.After(sess.Deadline()) {
+			return ports.ErrQuestionNotRunning
+		}
+
+		if sess.FindAnswer(playerId, question) != nil {
+			return ports.ErrAlreadyAnswered
+		}
+
+		responseTime := now.Sub(sess.QuestionStartedAt)
+		score := s.scoringService.Score(&ScoreRequest{
+			Policy:       sess.Game.ScoringPolicyOrDefault(),
+			Question:     sess.Question(),
+			Answer:       value,
+			ResponseTime: responseTime,
+			Streak:       player.Streak,
+		})
+
+		answer = &session.Answer{
+			PlayerId:     playerId,
+			Question:     question,
+			Value:        value,
+			Correct:      score.Correct,
+			Ungraded:     score.Ungraded,
+			Points:       score.Total(),
+			ResponseTime: responseTime,
+			AnsweredAt:   now,
+		}
+		sess.Answers = append(sess.Answers, answer)
+		player.Score += score.Total()
+		player.Streak = score.Streak
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publisher.Publish(session.Event{
+		Kind:     session.AnswerAcceptedEvent,
+		Session:  sess,
+		PlayerId: playerId,
+		Answer:   answer,
+	})
+
+	if len(sess.AnswersTo(question)) == len(sess.Players) {
+		err = s.TimeUp(ctx, pin, question)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return answer, nil
+}
+
+// TimeUp stops accepting answers to the given question and shows the
+// leaderboard. Closing a question that isn't running anymore does nothing, so
+// it is safe to call once its timer fires even if it was already closed.
+func (s *SessionService) TimeUp(ctx context.Context, pin string, question int) error {
+	closed := false
+
+	sess, err := s.sessionStorer.UpdateSession(ctx, pin, func(sess *session.Session) error {
+		if sess.State != session.QuestionState || sess.CurrentQuestion != question {
+			return nil
+		}
+
+		sess.State = session.ResultsState
+		closed = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !closed {
+		return nil
+	}
+
+	s.publisher.Publish(session.Event{Kind: session.TimeUpEvent, Session: sess})
+	s.publisher.Publish(session.Event{Kind: session.LeaderboardEvent, Session: sess})
+
+	return nil
+}
+
+func (s *SessionService) GetSession(
+	ctx context.Context,
+	pin string,
+) (*session.Session, error) {
+	return s.sessionStorer.FindSessionByPin(ctx, pin)
+}
+
+func (s *SessionService) CloseSession(
+	ctx context.Context,
+	userId string,
+	pin string,
+) error {
+	sess, err := s.sessionStorer.FindSessionByPin(ctx, pin)
+	if err != nil {
+		return err
+	}
+
+	if sess.HostId != userId {
+		return ports.ErrNotSessionHost
+	}
+
+	err = s.sessionStorer.DeleteSession(ctx, pin)
+	if err != nil {
+		return err
+	}
+
+	if sess.State != session.FinishedState {
+		sess.State = session.FinishedState
+		sess.CurrentQuestion = session.NoQuestion
+		s.publisher.Publish(session.Event{Kind: session.GameOverEvent, Session: sess})
+	}
+
+	return nil
+}
+
+func generatePin() (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(pinLength), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", pinLength, n), nil
+}
+
+func generatePlayerToken() (string, error) {
+	b := make([]byte, playerTokenBytes)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
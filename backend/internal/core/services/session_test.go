@@ -0,0 +1,584 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/taldoflemis/brain.test/internal/adapters/driven/memory"
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/domain/result_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/domain/session_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+	"github.com/taldoflemis/brain.test/internal/ports"
+	testshelpers "github.com/taldoflemis/brain.test/test/helpers"
+)
+
+// gameStorerStub keeps games in memory so that sessions can be exercised
+// without a database; any method it doesn't override panics.
+type gameStorerStub struct {
+	ports.GameStorer
+	games map[uuid.UUID]*game.Game
+}
+
+func (g *gameStorerStub) FindGameById(ctx context.Context, id uuid.UUID) (*game.Game, error) {
+	found, ok := g.games[id]
+	if !ok {
+		return nil, ports.ErrGameNotFound
+	}
+
+	return found, nil
+}
+
+func (g *gameStorerStub) FindPublishedGame(ctx context.Context, id uuid.UUID) (*game.Game, error) {
+	return g.FindGameById(ctx, id)
+}
+
+// publisherStub records every event published by the session service.
+type publisherStub struct {
+	mu     sync.Mutex
+	events []session.Event
+}
+
+func (p *publisherStub) Publish(event session.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.events = append(p.events, event)
+}
+
+func (p *publisherStub) kinds() []session.EventKind {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	kinds := make([]session.EventKind, len(p.events))
+	for i, e := range p.events {
+		kinds[i] = e.Kind
+	}
+
+	return kinds
+}
+
+// resultStorerStub keeps the stored results in memory; any method it doesn't
+// override panics.
+type resultStorerStub struct {
+	ports.ResultStorer
+	results []*result.SessionResult
+	// err, when set, is returned instead of storing the result
+	err error
+	// storing, when set, is told about every result about to be stored,
+	// which waits until release is closed
+	storing chan struct{}
+	release chan struct{}
+}
+
+func (r *resultStorerStub) StoreResult(ctx context.Context, res *result.SessionResult) error {
+	if r.storing != nil {
+		r.storing <- struct{}{}
+		<-r.release
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+
+	r.results = append(r.results, res)
+	return nil
+}
+
+type SessionServiceTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	gameStorer   *gameStorerStub
+	resultStorer *resultStorerStub
+	publisher    *publisherStub
+	svc          *services.SessionService
+}
+
+func (s *SessionServiceTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.gameStorer = &gameStorerStub{games: make(map[uuid.UUID]*game.Game)}
+	s.resultStorer = &resultStorerStub{}
+	s.publisher = &publisherStub{}
+
+	logger := testshelpers.NewDummyLogger(log.Writer())
+	s.svc = services.NewSessionService(
+		logger,
+		services.NewValidationService(),
+		services.NewScoringService(),
+		s.gameStorer,
+		memory.NewInMemorySessionStorer(),
+		s.resultStorer,
+		s.publisher,
+	)
+}
+
+func TestSessionService(t *testing.T) {
+	suite.Run(t, new(SessionServiceTestSuite))
+}
+
+func (s *SessionServiceTestSuite) storeMockedGame(ownerId string) *game.Game {
+	mockedGame := &game.Game{
+		Id:          uuid.New(),
+		Title:       "session game",
+		Description: "session game description",
+		OwnerId:     ownerId,
+		Questions: []game.Question{
+			&game.TrueFalseQuestion{
+				Title:            "testQuestion",
+				Points:           1,
+				TimeLimit:        30,
+				TrueAlternative:  "testTrueAlternative",
+				FalseAlternative: "testFalseAlternative",
+			},
+		},
+	}
+	s.gameStorer.games[mockedGame.Id] = mockedGame
+
+	return mockedGame
+}
+
+func (s *SessionServiceTestSuite) TestStartSession() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	mockedGame := s.storeMockedGame(hostId)
+
+	// Act
+	sess, err := s.svc.StartSession(s.ctx, hostId, mockedGame.Id)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, sess.Pin, 6)
+	assert.Equal(t, session.LobbyState, sess.State)
+	assert.Equal(t, session.NoQuestion, sess.CurrentQuestion)
+}
+
+func (s *SessionServiceTestSuite) TestStartSessionOfSomeoneElsesGame() {
+	// Arrange
+	t := s.T()
+	mockedGame := s.storeMockedGame(uuid.NewString())
+
+	// Act
+	_, err := s.svc.StartSession(s.ctx, uuid.NewString(), mockedGame.Id)
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrNotGameOwner)
+}
+
+func (s *SessionServiceTestSuite) TestStartSessionOfUnknownGame() {
+	// Act
+	_, err := s.svc.StartSession(s.ctx, uuid.NewString(), uuid.New())
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ports.ErrGameNotFound)
+}
+
+func (s *SessionServiceTestSuite) TestJoinSession() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	sess, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	assert.NoError(t, err)
+
+	// Act
+	player, err := s.svc.JoinSession(
+		s.ctx,
+		sess.Pin,
+		&services.JoinSessionRequest{Nickname: "tubias"},
+	)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "tubias", player.Nickname)
+
+	lobby, err := s.svc.GetSession(s.ctx, sess.Pin)
+	assert.NoError(t, err)
+	assert.Len(t, lobby.Players, 1)
+}
+
+func (s *SessionServiceTestSuite) TestJoinSessionWithBadInput() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	sess, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	assert.NoError(t, err)
+	_, err = s.svc.JoinSession(s.ctx, sess.Pin, &services.JoinSessionRequest{Nickname: "tubias"})
+	assert.NoError(t, err)
+
+	table := []struct {
+		desc     string
+		pin      string
+		nickname string
+		err      error
+	}{
+		{
+			desc:     "unknown pin",
+			pin:      "not a pin",
+			nickname: "gepeto",
+			err:      ports.ErrSessionNotFound,
+		},
+		{
+			desc:     "nickname already taken",
+			pin:      sess.Pin,
+			nickname: "TUBIAS",
+			err:      ports.ErrNicknameTaken,
+		},
+		{
+			desc:     "empty nickname",
+			pin:      sess.Pin,
+			nickname: "",
+			err:      &services.ValidationError{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			_, err := s.svc.JoinSession(
+				s.ctx,
+				tt.pin,
+				&services.JoinSessionRequest{Nickname: tt.nickname},
+			)
+
+			// Assert
+			assert.ErrorContains(t, err, tt.err.Error())
+		})
+	}
+}
+
+func (s *SessionServiceTestSuite) TestNextQuestion() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	sess, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	assert.NoError(t, err)
+
+	// Act
+	started, err := s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	assert.NoError(t, err)
+	finished, err := s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	assert.NoError(t, err)
+	_, errAfterFinish := s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	_, errFind := s.svc.GetSession(s.ctx, sess.Pin)
+
+	// Assert
+	assert.Equal(t, session.QuestionState, started.State)
+	assert.Equal(t, 0, started.CurrentQuestion)
+	assert.Equal(t, session.FinishedState, finished.State)
+	assert.ErrorIs(t, errAfterFinish, ports.ErrSessionNotFound)
+	assert.ErrorIs(t, errFind, ports.ErrSessionNotFound)
+	assert.Len(t, s.resultStorer.results, 1)
+	assert.Contains(t, s.publisher.kinds(), session.GameOverEvent)
+}
+
+func (s *SessionServiceTestSuite) TestOtherSessionsGoOnWhileStoringResults() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	finishing, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	assert.NoError(t, err)
+	_, err = s.svc.NextQuestion(s.ctx, hostId, finishing.Pin)
+	assert.NoError(t, err)
+	other, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	assert.NoError(t, err)
+	s.resultStorer.storing = make(chan struct{})
+	s.resultStorer.release = make(chan struct{})
+
+	finished := make(chan error)
+	go func() {
+		_, err := s.svc.NextQuestion(s.ctx, hostId, finishing.Pin)
+		finished <- err
+	}()
+	<-s.resultStorer.storing
+
+	// Act
+	_, joinErr := s.svc.JoinSession(s.ctx, other.Pin, &services.JoinSessionRequest{Nickname: "tubias"})
+	started, nextErr := s.svc.NextQuestion(s.ctx, hostId, other.Pin)
+	close(s.resultStorer.release)
+
+	// Assert
+	assert.NoError(t, joinErr)
+	assert.NoError(t, nextErr)
+	assert.Equal(t, session.QuestionState, started.State)
+	assert.NoError(t, <-finished)
+}
+
+func (s *SessionServiceTestSuite) TestNextQuestionFailingToStoreResults() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	sess, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	assert.NoError(t, err)
+	_, err = s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	assert.NoError(t, err)
+	s.resultStorer.err = errors.New("database is down")
+
+	// Act
+	_, errFailed := s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	running, errFind := s.svc.GetSession(s.ctx, sess.Pin)
+	s.resultStorer.err = nil
+	finished, errRetry := s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+
+	// Assert
+	assert.Error(t, errFailed)
+	assert.NoError(t, errFind)
+	assert.Equal(t, session.QuestionState, running.State)
+	assert.NoError(t, errRetry)
+	assert.Equal(t, session.FinishedState, finished.State)
+	assert.Len(t, s.resultStorer.results, 1)
+
+	gameOvers := 0
+	for _, kind := range s.publisher.kinds() {
+		if kind == session.GameOverEvent {
+			gameOvers++
+		}
+	}
+	assert.Equal(t, 1, gameOvers)
+}
+
+func (s *SessionServiceTestSuite) TestJoinSessionAfterItStarted() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	sess, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	assert.NoError(t, err)
+	_, err = s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	assert.NoError(t, err)
+
+	// Act
+	_, err = s.svc.JoinSession(s.ctx, sess.Pin, &services.JoinSessionRequest{Nickname: "late"})
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrSessionAlreadyStarted)
+}
+
+func (s *SessionServiceTestSuite) TestNextQuestionByAnotherUser() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	sess, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	assert.NoError(t, err)
+
+	// Act
+	_, err = s.svc.NextQuestion(s.ctx, uuid.NewString(), sess.Pin)
+
+	// Assert
+	assert.ErrorIs(t, err, ports.ErrNotSessionHost)
+}
+
+func (s *SessionServiceTestSuite) TestCloseSession() {
+	// Arrange
+	t := s.T()
+	hostId := uuid.NewString()
+	sess, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	assert.NoError(t, err)
+
+	// Act
+	err = s.svc.CloseSession(s.ctx, hostId, sess.Pin)
+
+	// Assert
+	assert.NoError(t, err)
+	_, err = s.svc.GetSession(s.ctx, sess.Pin)
+	assert.ErrorIs(t, err, ports.ErrSessionNotFound)
+}
+
+func (s *SessionServiceTestSuite) startWithPlayers(nicknames ...string) (
+	string,
+	*session.Session,
+	[]*session.Player,
+) {
+	hostId := uuid.NewString()
+	sess, err := s.svc.StartSession(s.ctx, hostId, s.storeMockedGame(hostId).Id)
+	if err != nil {
+		log.Fatalf("error starting session: %s", err)
+	}
+
+	players := make([]*session.Player, len(nicknames))
+	for i, nickname := range nicknames {
+		players[i], err = s.svc.JoinSession(
+			s.ctx,
+			sess.Pin,
+			&services.JoinSessionRequest{Nickname: nickname},
+		)
+		if err != nil {
+			log.Fatalf("error joining session: %s", err)
+		}
+	}
+
+	return hostId, sess, players
+}
+
+func (s *SessionServiceTestSuite) TestAuthenticatePlayer() {
+	// Arrange
+	t := s.T()
+	_, sess, players := s.startWithPlayers("tubias")
+
+	// Act
+	player, err := s.svc.AuthenticatePlayer(s.ctx, sess.Pin, players[0].Token)
+	_, errBadToken := s.svc.AuthenticatePlayer(s.ctx, sess.Pin, "invalid")
+	_, errEmptyToken := s.svc.AuthenticatePlayer(s.ctx, sess.Pin, "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, players[0].Id, player.Id)
+	assert.ErrorIs(t, errBadToken, ports.ErrPlayerNotFound)
+	assert.ErrorIs(t, errEmptyToken, ports.ErrPlayerNotFound)
+}
+
+func (s *SessionServiceTestSuite) TestFinishedSessionResults() {
+	// Arrange
+	t := s.T()
+	hostId, sess, players := s.startWithPlayers("tubias", "gepeto")
+	_, err := s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	assert.NoError(t, err)
+	_, err = s.svc.SubmitAnswer(s.ctx, sess.Pin, players[1].Id, 0, "testTrueAlternative")
+	assert.NoError(t, err)
+	_, err = s.svc.SubmitAnswer(s.ctx, sess.Pin, players[0].Id, 0, "testFalseAlternative")
+	assert.NoError(t, err)
+
+	// Act
+	_, err = s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, s.resultStorer.results, 1)
+
+	res := s.resultStorer.results[0]
+	assert.Equal(t, sess.Pin, res.Pin)
+	assert.Equal(t, sess.Game.Id, res.GameId)
+	assert.Equal(t, sess.Game.Title, res.GameTitle)
+	assert.Equal(t, hostId, res.HostId)
+	assert.Len(t, res.Participants, 2)
+	assert.Equal(t, players[1].Id, res.Participants[0].Id)
+	assert.Equal(t, 1, res.Participants[0].Rank)
+	assert.Equal(t, 2, res.Participants[1].Rank)
+
+	answers := res.AnswersOf(players[0].Id)
+	assert.Len(t, answers, 1)
+	assert.False(t, answers[0].Correct)
+	assert.Equal(t, "testFalseAlternative", answers[0].Value)
+	assert.Equal(t, "testQuestion", answers[0].QuestionTitle)
+	assert.Zero(t, answers[0].Points)
+}
+
+func (s *SessionServiceTestSuite) TestSubmitAnswer() {
+	// Arrange
+	t := s.T()
+	hostId, sess, players := s.startWithPlayers("tubias", "gepeto")
+	_, err := s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	assert.NoError(t, err)
+
+	// Act
+	answer, err := s.svc.SubmitAnswer(s.ctx, sess.Pin, players[0].Id, 0, "testTrueAlternative")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, answer.Correct)
+	assert.Positive(t, answer.Points)
+
+	running, err := s.svc.GetSession(s.ctx, sess.Pin)
+	assert.NoError(t, err)
+	assert.Equal(t, session.QuestionState, running.State)
+	assert.Equal(t, answer.Points, running.FindPlayer(players[0].Id).Score)
+}
+
+func (s *SessionServiceTestSuite) TestSubmitAnswerWithBadInput() {
+	// Arrange
+	t := s.T()
+	hostId, sess, players := s.startWithPlayers("tubias", "gepeto")
+	_, err := s.svc.SubmitAnswer(s.ctx, sess.Pin, players[0].Id, 0, "testTrueAlternative")
+	assert.ErrorIs(t, err, ports.ErrQuestionNotRunning)
+	_, err = s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	assert.NoError(t, err)
+	_, err = s.svc.SubmitAnswer(s.ctx, sess.Pin, players[0].Id, 0, "testTrueAlternative")
+	assert.NoError(t, err)
+
+	table := []struct {
+		desc     string
+		playerId uuid.UUID
+		question int
+		err      error
+	}{
+		{
+			desc:     "unknown player",
+			playerId: uuid.New(),
+			question: 0,
+			err:      ports.ErrPlayerNotFound,
+		},
+		{
+			desc:     "question that is not running",
+			playerId: players[1].Id,
+			question: 1,
+			err:      ports.ErrQuestionNotRunning,
+		},
+		{
+			desc:     "question already answered",
+			playerId: players[0].Id,
+			question: 0,
+			err:      ports.ErrAlreadyAnswered,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.desc, func(t *testing.T) {
+			// Act
+			_, err := s.svc.SubmitAnswer(s.ctx, sess.Pin, tt.playerId, tt.question, "any")
+
+			// Assert
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func (s *SessionServiceTestSuite) TestQuestionClosesWhenEveryoneAnswered() {
+	// Arrange
+	t := s.T()
+	hostId, sess, players := s.startWithPlayers("tubias", "gepeto")
+	_, err := s.svc.NextQuestion(s.ctx, hostId, sess.Pin)
+	assert.NoError(t, err)
+
+	// Act
+	_, err = s.svc.SubmitAnswer(s.ctx, sess.Pin, players[0].Id, 0, "testTrueAlternative")
+	assert.NoError(t, err)
+	_, err = s.svc.SubmitAnswer(s.ctx, sess.Pin, players[1].Id, 0, "testFalseAlternative")
+	assert.NoError(t, err)
+
+	// Assert
+	closed, err := s.svc.GetSession(s.ctx, sess.Pin)
+	assert.NoError(t, err)
+	assert.Equal(t, session.ResultsState, closed.State)
+	assert.Equal(t, players[0].Id, closed.Leaderboard()[0].Id)
+	assert.Zero(t, closed.FindPlayer(players[1].Id).Score)
+	assert.Equal(t, []session.EventKind{
+		session.PlayerJoinedEvent,
+		session.PlayerJoinedEvent,
+		session.QuestionStartedEvent,
+		session.AnswerAcceptedEvent,
+		session.AnswerAcceptedEvent,
+		session.TimeUpEvent,
+		session.LeaderboardEvent,
+	}, s.publisher.kinds())
+}
+
+func (s *SessionServiceTestSuite) TestTimeUpOfQuestionThatIsNotRunning() {
+	// Arrange
+	t := s.T()
+	_, sess, _ := s.startWithPlayers("tubias")
+
+	// Act
+	err := s.svc.TimeUp(s.ctx, sess.Pin, 0)
+
+	// Assert
+	assert.NoError(t, err)
+	lobby, err := s.svc.GetSession(s.ctx, sess.Pin)
+	assert.NoError(t, err)
+	assert.Equal(t, session.LobbyState, lobby.State)
+	assert.NotContains(t, s.publisher.kinds(), session.TimeUpEvent)
+}
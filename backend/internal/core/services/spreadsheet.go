@@ -0,0 +1,447 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+const (
+	// MaxSpreadsheetRows is how many questions a spreadsheet may have
+	MaxSpreadsheetRows = 200
+
+	maxSpreadsheetAlternatives = 6
+)
+
+const (
+	questionColumn  = "question"
+	kindColumn      = "kind"
+	timeLimitColumn = "time_limit"
+	pointsColumn    = "points"
+	correctColumn   = "correct"
+)
+
+// SpreadsheetColumns are the columns of the quiz template. The header row
+// names them, in any order, only the question column is required.
+var SpreadsheetColumns = []string{
+	questionColumn,
+	kindColumn,
+	timeLimitColumn,
+	pointsColumn,
+	alternativeColumn(0),
+	alternativeColumn(1),
+	alternativeColumn(2),
+	alternativeColumn(3),
+	alternativeColumn(4),
+	alternativeColumn(5),
+	correctColumn,
+}
+
+// ImportSpreadsheetRequest holds the cells of a spreadsheet with one question
+// per row. Quiz rows mark their correct alternatives by number or letter, e.g.
+// "1, 3" or "A C". True or false rows have two alternatives, defaulting to
+// True and False, and mark the one that is right the same way or as true or
+// false.
+type ImportSpreadsheetRequest struct {
+	Title       string `validate:"required,gte=1,lte=120"`
+	Description string `validate:"omitempty,max=200"`
+	// Rows are the cells of the spreadsheet, the first one being the header
+	Rows [][]string
+	// DryRun only reports the problems of the spreadsheet, nothing is stored
+	DryRun bool
+}
+
+// SpreadsheetError is a problem with a cell of a spreadsheet.
+type SpreadsheetError struct {
+	// Row is the number of the row in the spreadsheet, the header being 1
+	Row int
+	// Column is the name of the column in the template
+	Column string
+	// Cell is the reference of the cell, e.g. C4, empty when the column is
+	// missing
+	Cell    string
+	Message string
+}
+
+type SpreadsheetImport struct {
+	// Game is the created game, nil on dry runs and when there are errors
+	Game      *game.Game
+	Questions int
+	Errors    []SpreadsheetError
+}
+
+// ImportSpreadsheet creates a new game owned by userId from a spreadsheet.
+// Every row is checked before anything is stored, the errors found are
+// returned along the cell they were found in instead of failing.
+func (s *GameService) ImportSpreadsheet(
+	ctx context.Context,
+	userId string,
+	req *ImportSpreadsheetRequest,
+) (*SpreadsheetImport, error) {
+	err := s.validationService.Validate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := newSpreadsheet(req.Rows)
+	if _, ok := sheet.columns[questionColumn]; !ok {
+		return &SpreadsheetImport{Errors: sheet.errors}, nil
+	}
+
+	questions := make([]game.Question, 0)
+
+	for i, cells := range req.Rows {
+		if i == 0 || blankRow(cells) {
+			continue
+		}
+		if len(questions) == MaxSpreadsheetRows {
+			sheet.fail(i+1, "", newErrorMessage("Questions", len(questions)+1, fmt.Sprintf("max=%d", MaxSpreadsheetRows)))
+			break
+		}
+
+		row := sheet.row(i+1, cells)
+		question := row.question()
+		if question == nil {
+			continue
+		}
+
+		err := s.validationService.Validate(question)
+		if err != nil {
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				return nil, err
+			}
+			for _, message := range verr.GetMessages() {
+				column := row.columnOf(message.Field)
+				// the cell could already not be read
+				if row.failed[column] {
+					continue
+				}
+				row.fail(column, message)
+			}
+		}
+
+		questions = append(questions, question)
+	}
+
+	if len(questions) == 0 && len(sheet.errors) == 0 {
+		sheet.fail(2, questionColumn, newErrorMessage("Questions", 0, "min=1"))
+	}
+
+	result := &SpreadsheetImport{
+		Questions: len(questions),
+		Errors:    sheet.errors,
+	}
+	if req.DryRun || len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	imported := &game.Game{
+		Title:       req.Title,
+		Description: req.Description,
+		Questions:   questions,
+	}
+	err = s.CreateNewGame(ctx, userId, imported)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Game = imported
+	return result, nil
+}
+
+type spreadsheet struct {
+	// columns are the indexes of the columns of the template named in the
+	// header
+	columns map[string]int
+	errors  []SpreadsheetError
+}
+
+func newSpreadsheet(rows [][]string) *spreadsheet {
+	sheet := &spreadsheet{
+		columns: make(map[string]int),
+		errors:  make([]SpreadsheetError, 0),
+	}
+
+	known := make(map[string]bool, len(SpreadsheetColumns))
+	for _, column := range SpreadsheetColumns {
+		known[column] = true
+	}
+
+	var header []string
+	if len(rows) > 0 {
+		header = rows[0]
+	}
+
+	for i, name := range header {
+		column := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+		switch {
+		case column == "":
+			continue
+		case !known[column]:
+			sheet.failCell(1, column, cellReference(1, i),
+				newErrorMessage("Header", name, "oneof="+strings.Join(SpreadsheetColumns, " ")))
+		default:
+			sheet.columns[column] = i
+		}
+	}
+
+	if _, ok := sheet.columns[questionColumn]; !ok {
+		sheet.fail(1, questionColumn, newErrorMessage("Header", questionColumn, "required"))
+	}
+
+	return sheet
+}
+
+func (s *spreadsheet) fail(row int, column string, message ErrorMessage) {
+	cell := ""
+	if i, ok := s.columns[column]; ok {
+		cell = cellReference(row, i)
+	}
+
+	s.failCell(row, column, cell, message)
+}
+
+func (s *spreadsheet) failCell(row int, column string, cell string, message ErrorMessage) {
+	s.errors = append(s.errors, SpreadsheetError{
+		Row:     row,
+		Column:  column,
+		Cell:    cell,
+		Message: message.Message,
+	})
+}
+
+func (s *spreadsheet) row(number int, cells []string) *spreadsheetRow {
+	return &spreadsheetRow{
+		sheet:        s,
+		number:       number,
+		cells:        cells,
+		fieldColumns: make(map[string]string),
+		failed:       make(map[string]bool),
+	}
+}
+
+type spreadsheetRow struct {
+	sheet  *spreadsheet
+	number int
+	cells  []string
+	// fieldColumns are the columns the fields of the question were read from
+	fieldColumns map[string]string
+	// failed are the columns with errors
+	failed map[string]bool
+}
+
+func (r *spreadsheetRow) fail(column string, message ErrorMessage) {
+	r.failed[column] = true
+	r.sheet.fail(r.number, column, message)
+}
+
+func (r *spreadsheetRow) cell(column string) string {
+	i, ok := r.sheet.columns[column]
+	if !ok || i >= len(r.cells) {
+		return ""
+	}
+
+	return strings.TrimSpace(r.cells[i])
+}
+
+// question reads the question of the row, nil when it can't be read.
+func (r *spreadsheetRow) question() game.Question {
+	header := game.Header{
+		Title:     r.cell(questionColumn),
+		Points:    game.Points(r.number64(pointsColumn, "Points")),
+		TimeLimit: game.TimeLimit(r.number64(timeLimitColumn, "TimeLimit")),
+	}
+	r.fieldColumns["Title"] = questionColumn
+	r.fieldColumns["Points"] = pointsColumn
+	r.fieldColumns["TimeLimit"] = timeLimitColumn
+
+	switch kind := strings.ToLower(r.cell(kindColumn)); kind {
+	case "", string(game.QuizKind):
+		return r.quizQuestion(header)
+	case string(game.TrueFalseKind):
+		return r.trueFalseQuestion(header)
+	default:
+		r.fail(kindColumn, newErrorMessage("Kind", kind, "oneof=quiz true_false"))
+		return nil
+	}
+}
+
+func (r *spreadsheetRow) quizQuestion(header game.Header) game.Question {
+	question := &game.QuizQuestion{
+		Title:        header.Title,
+		Points:       header.Points,
+		TimeLimit:    header.TimeLimit,
+		Alternatives: []game.Alternative{},
+	}
+	correct, _ := r.correctAlternatives(nil)
+
+	for i := 0; i < maxSpreadsheetAlternatives; i++ {
+		data := r.cell(alternativeColumn(i))
+		if data == "" {
+			if correct[i] {
+				r.fail(correctColumn, newErrorMessage("Correct", i+1, "alternative"))
+			}
+			continue
+		}
+
+		r.fieldColumns[fmt.Sprintf("Alternatives[%d]", len(question.Alternatives))] = alternativeColumn(i)
+		question.Alternatives = append(question.Alternatives, game.Alternative{
+			Data:      data,
+			IsCorrect: correct[i],
+		})
+	}
+
+	if len(question.Alternatives) < 3 {
+		r.fieldColumns["Alternatives"] = alternativeColumn(len(question.Alternatives))
+	} else {
+		r.fieldColumns["Alternatives"] = correctColumn
+	}
+
+	return question
+}
+
+func (r *spreadsheetRow) trueFalseQuestion(header game.Header) game.Question {
+	question := &game.TrueFalseQuestion{
+		Title:     header.Title,
+		Points:    header.Points,
+		TimeLimit: header.TimeLimit,
+	}
+
+	alternatives := [2]string{r.cell(alternativeColumn(0)), r.cell(alternativeColumn(1))}
+	if alternatives[0] == "" && alternatives[1] == "" {
+		alternatives = [2]string{"True", "False"}
+	}
+
+	for i := 2; i < maxSpreadsheetAlternatives; i++ {
+		if r.cell(alternativeColumn(i)) != "" {
+			r.fail(alternativeColumn(i), newErrorMessage("Alternatives", r.cell(alternativeColumn(i)), "max=2"))
+		}
+	}
+
+	correct, ok := r.correctAlternatives(map[string]int{"true": 0, "false": 1})
+	right, wrong := 0, 1
+	switch {
+	case !ok:
+	case len(correct) != 1:
+		r.fail(correctColumn, newErrorMessage("Correct", r.cell(correctColumn), "oneof=1 2"))
+	case correct[1]:
+		right, wrong = 1, 0
+	case !correct[0]:
+		r.fail(correctColumn, newErrorMessage("Correct", r.cell(correctColumn), "oneof=1 2"))
+	}
+
+	question.TrueAlternative = alternatives[right]
+	question.FalseAlternative = alternatives[wrong]
+	r.fieldColumns["TrueAlternative"] = alternativeColumn(right)
+	r.fieldColumns["FalseAlternative"] = alternativeColumn(wrong)
+
+	return question
+}
+
+// correctAlternatives reads the indexes of the alternatives marked as
+// correct, by number, letter or one of the given words. It reports whether
+// every mark could be read.
+func (r *spreadsheetRow) correctAlternatives(words map[string]int) (map[int]bool, bool) {
+	correct := make(map[int]bool)
+	valid := true
+
+	marker := r.cell(correctColumn)
+	tokens := strings.FieldsFunc(marker, func(c rune) bool {
+		return c == ',' || c == ';' || c == ' '
+	})
+
+	for _, token := range tokens {
+		token = strings.ToLower(token)
+
+		i, ok := words[token]
+		if !ok {
+			i, ok = alternativeIndex(token)
+		}
+		if !ok {
+			r.fail(correctColumn, newErrorMessage("Correct", token, "alternative"))
+			valid = false
+			continue
+		}
+
+		correct[i] = true
+	}
+
+	return correct, valid
+}
+
+// number64 reads a whole number, spreadsheets may write them with decimals.
+// Empty cells are zero.
+func (r *spreadsheetRow) number64(column string, field string) int64 {
+	value := r.cell(column)
+	if value == "" {
+		return 0
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n != math.Trunc(n) || math.Abs(n) > math.MaxInt32 {
+		r.fail(column, newErrorMessage(field, value, "number"))
+		return 0
+	}
+
+	return int64(n)
+}
+
+// columnOf returns the column the field of the question, or the field it
+// belongs to, was read from.
+func (r *spreadsheetRow) columnOf(field string) string {
+	for {
+		if column, ok := r.fieldColumns[field]; ok {
+			return column
+		}
+
+		i := strings.LastIndexAny(field, ".[")
+		if i < 0 {
+			return ""
+		}
+		field = field[:i]
+	}
+}
+
+func alternativeColumn(i int) string {
+	return fmt.Sprintf("alternative_%d", i+1)
+}
+
+// alternativeIndex reads the number, from 1, or the letter, from A, of an
+// alternative.
+func alternativeIndex(token string) (int, bool) {
+	if n, err := strconv.Atoi(token); err == nil {
+		return n - 1, n >= 1 && n <= maxSpreadsheetAlternatives
+	}
+
+	if len(token) == 1 && token[0] >= 'a' && token[0] < 'a'+maxSpreadsheetAlternatives {
+		return int(token[0] - 'a'), true
+	}
+
+	return 0, false
+}
+
+func blankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cellReference writes the cell at the row, from 1, and column, from 0, the
+// way spreadsheets do, e.g. C4.
+func cellReference(row int, column int) string {
+	name := ""
+	for column++; column > 0; column = (column - 1) / 26 {
+		name = string(rune('A'+(column-1)%26)) + name
+	}
+
+	return name + strconv.Itoa(row)
+}
@@ -0,0 +1,109 @@
+package services_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/taldoflemis/brain.test/internal/core/services"
+	testshelpers "github.com/taldoflemis/brain.test/test/helpers"
+)
+
+func TestImportSpreadsheetDryRun(t *testing.T) {
+	header := []string{
+		"Question", "Kind", "Time Limit", "Points",
+		"Alternative 1", "Alternative 2", "Alternative 3", "Alternative 4",
+		"Alternative 5", "Alternative 6", "Correct",
+	}
+
+	table := []struct {
+		testDescription string
+		rows            [][]string
+		questions       int
+		errors          []services.SpreadsheetError
+	}{
+		{
+			testDescription: "valid quiz and true or false questions",
+			rows: [][]string{
+				header,
+				{"2 + 2?", "quiz", "30", "1", "3", "4", "5", "", "", "", "B"},
+				{},
+				{"Which are even?", "", "20.0", "2", "1", "2", "3", "4", "", "", "2, 4"},
+				{"The sky is blue", "true_false", "10", "1", "", "", "", "", "", "", "true"},
+				{"Pick the planet", "TRUE_FALSE", "10", "1", "Moon", "Mars", "", "", "", "", "2"},
+			},
+			questions: 4,
+			errors:    []services.SpreadsheetError{},
+		},
+		{
+			testDescription: "errors point to the cell they were found in",
+			rows: [][]string{
+				header,
+				{"", "quiz", "2", "1", "a", "b", "c", "", "", "", "1"},
+				{"Too few", "quiz", "30", "1", "a", "b", "", "", "", "", "1"},
+				{"No correct", "quiz", "30", "1", "a", "b", "c", "", "", "", ""},
+				{"Skipped alternative", "quiz", "30", "1", "a", "", "c", "d", "", "", "3, 5"},
+				{"Wrong kind", "essay", "30", "1", "", "", "", "", "", "", ""},
+				{"Wrong number", "quiz", "thirty", "1", "a", "b", "c", "", "", "", "C"},
+			},
+			questions: 5,
+			errors: []services.SpreadsheetError{
+				{Row: 2, Column: "question", Cell: "A2", Message: "[Title]: '' | Needs to implement 'required'"},
+				{Row: 2, Column: "time_limit", Cell: "C2", Message: "[TimeLimit]: '2' | Needs to implement 'gte'"},
+				{Row: 3, Column: "alternative_3", Cell: "G3", Message: "[Alternatives]: '[{a true <nil>  } {b false <nil>  }]' | Needs to implement 'min'"},
+				{Row: 4, Column: "correct", Cell: "K4", Message: "[Alternatives]: '[{a false <nil>  } {b false <nil>  } {c false <nil>  }]' | Needs to implement 'correctalternatives'"},
+				{Row: 5, Column: "correct", Cell: "K5", Message: "[Correct]: '5' | Needs to implement 'alternative'"},
+				{Row: 6, Column: "kind", Cell: "B6", Message: "[Kind]: 'essay' | Needs to implement 'oneof=quiz true_false'"},
+				{Row: 7, Column: "time_limit", Cell: "C7", Message: "[TimeLimit]: 'thirty' | Needs to implement 'number'"},
+			},
+		},
+		{
+			testDescription: "unknown and missing columns",
+			rows: [][]string{
+				{"Title", "Answer"},
+				{"2 + 2?", "4"},
+			},
+			questions: 0,
+			errors: []services.SpreadsheetError{
+				{Row: 1, Column: "title", Cell: "A1", Message: "[Header]: 'Title' | Needs to implement 'oneof=question kind time_limit points alternative_1 alternative_2 alternative_3 alternative_4 alternative_5 alternative_6 correct'"},
+				{Row: 1, Column: "answer", Cell: "B1", Message: "[Header]: 'Answer' | Needs to implement 'oneof=question kind time_limit points alternative_1 alternative_2 alternative_3 alternative_4 alternative_5 alternative_6 correct'"},
+				{Row: 1, Column: "question", Message: "[Header]: 'question' | Needs to implement 'required'"},
+			},
+		},
+		{
+			testDescription: "no questions",
+			rows:            [][]string{header},
+			questions:       0,
+			errors: []services.SpreadsheetError{
+				{Row: 2, Column: "question", Cell: "A2", Message: "[Questions]: '0' | Needs to implement 'min=1'"},
+			},
+		},
+	}
+
+	svc := services.NewGameService(
+		testshelpers.NewDummyLogger(log.Writer()),
+		services.NewValidationService(),
+		nil,
+		nil,
+		nil,
+	)
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Act
+			result, err := svc.ImportSpreadsheet(context.Background(), "owner", &services.ImportSpreadsheetRequest{
+				Title:  "title",
+				Rows:   tt.rows,
+				DryRun: true,
+			})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Nil(t, result.Game)
+			assert.Equal(t, tt.questions, result.Questions)
+			assert.Equal(t, tt.errors, result.Errors)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/ports"
+)
+
+// ExportedGame is a game along the files its questions reference, when they
+// were asked for.
+type ExportedGame struct {
+	Game  *game.Game
+	Media []ExportedMedia
+}
+
+type ExportedMedia struct {
+	File    *ports.MediaFile
+	Content []byte
+}
+
+// ImportGameRequest holds a game read from an export. Media embedded in the
+// export are uploaded again, references to any other media must already be
+// uploaded to this instance.
+type ImportGameRequest struct {
+	Game  *game.Game
+	Media []ImportedMedia
+}
+
+type ImportedMedia struct {
+	// Id is the id the questions of the export reference the media by
+	Id      uuid.UUID
+	Content []byte
+}
+
+// ExportGame returns a game owned by userId to be backed up or moved to
+// another instance, along the content of its media when includeMedia is set.
+// Media adding up to more than MaxBundledMediaSize can't be embedded.
+func (s *GameService) ExportGame(
+	ctx context.Context,
+	userId string,
+	gameId uuid.UUID,
+	includeMedia bool,
+) (*ExportedGame, error) {
+	found, err := s.findOwnedGame(ctx, userId, gameId)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := &ExportedGame{
+		Game:  found,
+		Media: make([]ExportedMedia, 0),
+	}
+	if !includeMedia {
+		return exported, nil
+	}
+
+	files := make([]*ports.MediaFile, 0)
+	var size int64
+	for _, id := range found.MediaIds() {
+		file, err := s.mediaStorer.FindMedia(ctx, id)
+		if err != nil {
+			s.logger.Errorf("Failed to find media %v", err)
+			return nil, err
+		}
+
+		files = append(files, file)
+		size += file.Size
+	}
+	if size > MaxBundledMediaSize {
+		return nil, mediaValidationError("Media", size, fmt.Sprintf("lte=%d", MaxBundledMediaSize))
+	}
+
+	for _, file := range files {
+		content, err := s.readMedia(ctx, file.Id)
+		if err != nil {
+			s.logger.Errorf("Failed to read media %v", err)
+			return nil, err
+		}
+
+		exported.Media = append(exported.Media, ExportedMedia{
+			File:    file,
+			Content: content,
+		})
+	}
+
+	return exported, nil
+}
+
+// ImportGame creates a new game owned by userId from an export. Published
+// games are validated and published again, drafts stay drafts. Questions get
+// fresh ids so that a game can be imported into the instance it was exported
+// from.
+func (s *GameService) ImportGame(
+	ctx context.Context,
+	userId string,
+	req *ImportGameRequest,
+) (*game.Game, error) {
+	imported := req.Game
+	for i, q := range imported.Questions {
+		imported.Questions[i] = q.Clone()
+	}
+
+	var size int64
+	for _, m := range req.Media {
+		size += int64(len(m.Content))
+	}
+	if size > MaxBundledMediaSize {
+		return nil, mediaValidationError("Media", size, fmt.Sprintf("lte=%d", MaxBundledMediaSize))
+	}
+
+	uploaded := make(map[uuid.UUID]uuid.UUID, len(req.Media))
+	for _, m := range req.Media {
+		file, err := storeUpload(ctx, s.mediaStorer, userId, int64(len(m.Content)), bytes.NewReader(m.Content))
+		if err != nil {
+			s.logger.Errorf("Failed to store imported media %v", err)
+			s.deleteImportedMedia(ctx, uploaded)
+			return nil, err
+		}
+		uploaded[m.Id] = file.Id
+	}
+
+	for _, q := range imported.Questions {
+		for _, m := range game.AttachedMedia(q) {
+			if id, ok := uploaded[m.Id]; ok {
+				m.Id = id
+			}
+		}
+	}
+
+	var err error
+	if imported.StatusOrDefault() == game.PublishedStatus {
+		err = s.CreateNewGame(ctx, userId, imported)
+	} else {
+		err = s.CreateDraftGame(ctx, userId, imported)
+	}
+	if err != nil {
+		s.deleteImportedMedia(ctx, uploaded)
+		return nil, err
+	}
+
+	return imported, nil
+}
+
+func (s *GameService) readMedia(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	content, err := s.mediaStorer.OpenMedia(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	return io.ReadAll(content)
+}
+
+// deleteImportedMedia deletes the media uploaded by an import that failed,
+// nothing references them.
+func (s *GameService) deleteImportedMedia(ctx context.Context, uploaded map[uuid.UUID]uuid.UUID) {
+	for _, id := range uploaded {
+		err := s.mediaStorer.DeleteMedia(ctx, id)
+		if err != nil {
+			s.logger.Errorf("Failed to delete media %v", err)
+		}
+	}
+}
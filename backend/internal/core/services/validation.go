@@ -3,6 +3,8 @@ package services
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 
@@ -15,6 +17,9 @@ type ValidationService struct {
 
 type ErrorMessage struct {
 	Message string
+	// Field is the path of the field that failed validation from the
+	// validated struct, e.g. Alternatives[2].Data, when known
+	Field string
 }
 
 type ValidationError struct {
@@ -35,9 +40,19 @@ func (v *ValidationError) AddNewMessage(e ErrorMessage) {
 
 func NewValidationService() *ValidationService {
 	validate := validator.New(validator.WithRequiredStructEnabled())
-	err := validate.RegisterValidation("atleastonecorrect", game.ValidateAtLeastOneCorrect)
-	if err != nil {
-		panic(err)
+	for tag, fn := range game.TextValidations {
+		err := validate.RegisterValidation(tag, fn)
+		if err != nil {
+			panic(err)
+		}
+	}
+	for _, spec := range game.Kinds() {
+		for tag, fn := range spec.Validations {
+			err := validate.RegisterValidation(tag, fn)
+			if err != nil {
+				panic(err)
+			}
+		}
 	}
 	return &ValidationService{
 		validate: validate,
@@ -45,21 +60,63 @@ func NewValidationService() *ValidationService {
 }
 
 func (v ValidationService) Validate(i interface{}) error {
+	return v.toValidationError(v.validate.Struct(i))
+}
+
+// ValidatePartial only checks the given fields of the struct, nested fields
+// being written as "Parent.Child".
+func (v ValidationService) ValidatePartial(i interface{}, fields ...string) error {
+	return v.toValidationError(v.validate.StructPartial(i, fields...))
+}
+
+// ValidateDraft checks the draft fields of a game that may still be
+// incomplete, and its questions against every rule but the incomplete ones,
+// see draftFields and incompleteRules.
+func (v ValidationService) ValidateDraft(g *game.Game) error {
+	err := v.validate.Struct(g)
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return v.toValidationError(err)
+	}
+
+	kept := make(validator.ValidationErrors, 0, len(ve))
+	for _, fe := range ve {
+		path := fieldPath(fe.StructNamespace())
+		if isDraftField(path) ||
+			strings.HasPrefix(path, "Questions[") && !slices.Contains(incompleteRules, fe.Tag()) {
+			kept = append(kept, fe)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+
+	return v.toValidationError(kept)
+}
+
+// isDraftField tells whether the field at path is one of the draftFields or
+// nested in one.
+func isDraftField(path string) bool {
+	for _, field := range draftFields {
+		if path == field ||
+			strings.HasPrefix(path, field+".") ||
+			strings.HasPrefix(path, field+"[") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v ValidationService) toValidationError(errs error) error {
 	validationErrors := make([]ErrorMessage, 0)
 
-	errs := v.validate.Struct(i)
 	if errs != nil {
 		var ve validator.ValidationErrors
 		if errors.As(errs, &ve) {
 			for _, err := range errs.(validator.ValidationErrors) {
-				var elem ErrorMessage
-
-				elem.Message = fmt.Sprintf(
-					"[%s]: '%v' | Needs to implement '%s'",
-					err.Field(),
-					err.Value(),
-					err.Tag(),
-				)
+				elem := newErrorMessage(err.Field(), err.Value(), err.Tag())
+				elem.Field = fieldPath(err.StructNamespace())
 				validationErrors = append(validationErrors, elem)
 			}
 		} else {
@@ -71,3 +128,21 @@ func (v ValidationService) Validate(i interface{}) error {
 
 	return nil
 }
+
+func newErrorMessage(field string, value any, rule string) ErrorMessage {
+	return ErrorMessage{
+		Message: fmt.Sprintf("[%s]: '%v' | Needs to implement '%s'", field, value, rule),
+		Field:   field,
+	}
+}
+
+// fieldPath drops the name of the validated struct from the namespace of a
+// field.
+func fieldPath(namespace string) string {
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return namespace
+	}
+
+	return path
+}
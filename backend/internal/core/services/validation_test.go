@@ -0,0 +1,334 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	game "github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+	"github.com/taldoflemis/brain.test/internal/core/services"
+)
+
+func TestRenderText(t *testing.T) {
+	table := []struct {
+		testDescription string
+		format          game.TextFormat
+		source          string
+		rendered        string
+		visible         string
+	}{
+		{
+			testDescription: "plain text is escaped",
+			format:          game.PlainText,
+			source:          "2 < 3 & **not bold**",
+			rendered:        "2 &lt; 3 &amp; **not bold**",
+			visible:         "2 < 3 & **not bold**",
+		},
+		{
+			testDescription: "markdown emphasis",
+			format:          game.MarkdownText,
+			source:          "**Which** is *not* a ~~fruit~~ `apple`?",
+			rendered:        "<strong>Which</strong> is <em>not</em> a <del>fruit</del> <code>apple</code>?",
+			visible:         "Which is not a fruit apple?",
+		},
+		{
+			testDescription: "markdown subscript and superscript",
+			format:          game.MarkdownText,
+			source:          "H~2~O and E=mc^2^ or CO<sub>2</sub>",
+			rendered:        "H<sub>2</sub>O and E=mc<sup>2</sup> or CO<sub>2</sub>",
+			visible:         "H2O and E=mc2 or CO2",
+		},
+		{
+			testDescription: "markdown delimiters without a match are text",
+			format:          game.MarkdownText,
+			source:          `5 * 3 and \*escaped\*`,
+			rendered:        "5 * 3 and *escaped*",
+			visible:         "5 * 3 and *escaped*",
+		},
+		{
+			testDescription: "markdown escapes disallowed html",
+			format:          game.MarkdownText,
+			source:          "<script>alert(1)</script>",
+			rendered:        "&lt;script&gt;alert(1)&lt;/script&gt;",
+			visible:         "<script>alert(1)</script>",
+		},
+		{
+			testDescription: "dollars are text without latex",
+			format:          game.MarkdownText,
+			source:          "$5",
+			rendered:        "$5",
+			visible:         "$5",
+		},
+		{
+			testDescription: "latex is kept for clients to typeset",
+			format:          game.MarkdownLatexText,
+			source:          `Solve $\frac{x}{2} < 1$ for **x**`,
+			rendered:        `Solve <span class="math">\frac{x}{2} &lt; 1</span> for <strong>x</strong>`,
+			visible:         `Solve \frac{x}{2} < 1 for x`,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Act
+			rendered := game.RenderText(tt.format, tt.source)
+			visible := game.VisibleText(tt.format, tt.source)
+
+			// Assert
+			assert.Equal(t, tt.rendered, rendered)
+			assert.Equal(t, tt.visible, visible)
+		})
+	}
+}
+
+func TestValidateFormattedText(t *testing.T) {
+	long := strings.Repeat("a", 100)
+
+	table := []struct {
+		testDescription string
+		format          game.TextFormat
+		title           string
+		valid           bool
+	}{
+		{
+			testDescription: "markup doesn't count towards the length",
+			format:          game.MarkdownText,
+			title:           "**" + long + "** " + strings.Repeat("*b* ", 4),
+			valid:           true,
+		},
+		{
+			testDescription: "visible text over the length",
+			format:          game.MarkdownText,
+			title:           "**" + long + long + "**",
+		},
+		{
+			testDescription: "plain text may contain anything",
+			format:          game.PlainText,
+			title:           "<b>is this bold?</b>",
+			valid:           true,
+		},
+		{
+			testDescription: "markdown with disallowed html",
+			format:          game.MarkdownText,
+			title:           `<img src="x" onerror="alert(1)">`,
+		},
+		{
+			testDescription: "markdown with unclosed allowed html",
+			format:          game.MarkdownText,
+			title:           "CO<sub>2",
+		},
+		{
+			testDescription: "latex with unbalanced braces",
+			format:          game.MarkdownLatexText,
+			title:           `$\frac{1}{2$`,
+		},
+		{
+			testDescription: "latex with disallowed command",
+			format:          game.MarkdownLatexText,
+			title:           `$\href{https://example.com}{x}$`,
+		},
+		{
+			testDescription: "latex without closing dollar",
+			format:          game.MarkdownLatexText,
+			title:           `costs $5`,
+		},
+		{
+			testDescription: "unknown format",
+			format:          "html",
+			title:           "title",
+		},
+	}
+
+	validationService := services.NewValidationService()
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Arrange
+			question := &game.WordCloudQuestion{
+				Title:     tt.title,
+				TimeLimit: 30,
+				Format:    tt.format,
+			}
+
+			// Act
+			err := validationService.Validate(question)
+
+			// Assert
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				validatorError := &services.ValidationError{}
+				assert.ErrorAs(t, err, &validatorError)
+			}
+		})
+	}
+}
+
+func TestValidateFormattedChoices(t *testing.T) {
+	long := strings.Repeat("a", 100)
+
+	table := []struct {
+		testDescription string
+		question        game.Question
+		valid           bool
+	}{
+		{
+			testDescription: "markup of alternatives doesn't count towards the length",
+			question: &game.TrueFalseQuestion{
+				Title:            "title",
+				Points:           1,
+				TimeLimit:        30,
+				Format:           game.MarkdownText,
+				TrueAlternative:  "**" + long + "** " + strings.Repeat("*b* ", 4),
+				FalseAlternative: "H~2~O",
+			},
+			valid: true,
+		},
+		{
+			testDescription: "alternative with disallowed html",
+			question: &game.TrueFalseQuestion{
+				Title:            "title",
+				Points:           1,
+				TimeLimit:        30,
+				Format:           game.MarkdownText,
+				TrueAlternative:  `<img src="x">`,
+				FalseAlternative: "b",
+			},
+		},
+		{
+			testDescription: "items in latex",
+			question: &game.OrderingQuestion{
+				Title:     "title",
+				Points:    1,
+				TimeLimit: 30,
+				Format:    game.MarkdownLatexText,
+				Items:     []string{`$\frac{1}{2}$`, `$1$`},
+			},
+			valid: true,
+		},
+		{
+			testDescription: "item with unbalanced latex",
+			question: &game.OrderingQuestion{
+				Title:     "title",
+				Points:    1,
+				TimeLimit: 30,
+				Format:    game.MarkdownLatexText,
+				Items:     []string{`$\frac{1}{2$`, `$1$`},
+			},
+		},
+		{
+			testDescription: "option with visible text over the length",
+			question: &game.PollQuestion{
+				Title:     "title",
+				TimeLimit: 30,
+				Format:    game.MarkdownText,
+				Options:   []string{"**" + long + long + "**", "b"},
+			},
+		},
+	}
+
+	validationService := services.NewValidationService()
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Act
+			err := validationService.Validate(tt.question)
+
+			// Assert
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				validatorError := &services.ValidationError{}
+				assert.ErrorAs(t, err, &validatorError)
+			}
+		})
+	}
+}
+
+func TestRenderChoices(t *testing.T) {
+	// Arrange
+	trueFalse := &game.TrueFalseQuestion{
+		Format:           game.MarkdownText,
+		TrueAlternative:  "**yes**",
+		FalseAlternative: "2 < 3",
+	}
+	ordering := &game.OrderingQuestion{Format: game.MarkdownText, Items: []string{"H~2~O", "*b*"}}
+	poll := &game.PollQuestion{Options: []string{"<b>", "**c**"}}
+	g := &game.Game{Questions: []game.Question{trueFalse, ordering, poll}}
+
+	// Act
+	g.Render()
+
+	// Assert
+	assert.Equal(t, "<strong>yes</strong>", trueFalse.RenderedTrueAlternative)
+	assert.Equal(t, "2 &lt; 3", trueFalse.RenderedFalseAlternative)
+	assert.Equal(t, []string{"H<sub>2</sub>O", "<em>b</em>"}, ordering.RenderedItems)
+	assert.Equal(t, []string{"&lt;b&gt;", "**c**"}, poll.RenderedOptions)
+}
+
+func TestValidateDraft(t *testing.T) {
+	table := []struct {
+		testDescription string
+		game            *game.Game
+		valid           bool
+	}{
+		{
+			testDescription: "game without questions",
+			game:            &game.Game{},
+			valid:           true,
+		},
+		{
+			testDescription: "incomplete question",
+			game: &game.Game{
+				Questions: []game.Question{&game.QuizQuestion{Title: "quiz"}},
+			},
+			valid: true,
+		},
+		{
+			testDescription: "question too long",
+			game: &game.Game{
+				Questions: []game.Question{
+					&game.QuizQuestion{Title: strings.Repeat("a", 121)},
+				},
+			},
+		},
+		{
+			testDescription: "question of unknown format",
+			game: &game.Game{
+				Questions: []game.Question{
+					&game.WordCloudQuestion{Title: "cloud", Format: "html"},
+				},
+			},
+		},
+		{
+			testDescription: "too many tags",
+			game: &game.Game{
+				Metadata: game.Metadata{Tags: strings.Split(strings.Repeat("a,", 21), ",")},
+			},
+		},
+	}
+
+	validationService := services.NewValidationService()
+
+	for _, tt := range table {
+		t.Run(tt.testDescription, func(t *testing.T) {
+			// Arrange
+			tt.game.Id = uuid.New()
+			tt.game.Title = "title"
+			tt.game.OwnerId = uuid.NewString()
+
+			// Act
+			err := validationService.ValidateDraft(tt.game)
+
+			// Assert
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				validatorError := &services.ValidationError{}
+				assert.ErrorAs(t, err, &validatorError)
+			}
+		})
+	}
+}
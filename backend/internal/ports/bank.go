@@ -0,0 +1,53 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+var (
+	ErrBankQuestionNotFound = errors.New("Question not found in the bank")
+	ErrNotBankQuestionOwner = errors.New("User is not the owner of the question")
+)
+
+// BankQuery selects questions of the bank of a user.
+type BankQuery struct {
+	OwnerId string
+	// Search matches questions whose title contains it, ignoring case
+	Search string
+	// Tags are all required, questions having them and possibly more
+	Tags []string
+	// Kinds are the kinds allowed, any kind when empty
+	Kinds []game.Kind
+	// Category and Difficulty are the ones of the question, any when empty
+	Category   string
+	Difficulty game.Difficulty
+	// Page starts at 1
+	Page     int
+	PageSize int
+}
+
+type BankStorer interface {
+	StoreBankQuestion(ctx context.Context, question *game.BankQuestion) error
+	// StoreBankQuestions stores either every one of the questions or none of
+	// them
+	StoreBankQuestions(ctx context.Context, questions []*game.BankQuestion) error
+	// UpdateBankQuestion replaces the question and tags of the bank question
+	// with the same id
+	UpdateBankQuestion(ctx context.Context, question *game.BankQuestion) error
+	DeleteBankQuestion(ctx context.Context, id uuid.UUID) error
+	FindBankQuestion(ctx context.Context, id uuid.UUID) (*game.BankQuestion, error)
+	// FindBankQuestionsByIds returns the questions with the given ids, in the
+	// order they were given in, failing when any of them is missing
+	FindBankQuestionsByIds(ctx context.Context, ids []uuid.UUID) ([]*game.BankQuestion, error)
+	// FindBankQuestions returns a page of the questions matching the query,
+	// newest first, along with the total amount matching it
+	FindBankQuestions(ctx context.Context, query BankQuery) ([]*game.BankQuestion, int, error)
+	// FindRandomBankQuestions picks up to count questions matching the query
+	// at random, ignoring its page
+	FindRandomBankQuestions(ctx context.Context, query BankQuery, count int) ([]*game.BankQuestion, error)
+}
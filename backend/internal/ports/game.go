@@ -3,6 +3,7 @@ package ports
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,15 +11,145 @@ import (
 )
 
 var (
-	ErrUnknownQuestionKind = errors.New("Unknown question type")
-	ErrGameNotFound        = errors.New("Game not found")
+	ErrGameNotFound     = errors.New("Game not found")
+	ErrNotGameOwner     = errors.New("User is not the owner of the game")
+	ErrGameNotPublished = errors.New("Game was never published")
 )
 
+// GameInfo is everything about a game besides its questions.
+type GameInfo struct {
+	Title       string
+	Description string
+	// Scoring is the policy of the game, nil meaning the default one
+	Scoring    *game.ScoringPolicy
+	Visibility game.Visibility
+	Metadata   game.Metadata
+}
+
+type GameSort string
+
+const (
+	// RecentGameSort lists the newest games first
+	RecentGameSort GameSort = "recent"
+	// PlayCountGameSort lists the most played games first
+	PlayCountGameSort GameSort = "plays"
+	// CreatedGameSort, UpdatedGameSort and TitleGameSort list the games of
+	// an owner by when they were created, last edited or by their title
+	CreatedGameSort GameSort = "created"
+	UpdatedGameSort GameSort = "updated"
+	TitleGameSort   GameSort = "title"
+)
+
+// GameCursor is the position of a game in a listing, Key being the value of
+// the game the listing is sorted by and Id breaking ties.
+type GameCursor struct {
+	Key string
+	Id  uuid.UUID
+}
+
+// MetadataFilter keeps the games having every one of the tags, and the
+// category and difficulty when they are set. Tags and category are expected
+// normalized, see game.Metadata.Normalize.
+type MetadataFilter struct {
+	Tags       []string
+	Category   string
+	Difficulty game.Difficulty
+}
+
+// PublicGamesQuery selects a page of the public catalogue.
+type PublicGamesQuery struct {
+	// Search matches games whose title or description contain it, ignoring
+	// case
+	Search string
+	Filter MetadataFilter
+	Sort   GameSort
+	// Page starts at 1
+	Page     int
+	PageSize int
+}
+
+// GameListing is a game of the public catalogue, its questions aren't loaded.
+type GameListing struct {
+	Game          *game.Game
+	QuestionCount int
+	// PlayCount is the amount of finished sessions of the game
+	PlayCount int
+}
+
+// FindGamesOptions tunes how much of each game is loaded by the finders that
+// return many games.
+type FindGamesOptions struct {
+	// SummaryOnly skips loading questions, leaving them nil
+	SummaryOnly bool
+	Filter      MetadataFilter
+	// Search matches games whose title contain it, ignoring case
+	Search string
+	// Sort is one of CreatedGameSort, the default, UpdatedGameSort or
+	// TitleGameSort
+	Sort       GameSort
+	Descending bool
+	// After keeps the games listed after the cursor, Before the ones listed
+	// before it. Either way, the games closest to the cursor are the ones
+	// returned.
+	After  *GameCursor
+	Before *GameCursor
+	// Limit caps the amount of games returned, 0 meaning no limit
+	Limit int
+}
+
 type GameStorer interface {
 	StoreGame(ctx context.Context, game *game.Game) error
-	UpdateGameInfo(ctx context.Context) error
-	UpdateGameQuestions(ctx context.Context) error
+	// CreateGame stores a new game along with its first revision, numbering
+	// it, all at once. Unless publishedAt is nil, the game is published as
+	// well, its status and publication time being set.
+	CreateGame(
+		ctx context.Context,
+		g *game.Game,
+		revision *game.Revision,
+		publishedAt *time.Time,
+	) error
+	// UpdateGameInfo replaces the info of the game with the given id. Like
+	// every other update, it turns the game back into a draft, of its next
+	// revision if it was published.
+	UpdateGameInfo(ctx context.Context, id uuid.UUID, info *GameInfo) error
+	// UpdateGameQuestions replaces every question of the game with the given
+	// id, keeping the order they are given in.
+	UpdateGameQuestions(ctx context.Context, id uuid.UUID, questions []game.Question) error
+	// UpdateGame replaces both the info and the questions of the game with
+	// the given id, all at once.
+	UpdateGame(ctx context.Context, id uuid.UUID, info *GameInfo, questions []game.Question) error
+	// PublishGame marks the game as published and stores the snapshot as the
+	// version sessions play, replacing the previous one.
+	PublishGame(ctx context.Context, snapshot *game.Game) error
+	// FindPublishedGame returns the snapshot of the game taken when it was
+	// last published.
+	FindPublishedGame(ctx context.Context, id uuid.UUID) (*game.Game, error)
 	DeleteGame(ctx context.Context, id uuid.UUID) error
+	// FindUnreferencedMedia returns the media, among the given ones, that no
+	// game references anymore, be it in its current version, its published
+	// one or its revisions, nor any question of a bank
+	FindUnreferencedMedia(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	// FindUnattachedMedia is FindUnreferencedMedia where revisions don't
+	// count, they only keep what games were like
+	FindUnattachedMedia(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	// FindMediaReferencedByUser returns the media, among the given ones, that
+	// the current version of a game of userId or a question of their bank
+	// references
+	FindMediaReferencedByUser(ctx context.Context, userId string, ids []uuid.UUID) ([]uuid.UUID, error)
 	FindGameById(ctx context.Context, id uuid.UUID) (*game.Game, error)
-	FindAllGamesByUserId(ctx context.Context, userId string) ([]*game.Game, error)
+	// FindAllGamesByUserId lists the games of userId in the order asked for,
+	// along with the total amount of their games matching the filter and
+	// search, no matter the cursors and limit.
+	FindAllGamesByUserId(
+		ctx context.Context,
+		userId string,
+		opts FindGamesOptions,
+	) ([]*game.Game, int, error)
+	// FindTagsByUserId suggests the tags userId already used, on their games,
+	// the questions of their games or their bank, that start with prefix.
+	// The most used ones come first.
+	FindTagsByUserId(ctx context.Context, userId string, prefix string, limit int) ([]string, error)
+	// FindPublicGames returns a page of public games along with the total
+	// amount of public games matching the query.
+	FindPublicGames(ctx context.Context, query PublicGamesQuery) ([]*GameListing, int, error)
 }
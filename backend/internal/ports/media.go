@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+var (
+	ErrMediaNotFound = errors.New("Media not found")
+)
+
+// MediaFile describes an uploaded file that questions may reference.
+type MediaFile struct {
+	Id          uuid.UUID
+	OwnerId     string
+	Kind        game.MediaKind
+	ContentType string
+	// Size of the file in bytes
+	Size      int64
+	CreatedAt time.Time
+}
+
+type MediaStorer interface {
+	// StoreMedia saves the content of the file, which must be exactly
+	// file.Size bytes long
+	StoreMedia(ctx context.Context, file *MediaFile, content io.Reader) error
+	FindMedia(ctx context.Context, id uuid.UUID) (*MediaFile, error)
+	// OpenMedia returns the content of the file, callers must close it
+	OpenMedia(ctx context.Context, id uuid.UUID) (io.ReadCloser, error)
+	// DeleteMedia removes the file, deleting one that doesn't exist is not an
+	// error
+	DeleteMedia(ctx context.Context, id uuid.UUID) error
+	// FindMediaStoredBefore returns the ids of every file stored before t
+	FindMediaStoredBefore(ctx context.Context, t time.Time) ([]uuid.UUID, error)
+}
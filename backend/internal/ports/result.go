@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/result_aggregate"
+)
+
+var (
+	ErrResultNotFound = errors.New("Result not found")
+)
+
+type ResultStorer interface {
+	StoreResult(ctx context.Context, result *result.SessionResult) error
+	FindResultById(ctx context.Context, id uuid.UUID) (*result.SessionResult, error)
+	FindAllResultsByHostId(ctx context.Context, hostId string) ([]*result.SessionResult, error)
+	FindAllResultsByGameId(ctx context.Context, gameId uuid.UUID) ([]*result.SessionResult, error)
+}
@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/game_aggregate"
+)
+
+var (
+	ErrRevisionNotFound = errors.New("Revision not found")
+)
+
+type RevisionStorer interface {
+	// StoreRevision appends the revision to the history of its game, setting
+	// its number
+	StoreRevision(ctx context.Context, revision *game.Revision) error
+	// FindRevisionsByGameId lists the history of a game, newest first and
+	// without the game of each revision
+	FindRevisionsByGameId(ctx context.Context, gameId uuid.UUID) ([]*game.Revision, error)
+	FindRevision(ctx context.Context, gameId uuid.UUID, number int) (*game.Revision, error)
+}
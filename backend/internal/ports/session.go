@@ -0,0 +1,39 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"github.com/taldoflemis/brain.test/internal/core/domain/session_aggregate"
+)
+
+var (
+	ErrSessionNotFound       = errors.New("Session not found")
+	ErrPinAlreadyInUse       = errors.New("Pin already in use")
+	ErrNotSessionHost        = errors.New("User is not the host of the session")
+	ErrSessionAlreadyStarted = errors.New("Session already started")
+	ErrSessionFinished       = errors.New("Session already finished")
+	ErrNicknameTaken         = errors.New("Nickname already taken")
+	ErrPlayerNotFound        = errors.New("Player not found")
+	ErrQuestionNotRunning    = errors.New("No question is accepting answers")
+	ErrAlreadyAnswered       = errors.New("Question already answered")
+)
+
+type SessionStorer interface {
+	StoreSession(ctx context.Context, session *session.Session) error
+	FindSessionByPin(ctx context.Context, pin string) (*session.Session, error)
+	// UpdateSession runs fn against the stored session while holding exclusive
+	// access to it, persisting the changes only when fn returns nil.
+	UpdateSession(
+		ctx context.Context,
+		pin string,
+		fn func(session *session.Session) error,
+	) (*session.Session, error)
+	DeleteSession(ctx context.Context, pin string) error
+}
+
+// SessionPublisher pushes session events to whoever is connected to the
+// session, e.g. hosts and players over a websocket.
+type SessionPublisher interface {
+	Publish(event session.Event)
+}